@@ -8,7 +8,6 @@ import (
 	"github.com/msaldanha/realChain/errors"
 	"github.com/msaldanha/realChain/keypair"
 	"github.com/davecgh/go-xdr/xdr2"
-	"fmt"
 )
 
 const version = byte(0x00)
@@ -79,14 +78,14 @@ func IsValid(addr string) (bool, error) {
 	return true, nil
 }
 
-func (a *Address) ToBytes() []byte {
+func (a *Address) ToBytes() ([]byte, error) {
 	var result bytes.Buffer
 	encoder := xdr.NewEncoder(&result)
-	count, err := encoder.Encode(a)
+	_, err := encoder.Encode(a)
 	if err != nil {
-		fmt.Printf("Encoded %d, Error: %s", count, err.Error())
+		return nil, err
 	}
-	return result.Bytes()
+	return result.Bytes(), nil
 }
 
 func generateAddressHash(pubKey []byte) (string, error) {
@@ -0,0 +1,4 @@
+// Package admin defines the Admin service, which reports the state of a running node to its operators.
+package admin
+
+//go:generate protoc -I.. admin/admin.proto --go_out=plugins=grpc:../
@@ -0,0 +1,571 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: admin/admin.proto
+
+package admin
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type StatusRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatusRequest) Reset()         { *m = StatusRequest{} }
+func (m *StatusRequest) String() string { return proto.CompactTextString(m) }
+func (*StatusRequest) ProtoMessage()    {}
+func (*StatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_e6c46a25dc6f6629, []int{0}
+}
+func (m *StatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatusRequest.Unmarshal(m, b)
+}
+func (m *StatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatusRequest.Marshal(b, m, deterministic)
+}
+func (dst *StatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatusRequest.Merge(dst, src)
+}
+func (m *StatusRequest) XXX_Size() int {
+	return xxx_messageInfo_StatusRequest.Size(m)
+}
+func (m *StatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatusRequest proto.InternalMessageInfo
+
+type StatusResult struct {
+	Address              string             `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Version              string             `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	ProtocolVersion      uint32             `protobuf:"varint,3,opt,name=protocolVersion,proto3" json:"protocolVersion,omitempty"`
+	ChainId              string             `protobuf:"bytes,4,opt,name=chainId,proto3" json:"chainId,omitempty"`
+	Peers                []*PeerStatus      `protobuf:"bytes,5,rep,name=peers,proto3" json:"peers,omitempty"`
+	Ledger               *LedgerStatus      `protobuf:"bytes,6,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Rounds               []*Round           `protobuf:"bytes,7,rep,name=rounds,proto3" json:"rounds,omitempty"`
+	AntiEntropy          *AntiEntropyStatus `protobuf:"bytes,8,opt,name=antiEntropy,proto3" json:"antiEntropy,omitempty"`
+	Config               map[string]string  `protobuf:"bytes,9,rep,name=config,proto3" json:"config,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *StatusResult) Reset()         { *m = StatusResult{} }
+func (m *StatusResult) String() string { return proto.CompactTextString(m) }
+func (*StatusResult) ProtoMessage()    {}
+func (*StatusResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_e6c46a25dc6f6629, []int{1}
+}
+func (m *StatusResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatusResult.Unmarshal(m, b)
+}
+func (m *StatusResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatusResult.Marshal(b, m, deterministic)
+}
+func (dst *StatusResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatusResult.Merge(dst, src)
+}
+func (m *StatusResult) XXX_Size() int {
+	return xxx_messageInfo_StatusResult.Size(m)
+}
+func (m *StatusResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatusResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatusResult proto.InternalMessageInfo
+
+func (m *StatusResult) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *StatusResult) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *StatusResult) GetProtocolVersion() uint32 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
+}
+
+func (m *StatusResult) GetChainId() string {
+	if m != nil {
+		return m.ChainId
+	}
+	return ""
+}
+
+func (m *StatusResult) GetPeers() []*PeerStatus {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+func (m *StatusResult) GetLedger() *LedgerStatus {
+	if m != nil {
+		return m.Ledger
+	}
+	return nil
+}
+
+func (m *StatusResult) GetRounds() []*Round {
+	if m != nil {
+		return m.Rounds
+	}
+	return nil
+}
+
+func (m *StatusResult) GetAntiEntropy() *AntiEntropyStatus {
+	if m != nil {
+		return m.AntiEntropy
+	}
+	return nil
+}
+
+func (m *StatusResult) GetConfig() map[string]string {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+// Times are unix nanoseconds and durations nanoseconds.
+type PeerStatus struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	PubKey               []byte   `protobuf:"bytes,2,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
+	ProtocolVersion      uint32   `protobuf:"varint,3,opt,name=protocolVersion,proto3" json:"protocolVersion,omitempty"`
+	Healthy              bool     `protobuf:"varint,4,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	Score                int32    `protobuf:"varint,5,opt,name=score,proto3" json:"score,omitempty"`
+	Latency              int64    `protobuf:"varint,6,opt,name=latency,proto3" json:"latency,omitempty"`
+	Failures             int32    `protobuf:"varint,7,opt,name=failures,proto3" json:"failures,omitempty"`
+	BannedUntil          int64    `protobuf:"varint,8,opt,name=bannedUntil,proto3" json:"bannedUntil,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeerStatus) Reset()         { *m = PeerStatus{} }
+func (m *PeerStatus) String() string { return proto.CompactTextString(m) }
+func (*PeerStatus) ProtoMessage()    {}
+func (*PeerStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_e6c46a25dc6f6629, []int{2}
+}
+func (m *PeerStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerStatus.Unmarshal(m, b)
+}
+func (m *PeerStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerStatus.Marshal(b, m, deterministic)
+}
+func (dst *PeerStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerStatus.Merge(dst, src)
+}
+func (m *PeerStatus) XXX_Size() int {
+	return xxx_messageInfo_PeerStatus.Size(m)
+}
+func (m *PeerStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerStatus proto.InternalMessageInfo
+
+func (m *PeerStatus) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *PeerStatus) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *PeerStatus) GetProtocolVersion() uint32 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
+}
+
+func (m *PeerStatus) GetHealthy() bool {
+	if m != nil {
+		return m.Healthy
+	}
+	return false
+}
+
+func (m *PeerStatus) GetScore() int32 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *PeerStatus) GetLatency() int64 {
+	if m != nil {
+		return m.Latency
+	}
+	return 0
+}
+
+func (m *PeerStatus) GetFailures() int32 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *PeerStatus) GetBannedUntil() int64 {
+	if m != nil {
+		return m.BannedUntil
+	}
+	return 0
+}
+
+type LedgerStatus struct {
+	Blocks               int64    `protobuf:"varint,1,opt,name=blocks,proto3" json:"blocks,omitempty"`
+	Accounts             int64    `protobuf:"varint,2,opt,name=accounts,proto3" json:"accounts,omitempty"`
+	PendingSends         int64    `protobuf:"varint,3,opt,name=pendingSends,proto3" json:"pendingSends,omitempty"`
+	LastSeq              uint64   `protobuf:"varint,4,opt,name=lastSeq,proto3" json:"lastSeq,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LedgerStatus) Reset()         { *m = LedgerStatus{} }
+func (m *LedgerStatus) String() string { return proto.CompactTextString(m) }
+func (*LedgerStatus) ProtoMessage()    {}
+func (*LedgerStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_e6c46a25dc6f6629, []int{3}
+}
+func (m *LedgerStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LedgerStatus.Unmarshal(m, b)
+}
+func (m *LedgerStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LedgerStatus.Marshal(b, m, deterministic)
+}
+func (dst *LedgerStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LedgerStatus.Merge(dst, src)
+}
+func (m *LedgerStatus) XXX_Size() int {
+	return xxx_messageInfo_LedgerStatus.Size(m)
+}
+func (m *LedgerStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_LedgerStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LedgerStatus proto.InternalMessageInfo
+
+func (m *LedgerStatus) GetBlocks() int64 {
+	if m != nil {
+		return m.Blocks
+	}
+	return 0
+}
+
+func (m *LedgerStatus) GetAccounts() int64 {
+	if m != nil {
+		return m.Accounts
+	}
+	return 0
+}
+
+func (m *LedgerStatus) GetPendingSends() int64 {
+	if m != nil {
+		return m.PendingSends
+	}
+	return 0
+}
+
+func (m *LedgerStatus) GetLastSeq() uint64 {
+	if m != nil {
+		return m.LastSeq
+	}
+	return 0
+}
+
+type Round struct {
+	SendHash             string   `protobuf:"bytes,1,opt,name=sendHash,proto3" json:"sendHash,omitempty"`
+	ReceiveHash          string   `protobuf:"bytes,2,opt,name=receiveHash,proto3" json:"receiveHash,omitempty"`
+	Started              int64    `protobuf:"varint,3,opt,name=started,proto3" json:"started,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Round) Reset()         { *m = Round{} }
+func (m *Round) String() string { return proto.CompactTextString(m) }
+func (*Round) ProtoMessage()    {}
+func (*Round) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_e6c46a25dc6f6629, []int{4}
+}
+func (m *Round) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Round.Unmarshal(m, b)
+}
+func (m *Round) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Round.Marshal(b, m, deterministic)
+}
+func (dst *Round) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Round.Merge(dst, src)
+}
+func (m *Round) XXX_Size() int {
+	return xxx_messageInfo_Round.Size(m)
+}
+func (m *Round) XXX_DiscardUnknown() {
+	xxx_messageInfo_Round.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Round proto.InternalMessageInfo
+
+func (m *Round) GetSendHash() string {
+	if m != nil {
+		return m.SendHash
+	}
+	return ""
+}
+
+func (m *Round) GetReceiveHash() string {
+	if m != nil {
+		return m.ReceiveHash
+	}
+	return ""
+}
+
+func (m *Round) GetStarted() int64 {
+	if m != nil {
+		return m.Started
+	}
+	return 0
+}
+
+type AntiEntropyStatus struct {
+	Enabled              bool     `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Rounds               int64    `protobuf:"varint,2,opt,name=rounds,proto3" json:"rounds,omitempty"`
+	FailedRounds         int64    `protobuf:"varint,3,opt,name=failedRounds,proto3" json:"failedRounds,omitempty"`
+	LastRound            int64    `protobuf:"varint,4,opt,name=lastRound,proto3" json:"lastRound,omitempty"`
+	LastDiverged         int64    `protobuf:"varint,5,opt,name=lastDiverged,proto3" json:"lastDiverged,omitempty"`
+	TotalStored          int64    `protobuf:"varint,6,opt,name=totalStored,proto3" json:"totalStored,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AntiEntropyStatus) Reset()         { *m = AntiEntropyStatus{} }
+func (m *AntiEntropyStatus) String() string { return proto.CompactTextString(m) }
+func (*AntiEntropyStatus) ProtoMessage()    {}
+func (*AntiEntropyStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_e6c46a25dc6f6629, []int{5}
+}
+func (m *AntiEntropyStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AntiEntropyStatus.Unmarshal(m, b)
+}
+func (m *AntiEntropyStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AntiEntropyStatus.Marshal(b, m, deterministic)
+}
+func (dst *AntiEntropyStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AntiEntropyStatus.Merge(dst, src)
+}
+func (m *AntiEntropyStatus) XXX_Size() int {
+	return xxx_messageInfo_AntiEntropyStatus.Size(m)
+}
+func (m *AntiEntropyStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_AntiEntropyStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AntiEntropyStatus proto.InternalMessageInfo
+
+func (m *AntiEntropyStatus) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *AntiEntropyStatus) GetRounds() int64 {
+	if m != nil {
+		return m.Rounds
+	}
+	return 0
+}
+
+func (m *AntiEntropyStatus) GetFailedRounds() int64 {
+	if m != nil {
+		return m.FailedRounds
+	}
+	return 0
+}
+
+func (m *AntiEntropyStatus) GetLastRound() int64 {
+	if m != nil {
+		return m.LastRound
+	}
+	return 0
+}
+
+func (m *AntiEntropyStatus) GetLastDiverged() int64 {
+	if m != nil {
+		return m.LastDiverged
+	}
+	return 0
+}
+
+func (m *AntiEntropyStatus) GetTotalStored() int64 {
+	if m != nil {
+		return m.TotalStored
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*StatusRequest)(nil), "admin.StatusRequest")
+	proto.RegisterType((*StatusResult)(nil), "admin.StatusResult")
+	proto.RegisterMapType((map[string]string)(nil), "admin.StatusResult.ConfigEntry")
+	proto.RegisterType((*PeerStatus)(nil), "admin.PeerStatus")
+	proto.RegisterType((*LedgerStatus)(nil), "admin.LedgerStatus")
+	proto.RegisterType((*Round)(nil), "admin.Round")
+	proto.RegisterType((*AntiEntropyStatus)(nil), "admin.AntiEntropyStatus")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AdminClient interface {
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResult, error)
+}
+
+type adminClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAdminClient(cc *grpc.ClientConn) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResult, error) {
+	out := new(StatusResult)
+	err := c.cc.Invoke(ctx, "/admin.Admin/Status", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+type AdminServer interface {
+	Status(context.Context, *StatusRequest) (*StatusResult, error)
+}
+
+func RegisterAdminServer(s *grpc.Server, srv AdminServer) {
+	s.RegisterService(&_Admin_serviceDesc, srv)
+}
+
+func _Admin_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.Admin/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Admin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "admin.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Status",
+			Handler:    _Admin_Status_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin/admin.proto",
+}
+
+func init() { proto.RegisterFile("admin/admin.proto", fileDescriptor_admin_e6c46a25dc6f6629) }
+
+var fileDescriptor_admin_e6c46a25dc6f6629 = []byte{
+	// 598 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x5f, 0x6b, 0xd4, 0x40,
+	0x10, 0x37, 0x4d, 0x93, 0x5e, 0xe7, 0xae, 0xd4, 0x6e, 0x8b, 0x84, 0x22, 0x78, 0x04, 0xc1, 0x03,
+	0xa1, 0x42, 0x45, 0xd4, 0x3e, 0x08, 0xf5, 0x0f, 0x28, 0xfa, 0x20, 0x7b, 0xe8, 0x83, 0x2f, 0xb2,
+	0x97, 0x9d, 0xde, 0x85, 0xc6, 0xcd, 0x75, 0x77, 0x73, 0x70, 0x6f, 0x7e, 0x26, 0xbf, 0x87, 0xdf,
+	0xc5, 0x8f, 0x20, 0xb3, 0x99, 0xb4, 0x69, 0x15, 0xc1, 0x97, 0xe3, 0x7e, 0xbf, 0xf9, 0xed, 0xcc,
+	0xfe, 0x66, 0x66, 0x03, 0x7b, 0x4a, 0x7f, 0x2b, 0xcd, 0xa3, 0xf0, 0x7b, 0xb4, 0xb4, 0xb5, 0xaf,
+	0x45, 0x12, 0x40, 0xbe, 0x0b, 0x3b, 0x53, 0xaf, 0x7c, 0xe3, 0x24, 0x5e, 0x34, 0xe8, 0x7c, 0xfe,
+	0x23, 0x86, 0x51, 0xc7, 0xb8, 0xa6, 0xf2, 0x22, 0x83, 0x2d, 0xa5, 0xb5, 0x45, 0xe7, 0xb2, 0x68,
+	0x1c, 0x4d, 0xb6, 0x65, 0x07, 0x29, 0xb2, 0x42, 0xeb, 0xca, 0xda, 0x64, 0x1b, 0x6d, 0x84, 0xa1,
+	0x98, 0xc0, 0x6e, 0xa8, 0x52, 0xd4, 0xd5, 0x67, 0x56, 0xc4, 0xe3, 0x68, 0xb2, 0x23, 0x6f, 0xd2,
+	0x94, 0xa3, 0x58, 0xa8, 0xd2, 0xbc, 0xd3, 0xd9, 0x66, 0x9b, 0x83, 0xa1, 0x78, 0x00, 0xc9, 0x12,
+	0xd1, 0xba, 0x2c, 0x19, 0xc7, 0x93, 0xe1, 0xf1, 0xde, 0x51, 0x7b, 0xfb, 0x8f, 0x88, 0x96, 0xef,
+	0xd7, 0xc6, 0xc5, 0x43, 0x48, 0x2b, 0xd4, 0x73, 0xb4, 0x59, 0x3a, 0x8e, 0x26, 0xc3, 0xe3, 0x7d,
+	0x56, 0x7e, 0x08, 0x24, 0x6b, 0x59, 0x22, 0xee, 0x43, 0x6a, 0xeb, 0xc6, 0x68, 0x97, 0x6d, 0x85,
+	0xb4, 0x23, 0x16, 0x4b, 0x22, 0x25, 0xc7, 0xc4, 0x09, 0x0c, 0x95, 0xf1, 0xe5, 0x1b, 0xe3, 0x6d,
+	0xbd, 0x5c, 0x67, 0x83, 0x90, 0x37, 0x63, 0xe9, 0xe9, 0x55, 0x84, 0x93, 0xf7, 0xc5, 0xe2, 0x29,
+	0xa4, 0x45, 0x6d, 0xce, 0xca, 0x79, 0xb6, 0x1d, 0x2a, 0xdc, 0xe3, 0x63, 0xfd, 0xa6, 0x1e, 0xbd,
+	0x0a, 0x0a, 0x3a, 0xb2, 0x96, 0x2c, 0x3f, 0x7c, 0x0e, 0xc3, 0x1e, 0x2d, 0x6e, 0x43, 0x7c, 0x8e,
+	0x6b, 0xee, 0x39, 0xfd, 0x15, 0x07, 0x90, 0xac, 0x54, 0xd5, 0x20, 0x77, 0xbb, 0x05, 0x27, 0x1b,
+	0xcf, 0xa2, 0xfc, 0x57, 0x04, 0x70, 0xd5, 0x98, 0x7f, 0x8c, 0xec, 0x0e, 0xa4, 0xcb, 0x66, 0xf6,
+	0x1e, 0xd7, 0x21, 0xc7, 0x48, 0x32, 0xfa, 0xbf, 0x81, 0x2d, 0x50, 0x55, 0x7e, 0xb1, 0x0e, 0x03,
+	0x1b, 0xc8, 0x0e, 0xd2, 0xf5, 0x5c, 0x51, 0x5b, 0xcc, 0x92, 0x71, 0x34, 0x49, 0x64, 0x0b, 0x48,
+	0x5f, 0x29, 0x8f, 0xa6, 0x58, 0x87, 0xf1, 0xc4, 0xb2, 0x83, 0xe2, 0x10, 0x06, 0x67, 0xaa, 0xac,
+	0x1a, 0x8b, 0x34, 0x0c, 0x3a, 0x72, 0x89, 0xc5, 0x18, 0x86, 0x33, 0x65, 0x0c, 0xea, 0x4f, 0xc6,
+	0x97, 0x55, 0x18, 0x40, 0x2c, 0xfb, 0x54, 0xfe, 0x3d, 0x82, 0x51, 0x7f, 0xc2, 0x64, 0x6d, 0x56,
+	0xd5, 0xc5, 0x79, 0xeb, 0x39, 0x96, 0x8c, 0xa8, 0x8c, 0x2a, 0x8a, 0xba, 0x31, 0xde, 0x05, 0xd3,
+	0xb1, 0xbc, 0xc4, 0x22, 0x87, 0xd1, 0x12, 0x8d, 0x2e, 0xcd, 0x7c, 0x8a, 0xb4, 0x13, 0x71, 0x88,
+	0x5f, 0xe3, 0x5a, 0x03, 0xce, 0x4f, 0xf1, 0x22, 0x18, 0xde, 0x94, 0x1d, 0xcc, 0xbf, 0x42, 0x12,
+	0xd6, 0x86, 0x4a, 0x38, 0x34, 0xfa, 0xad, 0x72, 0x0b, 0x6e, 0xf8, 0x25, 0x26, 0x27, 0x16, 0x0b,
+	0x2c, 0x57, 0x18, 0xc2, 0xed, 0xe8, 0xfa, 0x14, 0x15, 0x70, 0x5e, 0x59, 0x8f, 0x9a, 0xeb, 0x77,
+	0x30, 0xff, 0x19, 0xc1, 0xde, 0x1f, 0xdb, 0x46, 0x7a, 0x34, 0x6a, 0x56, 0xa1, 0x0e, 0xc5, 0x06,
+	0xb2, 0x83, 0xd4, 0x02, 0x5e, 0xee, 0xd6, 0x28, 0x23, 0xb2, 0x49, 0x9d, 0x45, 0x2d, 0xdb, 0x28,
+	0xdb, 0xec, 0x73, 0xe2, 0x2e, 0x6c, 0x93, 0xaf, 0x80, 0x82, 0xd1, 0x58, 0x5e, 0x11, 0x94, 0x81,
+	0xc0, 0xeb, 0x72, 0x85, 0x76, 0x8e, 0x3a, 0x8c, 0x38, 0x96, 0xd7, 0x38, 0x72, 0xea, 0x6b, 0xaf,
+	0xaa, 0xa9, 0xaf, 0x2d, 0x6a, 0x9e, 0x76, 0x9f, 0x3a, 0x7e, 0x01, 0xc9, 0x29, 0xbd, 0x05, 0xf1,
+	0x04, 0x52, 0x36, 0x73, 0x70, 0xe3, 0x75, 0x84, 0x8f, 0xd0, 0xe1, 0xfe, 0x5f, 0xde, 0x4c, 0x7e,
+	0xeb, 0xe5, 0xd6, 0x97, 0xf6, 0xab, 0x35, 0x4b, 0xc3, 0x56, 0x3e, 0xfe, 0x3d, 0x00, 0x47, 0x38,
+	0x64, 0xef, 0xd8, 0x04, 0x00, 0x00,
+}
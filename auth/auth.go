@@ -0,0 +1,318 @@
+// Package auth authenticates the clients of the node with API tokens and authorizes their calls by role, so the
+// ledger service is not open to anyone who can reach the node.
+package auth
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	ErrMissingToken     = errors.Error("missing API token")
+	ErrInvalidToken     = errors.Error("invalid API token")
+	ErrPermissionDenied = errors.Error("API token not allowed to call the method")
+	ErrInvalidRole      = errors.Error("invalid role")
+	ErrInvalidTokenFile = errors.Error("invalid token file line, expected <role> <token>")
+	ErrNoTokens         = errors.Error("authentication enabled without tokens")
+)
+
+// AuthorizationKey is the gRPC metadata key, and HTTP header, of the API token, sent as "Bearer <token>".
+const AuthorizationKey = "authorization"
+
+const bearerPrefix = "bearer "
+
+// Role is what the holder of a token may call. Every role may call the methods of the roles below it.
+type Role int
+
+const (
+	// RolePublic is the role of the methods anyone may call, without a token.
+	RolePublic Role = iota
+	RoleReader
+	RoleSubmitter
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{
+	RolePublic:    "public",
+	RoleReader:    "reader",
+	RoleSubmitter: "submitter",
+	RoleAdmin:     "admin",
+}
+
+func (r Role) String() string {
+	return roleNames[r]
+}
+
+// ParseRole returns the role named name.
+func ParseRole(name string) (Role, error) {
+	for role, roleName := range roleNames {
+		if strings.EqualFold(name, roleName) {
+			return role, nil
+		}
+	}
+	return RolePublic, ErrInvalidRole
+}
+
+// DefaultPolicy is the role required by each method, by method name in lower case. The consensus methods are
+// called by the peers, which do not hold tokens and authenticate with handshakes, and the health checks by the
+// probes. The methods not listed require the admin role.
+var DefaultPolicy = map[string]Role{
+	"getlasttransaction":     RoleReader,
+	"gettransaction":         RoleReader,
+	"verifytransaction":      RoleReader,
+	"getaddressstatement":    RoleReader,
+	"streamaddressstatement": RoleReader,
+	"getbalanceat":           RoleReader,
+	"getbalancehistory":      RoleReader,
+	"getincoming":            RoleReader,
+	"getreceiveforsend":      RoleReader,
+	"getvotes":               RoleReader,
+	"subscribe":              RoleReader,
+	"register":               RoleSubmitter,
+	"verify":                 RoleSubmitter,
+	"status":                 RoleAdmin,
+	"vote":                   RolePublic,
+	"accept":                 RolePublic,
+	"publish":                RolePublic,
+	"getfrontiers":           RolePublic,
+	"getchain":               RolePublic,
+	"getpeers":               RolePublic,
+	"ping":                   RolePublic,
+	"handshake":              RolePublic,
+	"check":                  RolePublic,
+	"watch":                  RolePublic,
+}
+
+// Identity is the holder of a token. Tokens have no names, so the identity is named after the role and the
+// fingerprint of the token, which can be logged without disclosing it.
+type Identity struct {
+	Name string
+	Role Role
+}
+
+type identityKey struct{}
+
+// Authorizer checks the tokens of the calls against the role required by the called method.
+type Authorizer struct {
+	tokens map[[sha256.Size]byte]*Identity
+	policy map[string]Role
+}
+
+// New creates the authorizer of the configured tokens, or returns nil if authentication is disabled. The tokens
+// of each role are listed in auth.tokens.<role> and in the token file set in auth.tokenfile, one "<role> <token>"
+// per line. auth.methods.<method> overrides the role required by a method of the default policy.
+func New(cfg *viper.Viper) (*Authorizer, error) {
+	if !cfg.GetBool(config.CfgAuth) {
+		return nil, nil
+	}
+
+	a := &Authorizer{tokens: make(map[[sha256.Size]byte]*Identity), policy: make(map[string]Role)}
+	for method, role := range DefaultPolicy {
+		a.policy[method] = role
+	}
+	for method, name := range cfg.GetStringMapString(config.CfgAuthMethods) {
+		role, err := ParseRole(name)
+		if err != nil {
+			return nil, err
+		}
+		a.policy[strings.ToLower(method)] = role
+	}
+
+	for name := range cfg.GetStringMap(config.CfgAuthTokens) {
+		role, err := ParseRole(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, token := range cfg.GetStringSlice(config.CfgAuthTokens + "." + name) {
+			a.add(token, role)
+		}
+	}
+	if file := cfg.GetString(config.CfgAuthTokenFile); file != "" {
+		err := a.load(dataPath(cfg, file))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(a.tokens) == 0 {
+		return nil, ErrNoTokens
+	}
+	return a, nil
+}
+
+// Authorize returns the identity holding token if it may call the method, given by name or by full gRPC
+// method name. The public methods need no token and have no identity.
+func (a *Authorizer) Authorize(token, method string) (*Identity, error) {
+	required, ok := a.policy[strings.ToLower(path.Base(method))]
+	if !ok {
+		required = RoleAdmin
+	}
+	if required == RolePublic {
+		return nil, nil
+	}
+
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+	identity, ok := a.tokens[sha256.Sum256([]byte(token))]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	if identity.Role < required {
+		return nil, ErrPermissionDenied
+	}
+	return identity, nil
+}
+
+// UnaryServerInterceptor refuses the unary calls whose token may not call the method and adds the identity of
+// the caller to the context of the others.
+func (a *Authorizer) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	identity, err := a.Authorize(tokenFromContext(ctx), info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(WithIdentity(ctx, identity), req)
+}
+
+// StreamServerInterceptor refuses the streaming calls whose token may not call the method and adds the identity
+// of the caller to the context of the others.
+func (a *Authorizer) StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	identity, err := a.Authorize(tokenFromContext(ss.Context()), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &identityStream{ServerStream: ss, ctx: WithIdentity(ss.Context(), identity)})
+}
+
+// WithIdentity returns a copy of ctx carrying the identity of the caller.
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	if identity == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the identity of the caller carried by ctx, or nil if the call is not authenticated.
+func FromContext(ctx context.Context) *Identity {
+	if ctx == nil {
+		return nil
+	}
+	identity, _ := ctx.Value(identityKey{}).(*Identity)
+	return identity
+}
+
+// Token returns the token of an authorization value, "Bearer <token>", or an empty string if there is none.
+func Token(authorization string) string {
+	if len(authorization) < len(bearerPrefix) || !strings.EqualFold(authorization[:len(bearerPrefix)], bearerPrefix) {
+		return ""
+	}
+	return strings.TrimSpace(authorization[len(bearerPrefix):])
+}
+
+// NewToken returns a new random token.
+func NewToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// IsSecret tells whether the configuration property key holds tokens, so it is not disclosed.
+func IsSecret(key string) bool {
+	key = strings.ToLower(key)
+	return key == config.CfgAuthToken || strings.HasPrefix(key, config.CfgAuthTokens+".")
+}
+
+// DialOptions returns the gRPC dial options that send the configured auth.token with every call, if set.
+func DialOptions(cfg *viper.Viper) []grpc.DialOption {
+	token := cfg.GetString(config.CfgAuthToken)
+	if token == "" {
+		return nil
+	}
+	return []grpc.DialOption{grpc.WithPerRPCCredentials(tokenCredentials(token))}
+}
+
+func (a *Authorizer) add(token string, role Role) {
+	sum := sha256.Sum256([]byte(token))
+	a.tokens[sum] = &Identity{Name: role.String() + ":" + hex.EncodeToString(sum[:4]), Role: role}
+}
+
+func (a *Authorizer) load(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return ErrInvalidTokenFile
+		}
+		role, err := ParseRole(fields[0])
+		if err != nil {
+			return err
+		}
+		a.add(fields[1], role)
+	}
+	return scanner.Err()
+}
+
+func tokenFromContext(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(AuthorizationKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return Token(values[0])
+}
+
+// dataPath returns file relative to the data folder.
+func dataPath(cfg *viper.Viper, file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(cfg.GetString(config.CfgDataFolder), file)
+}
+
+type identityStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identityStream) Context() context.Context {
+	return s.ctx
+}
+
+// tokenCredentials sends the token with every call. The token is sent over plain connections too, so nodes
+// reachable from untrusted networks should be served with TLS.
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{AuthorizationKey: "Bearer " + string(t)}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
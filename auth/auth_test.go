@@ -0,0 +1,159 @@
+package auth_test
+
+import (
+	"github.com/msaldanha/realChain/auth"
+	"github.com/msaldanha/realChain/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("Authorizer", func() {
+
+	var cfg *viper.Viper
+
+	BeforeEach(func() {
+		cfg = viper.New()
+		cfg.Set(config.CfgAuth, true)
+		cfg.Set(config.CfgAuthTokens+".reader", []string{"reader-token"})
+		cfg.Set(config.CfgAuthTokens+".submitter", []string{"submitter-token"})
+		cfg.Set(config.CfgAuthTokens+".admin", []string{"admin-token"})
+	})
+
+	It("Should not be created with authentication disabled", func() {
+		cfg.Set(config.CfgAuth, false)
+		authorizer, err := auth.New(cfg)
+		Expect(err).To(BeNil())
+		Expect(authorizer).To(BeNil())
+	})
+
+	It("Should not be created without tokens or with invalid roles", func() {
+		_, err := auth.New(viper.New())
+		Expect(err).To(BeNil())
+
+		empty := viper.New()
+		empty.Set(config.CfgAuth, true)
+		_, err = auth.New(empty)
+		Expect(err).To(Equal(auth.ErrNoTokens))
+
+		cfg.Set(config.CfgAuthTokens+".root", []string{"root-token"})
+		_, err = auth.New(cfg)
+		Expect(err).To(Equal(auth.ErrInvalidRole))
+	})
+
+	It("Should authorize the calls by role", func() {
+		authorizer, err := auth.New(cfg)
+		Expect(err).To(BeNil())
+
+		identity, err := authorizer.Authorize("reader-token", "/ledger.Ledger/GetTransaction")
+		Expect(err).To(BeNil())
+		Expect(identity.Role).To(Equal(auth.RoleReader))
+		Expect(identity.Name).To(HavePrefix("reader:"))
+		Expect(identity.Name).NotTo(ContainSubstring("reader-token"))
+
+		_, err = authorizer.Authorize("reader-token", "/ledger.Ledger/Register")
+		Expect(err).To(Equal(auth.ErrPermissionDenied))
+		_, err = authorizer.Authorize("submitter-token", "/ledger.Ledger/Register")
+		Expect(err).To(BeNil())
+		_, err = authorizer.Authorize("submitter-token", "/admin.Admin/Status")
+		Expect(err).To(Equal(auth.ErrPermissionDenied))
+		_, err = authorizer.Authorize("admin-token", "/admin.Admin/Status")
+		Expect(err).To(BeNil())
+		_, err = authorizer.Authorize("admin-token", "/ledger.Ledger/Register")
+		Expect(err).To(BeNil())
+	})
+
+	It("Should refuse missing and unknown tokens", func() {
+		authorizer, err := auth.New(cfg)
+		Expect(err).To(BeNil())
+
+		_, err = authorizer.Authorize("", "GetTransaction")
+		Expect(err).To(Equal(auth.ErrMissingToken))
+		_, err = authorizer.Authorize("unknown-token", "GetTransaction")
+		Expect(err).To(Equal(auth.ErrInvalidToken))
+	})
+
+	It("Should let anyone call the public methods and require admin for the unknown ones", func() {
+		authorizer, err := auth.New(cfg)
+		Expect(err).To(BeNil())
+
+		identity, err := authorizer.Authorize("", "/Consensus/Vote")
+		Expect(err).To(BeNil())
+		Expect(identity).To(BeNil())
+		_, err = authorizer.Authorize("", "/grpc.health.v1.Health/Check")
+		Expect(err).To(BeNil())
+
+		_, err = authorizer.Authorize("submitter-token", "/other.Service/Method")
+		Expect(err).To(Equal(auth.ErrPermissionDenied))
+		_, err = authorizer.Authorize("admin-token", "/other.Service/Method")
+		Expect(err).To(BeNil())
+	})
+
+	It("Should override the role of the methods", func() {
+		cfg.Set(config.CfgAuthMethods+".gettransaction", "public")
+		cfg.Set(config.CfgAuthMethods+".register", "admin")
+		authorizer, err := auth.New(cfg)
+		Expect(err).To(BeNil())
+
+		_, err = authorizer.Authorize("", "GetTransaction")
+		Expect(err).To(BeNil())
+		_, err = authorizer.Authorize("submitter-token", "Register")
+		Expect(err).To(Equal(auth.ErrPermissionDenied))
+	})
+
+	It("Should load the tokens of the token file", func() {
+		dir, err := ioutil.TempDir("", "auth")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(dir)
+		err = ioutil.WriteFile(filepath.Join(dir, "tokens"), []byte("# tokens\n\nsubmitter file-token\n"), 0600)
+		Expect(err).To(BeNil())
+
+		cfg.Set(config.CfgDataFolder, dir)
+		cfg.Set(config.CfgAuthTokenFile, "tokens")
+		authorizer, err := auth.New(cfg)
+		Expect(err).To(BeNil())
+		identity, err := authorizer.Authorize("file-token", "Register")
+		Expect(err).To(BeNil())
+		Expect(identity.Role).To(Equal(auth.RoleSubmitter))
+
+		err = ioutil.WriteFile(filepath.Join(dir, "tokens"), []byte("file-token\n"), 0600)
+		Expect(err).To(BeNil())
+		_, err = auth.New(cfg)
+		Expect(err).To(Equal(auth.ErrInvalidTokenFile))
+	})
+
+	It("Should authorize the calls with the token in the metadata", func() {
+		authorizer, err := auth.New(cfg)
+		Expect(err).To(BeNil())
+		info := &grpc.UnaryServerInfo{FullMethod: "/ledger.Ledger/GetTransaction"}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return auth.FromContext(ctx), nil
+		}
+
+		_, err = authorizer.UnaryServerInterceptor(context.Background(), nil, info, handler)
+		Expect(err).To(Equal(auth.ErrMissingToken))
+
+		ctx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(auth.AuthorizationKey, "Bearer reader-token"))
+		identity, err := authorizer.UnaryServerInterceptor(ctx, nil, info, handler)
+		Expect(err).To(BeNil())
+		Expect(identity.(*auth.Identity).Role).To(Equal(auth.RoleReader))
+	})
+
+	It("Should parse bearer tokens and tell the secret properties", func() {
+		Expect(auth.Token("Bearer some-token")).To(Equal("some-token"))
+		Expect(auth.Token("bearer some-token")).To(Equal("some-token"))
+		Expect(auth.Token("Basic some-token")).To(Equal(""))
+		Expect(auth.Token("")).To(Equal(""))
+
+		Expect(auth.IsSecret(config.CfgAuthToken)).To(BeTrue())
+		Expect(auth.IsSecret(config.CfgAuthTokens + ".reader")).To(BeTrue())
+		Expect(auth.IsSecret(config.CfgAuthTokenFile)).To(BeFalse())
+	})
+})
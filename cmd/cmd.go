@@ -4,8 +4,15 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"log"
+	"github.com/msaldanha/realChain/auth"
 	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/errcodes"
+	"github.com/msaldanha/realChain/node"
+	"github.com/msaldanha/realChain/security"
+	"google.golang.org/grpc"
+	"net"
+	"os"
+	"time"
 )
 
 var rootCmd *cobra.Command
@@ -22,22 +29,56 @@ func init() {
 
 	cfg.SetDefault(config.CfgDataFolder, "./")
 	cfg.SetDefault(config.CfgLedgerChainFile, "chain.db")
+	cfg.SetDefault(config.CfgLedgerEventsFile, "events.db")
+	cfg.SetDefault(config.CfgLedgerIndexFile, "index.db")
 	cfg.SetDefault(config.CfgNodeAddressesFile, "addresses.db")
+	cfg.SetDefault(config.CfgNodeSyncStateFile, "syncstate.db")
+	cfg.SetDefault(config.CfgNodeVotesFile, "votes.db")
+	cfg.SetDefault(config.CfgNodeAntiEntropy, "30s")
+	cfg.SetDefault(config.CfgNodeShutdownTimeout, "30s")
+	cfg.SetDefault(config.CfgNodeQuorum, 1)
+	cfg.SetDefault(config.CfgNodeSocketMode, "0600")
 	cfg.SetDefault(config.CfgWalletChainFile, "wchain.db")
 	cfg.SetDefault(config.CfgWalletAddressesFile, "waddresses.db")
 	cfg.SetDefault(config.CfgNodeServer, "localhost:1300")
-	cfg.SetDefault(config.CfgUdpServer, "localhost:1200")
+	cfg.SetDefault(config.CfgAdminServer, "localhost:1301")
+	cfg.SetDefault(config.CfgUdpServer, "0.0.0.0:1200")
+	cfg.SetDefault(config.CfgChainId, "realchain")
+	cfg.SetDefault(config.CfgDiscovery, config.DiscoveryStatic)
+	cfg.SetDefault(config.CfgDiscoveryTableFile, "peers.db")
+	cfg.SetDefault(config.CfgDiscoveryMaxPeers, 8)
+	cfg.SetDefault(config.CfgDiscoveryInterval, "1m")
+	cfg.SetDefault(config.CfgDiscoveryExpiry, "24h")
+	cfg.SetDefault(config.CfgDiscoveryPing, "10s")
+	cfg.SetDefault(config.CfgDiscoveryBanTime, "10m")
+	cfg.SetDefault(config.CfgTlsCert, "node.pem")
+	cfg.SetDefault(config.CfgTlsKey, "node-key.pem")
+	cfg.SetDefault(config.CfgTlsCA, "ca.pem")
+	cfg.SetDefault(config.CfgTlsCAKey, "ca-key.pem")
+	cfg.SetDefault(config.CfgLogLevel, "info")
+	cfg.SetDefault(config.CfgLogFormat, config.LogFormatText)
+	cfg.SetDefault(config.CfgLimitRate, 100)
+	cfg.SetDefault(config.CfgLimitBurst, 200)
+	cfg.SetDefault(config.CfgLimitConcurrency+".register", 64)
+	cfg.SetDefault(config.CfgLimitConcurrency+".getaddressstatement", 16)
+	cfg.SetDefault(config.CfgLimitConcurrency+".streamaddressstatement", 4)
+	cfg.SetDefault(config.CfgLimitMaxRecvSize, 4<<20)
 
 	err := cfg.ReadInConfig()
 	if err != nil {
-		log.Printf("Error loading config file: %s . Using defaults.\n", err)
+		fmt.Fprintf(os.Stderr, "Error loading config file: %s . Using defaults.\n", err)
 	}
 
 	rootCmd = &cobra.Command{Use: "realChain"}
 	rootCmd.AddCommand(versionCmd)
 
+	nodeServerCmd.Flags().Bool("bootstrap", false, "Sync the ledger from the peers if it is not initialized")
+	cfg.BindPFlag(config.CfgNodeBootstrap, nodeServerCmd.Flags().Lookup("bootstrap"))
 	nodeCmd.AddCommand(nodeServerCmd)
 	nodeCmd.AddCommand(nodeInitCmd)
+	nodeCmd.AddCommand(nodeGenCertsCmd)
+	nodeCmd.AddCommand(nodeGenTokenCmd)
+	nodeCmd.AddCommand(nodeStatusCmd)
 	rootCmd.AddCommand(nodeCmd)
 
 	ledgerCmd.AddCommand(ledgerInitCmd)
@@ -45,10 +86,34 @@ func init() {
 
 
 	walletCmd.AddCommand(walletListAddrsCmd)
+	walletListAddressStatementCmd.Flags().String("start", "", "Start at the transaction with this hash")
+	walletListAddressStatementCmd.Flags().Int64("height", 0, "Start at the transaction at this height, the open one being 1")
+	walletListAddressStatementCmd.Flags().Uint32("limit", 0, "List a single page of up to this many transactions")
+	walletListAddressStatementCmd.Flags().Bool("reverse", false, "List from the head towards the open transaction")
+	walletListAddressStatementCmd.Flags().String("type", "", "Only transactions of this type (open, send, receive or change)")
+	walletListAddressStatementCmd.Flags().String("from", "", "Only transactions at or after this time (RFC 3339 or date)")
+	walletListAddressStatementCmd.Flags().String("to", "", "Only transactions before this time (RFC 3339 or date)")
 	walletCmd.AddCommand(walletListAddressStatementCmd)
+	walletBalanceCmd.Flags().String("at", "", "Balance before this time (RFC 3339 or date)")
+	walletBalanceCmd.Flags().Int64("height", 0, "Balance after the transaction at this height, the open one being 1")
+	walletCmd.AddCommand(walletBalanceCmd)
+	walletHistoryCmd.Flags().String("from", "", "Start of the history (RFC 3339 or date)")
+	walletHistoryCmd.Flags().String("to", "", "End of the history (RFC 3339 or date), now by default")
+	walletHistoryCmd.Flags().Duration("bucket", 24*time.Hour, "Time between the balances")
+	walletCmd.AddCommand(walletHistoryCmd)
+	walletIncomingCmd.Flags().Bool("pending", false, "Only the send transactions not received yet")
+	walletCmd.AddCommand(walletIncomingCmd)
 	walletCmd.AddCommand(walletSendCmd)
 	walletCmd.AddCommand(walletCreateAddressCmd)
+	walletWatchCmd.Flags().String("type", "", "Only transactions of this type (open, send, receive or change)")
+	walletWatchCmd.Flags().String("state", "", "Only pending or confirmed transactions")
+	walletWatchCmd.Flags().Uint64("cursor", 0, "Replay the confirmed transactions after this cursor first")
+	walletCmd.AddCommand(walletWatchCmd)
 	rootCmd.AddCommand(walletCmd)
+
+	txCmd.AddCommand(txShowCmd)
+	txCmd.AddCommand(txTraceCmd)
+	rootCmd.AddCommand(txCmd)
 }
 
 var versionCmd = &cobra.Command{
@@ -56,10 +121,31 @@ var versionCmd = &cobra.Command{
 	Short: "Print the version number of realChain",
 	Long:  `Print the version number of realChain`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("realChain v0.1 -- HEAD")
+		fmt.Printf("realChain %s -- HEAD\n", config.Version)
 	},
 }
 
 func New() *cobra.Command {
 	return rootCmd
+}
+
+// dialNode connects to the node through its Unix domain socket, if node.socket is set, or else to addr with the
+// TLS configuration. The configured API token is sent with every call.
+func dialNode(addr string) (*grpc.ClientConn, error) {
+	opts := append([]grpc.DialOption{}, errcodes.DialOptions()...)
+	opts = append(opts, auth.DialOptions(cfg)...)
+
+	if socket := node.SocketPath(cfg); socket != "" {
+		opts = append(opts, grpc.WithInsecure(),
+			grpc.WithDialer(func(addr string, timeout time.Duration) (net.Conn, error) {
+				return net.DialTimeout("unix", addr, timeout)
+			}))
+		return grpc.Dial(socket, opts...)
+	}
+
+	dialOpt, err := security.DialOption(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return grpc.Dial(addr, append(opts, dialOpt)...)
 }
\ No newline at end of file
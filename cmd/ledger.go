@@ -6,7 +6,6 @@ import (
 	"github.com/msaldanha/realChain/keyvaluestore"
 	"github.com/msaldanha/realChain/ledger"
 	"github.com/spf13/cobra"
-	"log"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -48,7 +47,30 @@ var ledgerInitCmd = &cobra.Command{
 		txStore := keyvaluestore.NewBoltKeyValueStore()
 		err = txStore.Init(bklStoreOptions)
 		if err != nil {
-			log.Fatal("Failed to init ledger chain: " + err.Error())
+			fmt.Printf("Failed to init ledger chain: %s\n", err)
+			os.Exit(1)
+		}
+
+		eventsOptions := &keyvaluestore.BoltKeyValueStoreOptions{
+			DbFile: filepath.Join(cfg.GetString(config.CfgDataFolder), cfg.GetString(config.CfgLedgerEventsFile)),
+			BucketName: config.EventsBucket,
+		}
+		eventStore := keyvaluestore.NewBoltKeyValueStore()
+		err = eventStore.Init(eventsOptions)
+		if err != nil {
+			fmt.Printf("Failed to init ledger events: %s\n", err)
+			os.Exit(1)
+		}
+
+		indexOptions := &keyvaluestore.BoltKeyValueStoreOptions{
+			DbFile: filepath.Join(cfg.GetString(config.CfgDataFolder), cfg.GetString(config.CfgLedgerIndexFile)),
+			BucketName: config.IndexBucket,
+		}
+		indexStore := keyvaluestore.NewBoltKeyValueStore()
+		err = indexStore.Init(indexOptions)
+		if err != nil {
+			fmt.Printf("Failed to init ledger index: %s\n", err)
+			os.Exit(1)
 		}
 
 		asOpts := &keyvaluestore.BoltKeyValueStoreOptions{DbFile: filepath.Join(cfg.GetString(config.CfgDataFolder),
@@ -63,8 +85,8 @@ var ledgerInitCmd = &cobra.Command{
 		}
 
 		val := ledger.NewValidatorCreator()
-		ts := ledger.NewTransactionStore(txStore, val)
-		ld := ledger.NewLocalLedger(ts)
+		ts := ledger.NewTransactionStoreWithIndex(txStore, indexStore, val)
+		ld := ledger.NewLocalLedgerWithEvents(ts, ledger.NewEventBus(eventStore))
 		if !ts.IsEmpty() {
 			fmt.Println("Ledger already initialized")
 			os.Exit(1)
@@ -80,7 +102,10 @@ var ledgerInitCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		err = as.Put(addr.Address, addr.ToBytes())
+		addrBytes, err := addr.ToBytes()
+		if err == nil {
+			err = as.Put(addr.Address, addrBytes)
+		}
 		if  err != nil {
 			fmt.Printf("Failed to save genesis address: %s\n", err)
 			os.Exit(1)
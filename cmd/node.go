@@ -1,8 +1,19 @@
 package cmd
 
 import (
+	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/msaldanha/realChain/admin"
+	"github.com/msaldanha/realChain/auth"
+	"github.com/msaldanha/realChain/config"
 	"github.com/msaldanha/realChain/node"
+	"github.com/msaldanha/realChain/security"
+	"golang.org/x/net/context"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
 )
 
 var nodeCmd = &cobra.Command{
@@ -17,7 +28,12 @@ var nodeServerCmd = &cobra.Command{
 	Long:  `Start serving. Starts rest and udp servers`,
 	Run: func(cmd *cobra.Command, args []string) {
 		node := node.New(cfg)
-		node.Run()
+		err := node.Run(context.Background())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Done.")
 	},
 }
 
@@ -27,6 +43,142 @@ var nodeInitCmd = &cobra.Command{
 	Long:  `Init node configuration creating node address keys.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		node := node.New(cfg)
-		node.Init()
+		err := node.Init()
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
 	},
 }
+
+var nodeGenCertsCmd = &cobra.Command{
+	Use:   "gen-certs [host...]",
+	Short: "Creates a dev CA and a node certificate for [host...]",
+	Long: `Creates a node certificate for [host...] (localhost and 127.0.0.1 by default), signed by a self-signed dev CA.
+The CA is created if the configured CA files do not exist, otherwise it is reused to sign the node certificate.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		hosts := args
+		if len(hosts) == 0 {
+			hosts = []string{"localhost", "127.0.0.1"}
+		}
+
+		fingerprint, err := security.GenerateCerts(cfg, hosts)
+		if err != nil {
+			fmt.Printf("Failed to create certificates: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Done. Node certificate fingerprint: %s\n", fingerprint)
+	},
+}
+
+var nodeGenTokenCmd = &cobra.Command{
+	Use:   "gen-token [role]",
+	Short: "Creates an API token for [role]",
+	Long: `Creates a random API token for [role] (reader, submitter or admin) and prints it as a line of the token file,
+"<role> <token>". The token is accepted by the node once added to its token file or to auth.tokens.<role>.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Println("Expected [role]")
+			os.Exit(1)
+		}
+		role, err := auth.ParseRole(args[0])
+		if err != nil || role == auth.RolePublic {
+			fmt.Printf("Invalid role: %s\n", args[0])
+			os.Exit(1)
+		}
+
+		token, err := auth.NewToken()
+		if err != nil {
+			fmt.Printf("Failed to create token: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s %s\n", role, token)
+	},
+}
+
+var nodeStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows the status of the running node",
+	Long: `Shows the status of the node serving at the configured node socket, admin server or node server: its identity,
+peers, ledger counters, votings in progress, anti-entropy rounds and effective configuration.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		addr := cfg.GetString(config.CfgAdminServer)
+		if addr == "" {
+			addr = cfg.GetString(config.CfgNodeServer)
+		}
+		conn, err := dialNode(addr)
+		if err != nil {
+			fmt.Printf("Connection to node failed: %s\n", err)
+			os.Exit(1)
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		status, err := admin.NewAdminClient(conn).Status(ctx, &admin.StatusRequest{})
+		if err != nil {
+			fmt.Printf("Node status failed: %s\n", err)
+			os.Exit(1)
+		}
+		printStatus(os.Stdout, status)
+	},
+}
+
+func printStatus(out io.Writer, status *admin.StatusResult) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Address:\t%s\n", status.Address)
+	fmt.Fprintf(w, "Version:\t%s (protocol %d)\n", status.Version, status.ProtocolVersion)
+	fmt.Fprintf(w, "Chain id:\t%s\n", status.ChainId)
+
+	fmt.Fprintln(w, "\nLedger")
+	if status.Ledger != nil {
+		fmt.Fprintf(w, "  Blocks:\t%d\n", status.Ledger.Blocks)
+		fmt.Fprintf(w, "  Accounts:\t%d\n", status.Ledger.Accounts)
+		fmt.Fprintf(w, "  Pending sends:\t%d\n", status.Ledger.PendingSends)
+		fmt.Fprintf(w, "  Last cursor:\t%d\n", status.Ledger.LastSeq)
+	}
+
+	fmt.Fprintf(w, "\nPeers (%d)\n", len(status.Peers))
+	if len(status.Peers) > 0 {
+		fmt.Fprintln(w, "  ADDRESS\tHEALTHY\tSCORE\tLATENCY\tFAILURES\tBANNED UNTIL")
+	}
+	for _, peer := range status.Peers {
+		fmt.Fprintf(w, "  %s\t%t\t%d\t%s\t%d\t%s\n", peer.Address, peer.Healthy, peer.Score,
+			time.Duration(peer.Latency), peer.Failures, formatTime(peer.BannedUntil))
+	}
+
+	fmt.Fprintf(w, "\nVotings in progress (%d)\n", len(status.Rounds))
+	if len(status.Rounds) > 0 {
+		fmt.Fprintln(w, "  SEND\tRECEIVE\tSTARTED")
+	}
+	for _, round := range status.Rounds {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", round.SendHash, round.ReceiveHash, formatTime(round.Started))
+	}
+
+	fmt.Fprintln(w, "\nAnti-entropy")
+	if ae := status.AntiEntropy; ae != nil && ae.Enabled {
+		fmt.Fprintf(w, "  Rounds:\t%d (%d failed)\n", ae.Rounds, ae.FailedRounds)
+		fmt.Fprintf(w, "  Last round:\t%s (%d diverged chains)\n", formatTime(ae.LastRound), ae.LastDiverged)
+		fmt.Fprintf(w, "  Stored:\t%d\n", ae.TotalStored)
+	} else {
+		fmt.Fprintln(w, "  Disabled")
+	}
+
+	fmt.Fprintln(w, "\nConfiguration")
+	keys := make([]string, 0, len(status.Config))
+	for key := range status.Config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "  %s\t%s\n", key, status.Config[key])
+	}
+	w.Flush()
+}
+
+func formatTime(unixNano int64) string {
+	if unixNano == 0 {
+		return "-"
+	}
+	return time.Unix(0, unixNano).Format(time.RFC3339)
+}
@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/ledger"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+var txCmd = &cobra.Command{
+	Use:   "tx",
+	Short: "Transaction related commands",
+	Long:  `Transaction related commands`,
+}
+
+var txShowCmd = &cobra.Command{
+	Use:   "show [hash]",
+	Short: "Shows the transaction [hash]",
+	Long: `Shows the transaction [hash]: its decoded fields, whether the node verifies it as a transaction of its
+ledger and the votes that confirmed its transfer, if the node knows them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Printf("Expected [hash]\n")
+			os.Exit(1)
+		}
+
+		lookup, done := newTxLookup()
+		defer done()
+
+		tx := lookup.mustGet(args[0])
+		previous, err := lookup.previous(tx)
+		if err != nil {
+			fmt.Printf("Get previous transaction failed: %s\n", err)
+			os.Exit(1)
+		}
+		verifyErr := lookup.verify(tx)
+		confirmation, err := lookup.votes(tx.Hash)
+		if err != nil {
+			fmt.Printf("Get votes failed: %s\n", err)
+			os.Exit(1)
+		}
+		printTransaction(os.Stdout, tx, previous, verifyErr, confirmation, cfg.GetString(config.CfgChainId))
+	},
+}
+
+var txTraceCmd = &cobra.Command{
+	Use:   "trace [hash]",
+	Short: "Follows the transfer of the transaction [hash] across the accounts",
+	Long: `Follows the transfer of the transaction [hash], a send, receive or open transaction, across the accounts:
+the send transaction, the receive (or open) transaction that claims it, if any yet, and the previous and next
+transactions in the chain of each.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Printf("Expected [hash]\n")
+			os.Exit(1)
+		}
+
+		lookup, done := newTxLookup()
+		defer done()
+
+		tx := lookup.mustGet(args[0])
+		var send, receive *ledger.Transaction
+		switch {
+		case tx.Type == ledger.Transaction_SEND:
+			send = tx
+			result, err := lookup.ld.GetReceiveForSend(lookup.ctx, &ledger.GetReceiveForSendRequest{Hash: tx.Hash})
+			if err != nil {
+				fmt.Printf("Get receive transaction failed: %s\n", err)
+				os.Exit(1)
+			}
+			receive = result.Tx
+		case (tx.Type == ledger.Transaction_OPEN || tx.Type == ledger.Transaction_RECEIVE) && tx.Link != "":
+			receive = tx
+			send = lookup.mustGet(tx.Link)
+		default:
+			fmt.Printf("Transaction %s is not part of a transfer\n", tx.Hash)
+			os.Exit(1)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(w, "Transfer of %v from %s to %s\n", lookup.mustBalanceBefore(send)-send.Balance,
+			send.Address, send.Link)
+		lookup.printChain(w, "Send", send)
+		if receive == nil {
+			fmt.Fprintln(w, "\nReceive\n  Pending")
+		} else {
+			lookup.printChain(w, "Receive", receive)
+		}
+		w.Flush()
+	},
+}
+
+// txLookup reads the transactions, their verification and their votes from the node.
+type txLookup struct {
+	ctx context.Context
+	ld  ledger.LedgerClient
+	con consensus.ConsensusClient
+}
+
+// newTxLookup connects to the node and returns the lookup and the function that closes it.
+func newTxLookup() (*txLookup, func()) {
+	conn, err := dialNode(cfg.GetString(config.CfgNodeServer))
+	if err != nil {
+		fmt.Printf("Connection to node failed: %s\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	lookup := &txLookup{ctx: ctx, ld: ledger.NewLedgerClient(conn), con: consensus.NewConsensusClient(conn)}
+	return lookup, func() {
+		cancel()
+		conn.Close()
+	}
+}
+
+// mustGet returns the transaction hash, exiting if the node does not have it.
+func (l *txLookup) mustGet(hash string) *ledger.Transaction {
+	result, err := l.ld.GetTransaction(l.ctx, &ledger.GetTransactionRequest{Hash: hash})
+	if err != nil {
+		fmt.Printf("Get transaction failed: %s\n", err)
+		os.Exit(1)
+	}
+	if result.Tx == nil {
+		fmt.Printf("Transaction %s not found\n", hash)
+		os.Exit(1)
+	}
+	return result.Tx
+}
+
+// previous returns the transaction before tx in its chain, nil for an open transaction.
+func (l *txLookup) previous(tx *ledger.Transaction) (*ledger.Transaction, error) {
+	if tx.Previous == "" {
+		return nil, nil
+	}
+	result, err := l.ld.GetTransaction(l.ctx, &ledger.GetTransactionRequest{Hash: tx.Previous})
+	if err != nil {
+		return nil, err
+	}
+	return result.Tx, nil
+}
+
+// next returns the transaction after tx in its chain, nil for the head.
+func (l *txLookup) next(tx *ledger.Transaction) (*ledger.Transaction, error) {
+	result, err := l.ld.GetAddressStatement(l.ctx, &ledger.GetAddressStatementRequest{Address: tx.Address,
+		StartHash: tx.Hash, Limit: 2})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Txs) < 2 {
+		return nil, nil
+	}
+	return result.Txs[1], nil
+}
+
+// mustBalanceBefore returns the balance of the chain of tx before it, exiting if the previous transaction cannot
+// be read.
+func (l *txLookup) mustBalanceBefore(tx *ledger.Transaction) float64 {
+	previous, err := l.previous(tx)
+	if err != nil {
+		fmt.Printf("Get previous transaction failed: %s\n", err)
+		os.Exit(1)
+	}
+	return balanceBefore(tx, previous)
+}
+
+// verify returns the reason the node rejects tx as a transaction of its ledger, nil if it does not.
+func (l *txLookup) verify(tx *ledger.Transaction) error {
+	_, err := l.ld.VerifyTransaction(l.ctx, &ledger.VerifyTransactionRequest{Tx: tx, Stored: true})
+	return err
+}
+
+// votes returns the votes that confirmed the transfer of the transaction hash, nil if the node does not know them.
+func (l *txLookup) votes(hash string) (*consensus.Confirmation, error) {
+	result, err := l.con.GetVotes(l.ctx, &consensus.GetVotesRequest{Hash: hash})
+	if err != nil {
+		return nil, err
+	}
+	return result.Confirmation, nil
+}
+
+// printChain prints tx and the transactions around it in its chain under title.
+func (l *txLookup) printChain(w io.Writer, title string, tx *ledger.Transaction) {
+	previous, err := l.previous(tx)
+	if err != nil {
+		fmt.Printf("Get previous transaction failed: %s\n", err)
+		os.Exit(1)
+	}
+	next, err := l.next(tx)
+	if err != nil {
+		fmt.Printf("Get next transaction failed: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(w, "\n%s (chain of %s)\n", title, tx.Address)
+	fmt.Fprintln(w, "  \tHASH\tTYPE\tTIME\tBALANCE")
+	printChainEntry(w, "Previous", previous)
+	printChainEntry(w, ">", tx)
+	printChainEntry(w, "Next", next)
+}
+
+func printChainEntry(w io.Writer, label string, tx *ledger.Transaction) {
+	if tx == nil {
+		fmt.Fprintf(w, "  %s\t-\t\t\t\n", label)
+		return
+	}
+	fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%v\n", label, tx.Hash, tx.Type, formatTime(tx.Timestamp), tx.Balance)
+}
+
+func printTransaction(out io.Writer, tx, previous *ledger.Transaction, verifyErr error,
+	confirmation *consensus.Confirmation, chainId string) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Hash:\t%s\n", tx.Hash)
+	fmt.Fprintf(w, "Type:\t%s\n", tx.Type)
+	fmt.Fprintf(w, "Address:\t%s\n", tx.Address)
+	fmt.Fprintf(w, "Time:\t%s\n", formatTime(tx.Timestamp))
+	if tx.Previous == "" {
+		fmt.Fprintln(w, "Previous:\t-")
+	} else {
+		fmt.Fprintf(w, "Previous:\t%s\n", tx.Previous)
+	}
+	switch tx.Type {
+	case ledger.Transaction_SEND:
+		fmt.Fprintf(w, "Sent to:\t%s\n", tx.Link)
+		fmt.Fprintf(w, "Amount:\t%v\n", balanceBefore(tx, previous)-tx.Balance)
+	case ledger.Transaction_OPEN, ledger.Transaction_RECEIVE:
+		if tx.Link == "" {
+			fmt.Fprintln(w, "Send transaction:\t- (genesis)")
+		} else {
+			fmt.Fprintf(w, "Send transaction:\t%s\n", tx.Link)
+		}
+		fmt.Fprintf(w, "Amount:\t%v\n", tx.Balance-balanceBefore(tx, previous))
+	default:
+		fmt.Fprintf(w, "Link:\t%s\n", tx.Link)
+	}
+	fmt.Fprintf(w, "Balance:\t%v\n", tx.Balance)
+	fmt.Fprintf(w, "PoW nonce:\t%d\n", tx.PowNonce)
+	fmt.Fprintf(w, "Public key:\t%s\n", tx.PubKey)
+	fmt.Fprintf(w, "Signature:\t%s\n", tx.Signature)
+	if verifyErr == nil {
+		fmt.Fprintln(w, "Verification:\tvalid")
+	} else {
+		fmt.Fprintf(w, "Verification:\tinvalid (%s)\n", verifyErr)
+	}
+
+	if confirmation == nil {
+		fmt.Fprintln(w, "\nVotes\n  Not known to the node")
+	} else {
+		fmt.Fprintf(w, "\nVotes (%d)\n", len(confirmation.Votes))
+		fmt.Fprintln(w, "  VOTER\tVOTE\tCONFIRMS")
+		for _, vote := range confirmation.Votes {
+			result := "accept"
+			if !vote.Ok {
+				result = "reject"
+			}
+			if vote.Reason != "" {
+				result += " (" + vote.Reason + ")"
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", hex.EncodeToString(vote.PubKey), result,
+				voteConfirmation(vote, confirmation, chainId))
+		}
+	}
+	w.Flush()
+}
+
+// voteConfirmation tells whether vote confirms the transfer of confirmation in the chain chainId: a signature only
+// proves that someone signed the vote, which confirms the transfer only if it is bound to the chain and to the send
+// and receive transactions of the transfer.
+func voteConfirmation(vote *consensus.Vote, confirmation *consensus.Confirmation, chainId string) string {
+	if !vote.VerifySignature() {
+		return "no (invalid signature)"
+	}
+	sendTx := &ledger.Transaction{Hash: confirmation.SendHash}
+	receiveTx := &ledger.Transaction{Hash: confirmation.ReceiveHash}
+	if !vote.IsFor(chainId, sendTx, receiveTx) {
+		return "no (vote for another transfer)"
+	}
+	return "yes"
+}
+
+// balanceBefore returns the balance of the chain of tx before it, given the previous transaction.
+func balanceBefore(tx, previous *ledger.Transaction) float64 {
+	if tx.Type == ledger.Transaction_OPEN || previous == nil {
+		return 0
+	}
+	return previous.Balance
+}
@@ -2,17 +2,20 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/msaldanha/realChain/config"
 	"github.com/msaldanha/realChain/keyvaluestore"
 	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/logging"
 	"github.com/msaldanha/realChain/wallet"
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 )
 
 var walletCmd = &cobra.Command{
@@ -57,8 +60,10 @@ var walletCreateAddressCmd = &cobra.Command{
 
 var walletListAddressStatementCmd = &cobra.Command{
 	Use:   "statement [address]",
-	Short: "Lists all transactions for [address]",
-	Long:  `Lists all transactions for [address]`,
+	Short: "Lists the transactions of [address]",
+	Long: `Lists the transactions of [address], from the open transaction (or from --start or --height) towards the head,
+or the other way round with --reverse. With --limit a single page is listed, followed by the hash the next page
+starts at, otherwise the whole statement is streamed. --type, --from and --to filter the transactions.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
 			fmt.Printf("Expected [address]\n")
@@ -66,8 +71,28 @@ var walletListAddressStatementCmd = &cobra.Command{
 			return
 		}
 
+		request, err := statementRequest(cmd, args[0])
+		if err != nil {
+			fmt.Printf("Invalid statement options: %s \n", err)
+			os.Exit(1)
+		}
+
 		wa := getWallet()
-		st, err := wa.GetAddressStatement(args[0])
+		var st []*ledger.Transaction
+		next := ""
+		if request.Limit > 0 {
+			var result *ledger.GetAddressStatementResult
+			result, err = wa.GetStatementPage(request)
+			if result != nil {
+				st, next = result.Txs, result.NextHash
+			}
+		} else {
+			st = make([]*ledger.Transaction, 0)
+			err = wa.ExportStatement(context.Background(), request, func(tx *ledger.Transaction) error {
+				st = append(st, tx)
+				return nil
+			})
+		}
 		if err != nil {
 			fmt.Printf("List address statement failed: %s \n", err)
 			os.Exit(1)
@@ -75,6 +100,154 @@ var walletListAddressStatementCmd = &cobra.Command{
 		}
 
 		fmt.Printf("Statement for address %s : \n%s\n", args[0], getPrettyJson(st))
+		if next != "" {
+			fmt.Printf("Next page starts at: %s\n", next)
+		}
+	},
+}
+
+func statementRequest(cmd *cobra.Command, addr string) (*ledger.GetAddressStatementRequest, error) {
+	request := &ledger.GetAddressStatementRequest{Address: addr}
+	request.StartHash, _ = cmd.Flags().GetString("start")
+	request.StartHeight, _ = cmd.Flags().GetInt64("height")
+	request.Limit, _ = cmd.Flags().GetUint32("limit")
+	if reverse, _ := cmd.Flags().GetBool("reverse"); reverse {
+		request.Direction = ledger.Direction_BACKWARD
+	}
+
+	txType, _ := cmd.Flags().GetString("type")
+	if txType != "" {
+		value, ok := ledger.Transaction_Type_value[strings.ToUpper(txType)]
+		if !ok {
+			return nil, fmt.Errorf("invalid transaction type: %s", txType)
+		}
+		request.Type = ledger.Transaction_Type(value)
+	}
+
+	var err error
+	from, _ := cmd.Flags().GetString("from")
+	request.FromTimestamp, err = parseTime(from)
+	if err != nil {
+		return nil, err
+	}
+	to, _ := cmd.Flags().GetString("to")
+	request.ToTimestamp, err = parseTime(to)
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// parseTime parses an RFC 3339 time or a date into a transaction timestamp, 0 if value is empty.
+func parseTime(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time: %s", value)
+		}
+	}
+	return t.UnixNano(), nil
+}
+
+var walletBalanceCmd = &cobra.Command{
+	Use:   "balance [address]",
+	Short: "Prints the balance of [address]",
+	Long: `Prints the balance of [address], now, after the transactions before --at or after the transaction at
+--height, the open one being 1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Printf("Expected [address]\n")
+			os.Exit(1)
+			return
+		}
+
+		at, _ := cmd.Flags().GetString("at")
+		timestamp, err := parseTime(at)
+		if err != nil {
+			fmt.Printf("Invalid balance options: %s \n", err)
+			os.Exit(1)
+		}
+		height, _ := cmd.Flags().GetInt64("height")
+
+		wa := getWallet()
+		balance, err := wa.GetBalanceAt(args[0], timestamp, height)
+		if err != nil {
+			fmt.Printf("Get balance failed: %s \n", err)
+			os.Exit(1)
+			return
+		}
+
+		fmt.Printf("Balance for address %s : \n%s\n", args[0], getPrettyJson(balance))
+	},
+}
+
+var walletHistoryCmd = &cobra.Command{
+	Use:   "history [address]",
+	Short: "Prints the balances of [address] over time",
+	Long: `Prints the balances of [address] at the end of every --bucket (a day by default) from --from up to --to,
+or up to now`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Printf("Expected [address]\n")
+			os.Exit(1)
+			return
+		}
+
+		from, _ := cmd.Flags().GetString("from")
+		if from == "" {
+			fmt.Printf("Expected --from\n")
+			os.Exit(1)
+		}
+		fromTimestamp, err := parseTime(from)
+		if err != nil {
+			fmt.Printf("Invalid balance history options: %s \n", err)
+			os.Exit(1)
+		}
+		to, _ := cmd.Flags().GetString("to")
+		toTimestamp, err := parseTime(to)
+		if err != nil {
+			fmt.Printf("Invalid balance history options: %s \n", err)
+			os.Exit(1)
+		}
+		bucket, _ := cmd.Flags().GetDuration("bucket")
+
+		wa := getWallet()
+		balances, err := wa.GetBalanceHistory(args[0], fromTimestamp, toTimestamp, bucket)
+		if err != nil {
+			fmt.Printf("Get balance history failed: %s \n", err)
+			os.Exit(1)
+			return
+		}
+
+		fmt.Printf("Balance history for address %s : \n%s\n", args[0], getPrettyJson(balances))
+	},
+}
+
+var walletIncomingCmd = &cobra.Command{
+	Use:   "incoming [address]",
+	Short: "Lists the send transactions to [address]",
+	Long:  `Lists the send transactions to [address], only the ones not received yet with --pending`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Printf("Expected [address]\n")
+			os.Exit(1)
+			return
+		}
+		pending, _ := cmd.Flags().GetBool("pending")
+
+		wa := getWallet()
+		txs, err := wa.GetIncoming(args[0], pending)
+		if err != nil {
+			fmt.Printf("List incoming transactions failed: %s \n", err)
+			os.Exit(1)
+			return
+		}
+
+		fmt.Printf("Incoming transactions for address %s : \n%s\n", args[0], getPrettyJson(txs))
 	},
 }
 
@@ -107,6 +280,50 @@ var walletSendCmd = &cobra.Command{
 	},
 }
 
+var walletWatchCmd = &cobra.Command{
+	Use:   "watch [address...]",
+	Short: "Prints the transactions of [address...] as the node receives them",
+	Long:  `Prints the transactions of [address...] (or of every address) as the node receives them`,
+	Run: func(cmd *cobra.Command, args []string) {
+		request := &ledger.SubscribeRequest{Addresses: args}
+
+		txType, _ := cmd.Flags().GetString("type")
+		if txType != "" {
+			value, ok := ledger.Transaction_Type_value[strings.ToUpper(txType)]
+			if !ok {
+				fmt.Printf("Invalid transaction type: %s\n", txType)
+				os.Exit(1)
+			}
+			request.Type = ledger.Transaction_Type(value)
+		}
+
+		state, _ := cmd.Flags().GetString("state")
+		if state != "" {
+			value, ok := ledger.ConfirmationState_value[strings.ToUpper(state)]
+			if !ok {
+				fmt.Printf("Invalid confirmation state: %s\n", state)
+				os.Exit(1)
+			}
+			request.State = ledger.ConfirmationState(value)
+		}
+
+		if cmd.Flags().Changed("cursor") {
+			request.Cursor, _ = cmd.Flags().GetUint64("cursor")
+			request.Resume = true
+		}
+
+		wa := getWallet()
+		err := wa.Watch(context.Background(), request, func(event *ledger.TransactionEvent) error {
+			fmt.Printf("%s transaction (cursor %d): \n%s\n", event.State, event.Cursor, getPrettyJson(event.Tx))
+			return nil
+		})
+		if err != nil {
+			fmt.Printf("Watch failed: %s \n", err)
+			os.Exit(1)
+		}
+	},
+}
+
 func getPrettyJson(v interface{}) string {
 	var prettyJSON bytes.Buffer
 	jsonBytes, _ := json.Marshal(v)
@@ -126,13 +343,21 @@ func getWallet() *wallet.Wallet {
 		os.Exit(1)
 	}
 
-	conn, err := grpc.Dial(cfg.GetString(config.CfgNodeServer), grpc.WithInsecure())
+	conn, err := dialNode(cfg.GetString(config.CfgNodeServer))
 	if err != nil {
 		fmt.Printf("Wallet connection to ledger failed: %s ", err)
 		os.Exit(1)
 	}
 
+	logger, err := logging.New(cfg)
+	if err != nil {
+		fmt.Printf("Wallet logging configuration failed: %s ", err)
+		os.Exit(1)
+	}
+
 	ld := ledger.NewLedgerClient(conn)
 
-	return wallet.New(as, ld)
+	wa := wallet.New(as, ld)
+	wa.SetLogger(logger)
+	return wa
 }
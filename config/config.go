@@ -1,15 +1,74 @@
 package config
 
+// Version is the version of the node software.
+const Version = "v0.1"
+
 const (
 	CfgDataFolder          = "datafolder"
 	CfgLedgerChainFile     = "ledger.chain"
+	CfgLedgerEventsFile    = "ledger.events"
+	CfgLedgerIndexFile     = "ledger.index"
 	CfgWalletChainFile     = "wallet.chain"
 	CfgWalletAddressesFile = "wallet.addresses"
 	CfgNodeAddressesFile   = "node.addresses"
 	CfgNodeServer          = "node.server"
+	CfgNodeAdvertise       = "node.advertise"
+	CfgNodeBootstrap       = "node.bootstrap"
+	CfgNodeSyncStateFile   = "node.syncstate"
+	CfgNodeVotesFile       = "node.votes"
+	CfgNodeAntiEntropy     = "node.antientropy"
+	CfgNodeShutdownTimeout = "node.shutdowntimeout"
+	CfgNodeQuorum          = "node.quorum"
+	CfgNodeSocket          = "node.socket"
+	CfgNodeSocketMode      = "node.socketmode"
 	CfgUdpServer           = "node.udpserver"
+	CfgChainId             = "chainid"
 	CfgPeers               = "peers"
+	CfgDiscovery           = "discovery.mode"
+	CfgDiscoveryTableFile  = "discovery.table"
+	CfgDiscoveryMaxPeers   = "discovery.maxpeers"
+	CfgDiscoveryInterval   = "discovery.interval"
+	CfgDiscoveryExpiry     = "discovery.expiry"
+	CfgDiscoveryAnnounce   = "discovery.announce"
+	CfgDiscoveryPing       = "discovery.pinginterval"
+	CfgDiscoveryBanTime    = "discovery.bantime"
+	CfgTls                 = "tls.enabled"
+	CfgTlsCert             = "tls.cert"
+	CfgTlsKey              = "tls.key"
+	CfgTlsCA               = "tls.ca"
+	CfgTlsCAKey            = "tls.cakey"
+	CfgTlsClientAuth       = "tls.clientauth"
+	CfgTlsPins             = "tls.pins"
+	CfgGatewayServer       = "gateway.server"
+	CfgAdminServer         = "admin.server"
+	CfgMetricsServer       = "metrics.server"
+	CfgHealthServer        = "health.server"
+	CfgLogLevel            = "log.level"
+	CfgLogFormat           = "log.format"
+	CfgLimitRate           = "limits.rate"
+	CfgLimitBurst          = "limits.burst"
+	CfgLimitMethods        = "limits.methods"
+	CfgLimitConcurrency    = "limits.concurrency"
+	CfgLimitMaxRecvSize    = "limits.maxrecvsize"
+	CfgLimitMaxSendSize    = "limits.maxsendsize"
+	CfgAuth                = "auth.enabled"
+	CfgAuthTokens          = "auth.tokens"
+	CfgAuthTokenFile       = "auth.tokenfile"
+	CfgAuthMethods         = "auth.methods"
+	CfgAuthToken           = "auth.token"
 
 	AddressBucket = "Addresses"
 	TxBucket      = "TxChain"
+	SyncBucket    = "Sync"
+	PeersBucket   = "Peers"
+	EventsBucket  = "Events"
+	IndexBucket   = "Index"
+	VotesBucket   = "Votes"
+
+	DiscoveryStatic  = "static"
+	DiscoveryDynamic = "dynamic"
+	DiscoveryUdp     = "udp"
+
+	LogFormatText = "text"
+	LogFormatJson = "json"
 )
@@ -1,9 +1,15 @@
 package consensus
 
 import (
+	"bytes"
+	"encoding/hex"
+	"github.com/golang/protobuf/proto"
 	"github.com/msaldanha/realChain/address"
 	"github.com/msaldanha/realChain/errors"
+	"github.com/msaldanha/realChain/keyvaluestore"
 	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/logging"
+	log "github.com/sirupsen/logrus"
 )
 
 //go:generate mockgen -destination=../tests/mock_consensusclient.go -package=tests github.com/msaldanha/realChain/consensus ConsensusClient
@@ -12,51 +18,116 @@ import (
 
 const (
 	ErrInvalidVotingResult = errors.Error("invalid voting result")
+	ErrInvalidVote         = errors.Error("invalid vote")
+	ErrDuplicatedVote      = errors.Error("duplicated vote")
+	ErrUnknownVoter        = errors.Error("vote not signed by a known peer")
+	ErrNotEnoughVotes      = errors.Error("not enough votes for the quorum")
 )
 
 type Consensus interface {
 	Vote(*VoteRequest) (*VoteResult, error)
 	Accept(*AcceptRequest) (*AcceptResult, error)
+	Handshake(*HandshakeRequest) (*HandshakeResult, error)
+	Confirm(sendTx, receiveTx *ledger.Transaction, votes []*Vote) error
+	GetVotes(hash string) (*Confirmation, error)
+}
+
+// Voters tells whether a node key belongs to a node whose votes are accepted, such as an authenticated peer.
+type Voters interface {
+	IsVoter(pubKey []byte) bool
+}
+
+// VoterSets accepts the votes of the nodes of any of its sets.
+type VoterSets []Voters
+
+func (s VoterSets) IsVoter(pubKey []byte) bool {
+	for _, voters := range s {
+		if voters.IsVoter(pubKey) {
+			return true
+		}
+	}
+	return false
 }
 
 type consensus struct {
-	ledger ledger.Ledger
-	addr   *address.Address
+	ledger  ledger.Ledger
+	addr    *address.Address
+	chainId string
+	id      *Identity
+	voters  Voters
+	quorum  int
+	votes   keyvaluestore.Storer
+	logger  *log.Entry
 }
 
-func NewConsensus(ledger ledger.Ledger, addr *address.Address) *consensus {
+func NewConsensus(ledger ledger.Ledger, addr *address.Address, chainId string) *consensus {
 	return &consensus{
-		ledger: ledger,
-		addr:   addr,
+		ledger:  ledger,
+		addr:    addr,
+		chainId: chainId,
+		id:      NewIdentity(addr.Keys, chainId),
+		quorum:  1,
+		votes:   keyvaluestore.NewMemoryKeyValueStore(),
+		logger:  logging.Component(log.StandardLogger(), "consensus"),
 	}
 }
 
+// SetVoters sets the nodes whose votes are accepted besides this node, none by default.
+func (c *consensus) SetVoters(voters Voters) {
+	c.voters = voters
+}
+
+// SetQuorum sets the number of votes of distinct nodes a transfer needs to be accepted, 1 by default.
+func (c *consensus) SetQuorum(quorum int) {
+	c.quorum = quorum
+}
+
+// SetLogger sets the logger of the consensus.
+func (c *consensus) SetLogger(logger log.FieldLogger) {
+	c.logger = logging.Component(logger, "consensus")
+}
+
+// SetVoteStore sets the store of the votes that confirmed the registered transfers, an in-memory one by default.
+func (c *consensus) SetVoteStore(store keyvaluestore.Storer) {
+	c.votes = store
+}
+
 func (c *consensus) Vote(request *VoteRequest) (*VoteResult, error) {
-	err := c.ledger.VerifyTransaction(request.ReceiveTx, true)
+	err := c.verify(request)
 	if err != nil {
-		return c.createVoteResult(false, err.Error())
+		c.transferLogger(request.SendTx, request.ReceiveTx).Debugf("Voting against transfer: %s", err)
+		return c.createVoteResult(request, false, err.Error())
 	}
+	return c.createVoteResult(request, true, "")
+}
 
-	err = c.ledger.VerifyTransaction(request.SendTx, true)
+func (c *consensus) verify(request *VoteRequest) error {
+	err := c.ledger.VerifyTransaction(request.ReceiveTx, true)
 	if err != nil {
-		return c.createVoteResult(false, err.Error())
+		return err
 	}
 
-	err = c.ledger.Verify(request.SendTx, request.ReceiveTx)
+	err = c.ledger.VerifyTransaction(request.SendTx, true)
 	if err != nil {
-		return c.createVoteResult(false, err.Error())
+		return err
 	}
 
-	return c.createVoteResult(true, "")
+	return c.ledger.Verify(request.SendTx, request.ReceiveTx)
 }
 
 func (c *consensus) Accept(request *AcceptRequest) (*AcceptResult, error) {
 	oks := 0
+	voters := make(map[string]bool)
 	for _, vote := range request.Votes {
-		err := c.validateVote(vote)
+		err := c.validateVote(vote, request.SendTx, request.ReceiveTx)
 		if err != nil {
 			return nil, err
 		}
+		voter := hex.EncodeToString(vote.PubKey)
+		if voters[voter] {
+			return nil, ErrDuplicatedVote
+		}
+		voters[voter] = true
 		if vote.Ok {
 			oks++
 		}
@@ -64,15 +135,80 @@ func (c *consensus) Accept(request *AcceptRequest) (*AcceptResult, error) {
 	if oks == 0 || oks != len(request.Votes) {
 		return nil, ErrInvalidVotingResult
 	}
+	if oks < c.quorum {
+		return nil, ErrNotEnoughVotes
+	}
 	err := c.ledger.Register(request.SendTx, request.ReceiveTx)
 	if err != nil {
 		return nil, err
 	}
+	logger := c.transferLogger(request.SendTx, request.ReceiveTx)
+	logger.Debugf("Accepted transfer with %d votes", len(request.Votes))
+
+	// The transfer is registered even if its votes are not kept.
+	err = c.Confirm(request.SendTx, request.ReceiveTx, request.Votes)
+	if err != nil {
+		logger.Warnf("Failed to keep the votes of the transfer: %s", err)
+	}
 	return &AcceptResult{}, nil
 }
 
-func (c *consensus) createVoteResult(ok bool, reason string) (*VoteResult, error) {
-	vote := &Vote{Ok: ok, Reason: reason}
+// Confirm keeps the votes that confirmed the registered transfer of sendTx and receiveTx, found by the hash of
+// either transaction.
+func (c *consensus) Confirm(sendTx, receiveTx *ledger.Transaction, votes []*Vote) error {
+	confirmation := &Confirmation{SendHash: sendTx.Hash, ReceiveHash: receiveTx.Hash, Votes: votes}
+	value, err := proto.Marshal(confirmation)
+	if err != nil {
+		return err
+	}
+	err = c.votes.Put(sendTx.Hash, value)
+	if err != nil {
+		return err
+	}
+	return c.votes.Put(receiveTx.Hash, value)
+}
+
+// GetVotes returns the votes that confirmed the transfer of the transaction hash, nil if they are not known, as
+// for the transfers registered before the votes were kept or synchronized from peers.
+func (c *consensus) GetVotes(hash string) (*Confirmation, error) {
+	value, ok, err := c.votes.Get(hash)
+	if err != nil || !ok {
+		return nil, err
+	}
+	confirmation := &Confirmation{}
+	err = proto.Unmarshal(value, confirmation)
+	if err != nil {
+		return nil, err
+	}
+	return confirmation, nil
+}
+
+func (c *consensus) Handshake(request *HandshakeRequest) (*HandshakeResult, error) {
+	return c.id.Answer(request)
+}
+
+// transferLogger returns the logger with the receive transaction of a transfer, which may be missing from an
+// invalid request.
+func (c *consensus) transferLogger(sendTx, receiveTx *ledger.Transaction) *log.Entry {
+	fields := log.Fields{}
+	if sendTx != nil {
+		fields[logging.SendTxField] = sendTx.Hash
+	}
+	if receiveTx != nil {
+		fields[logging.TxField] = receiveTx.Hash
+		fields[logging.AddressField] = receiveTx.Address
+	}
+	return c.logger.WithFields(fields)
+}
+
+func (c *consensus) createVoteResult(request *VoteRequest, ok bool, reason string) (*VoteResult, error) {
+	vote := &Vote{Ok: ok, Reason: reason, ChainId: c.chainId}
+	if request.SendTx != nil {
+		vote.SendHash = request.SendTx.Hash
+	}
+	if request.ReceiveTx != nil {
+		vote.ReceiveHash = request.ReceiveTx.Hash
+	}
 	err := vote.Sign(c.addr.Keys.ToEcdsaPrivateKey())
 	if err != nil {
 		return nil, err
@@ -81,7 +217,15 @@ func (c *consensus) createVoteResult(ok bool, reason string) (*VoteResult, error
 	return &VoteResult{Vote: vote}, nil
 }
 
-func (c *consensus) validateVote(vote *Vote) error {
+// validateVote checks that vote is a vote on the transfer of sendTx and receiveTx signed by this node or by one
+// of the voters.
+func (c *consensus) validateVote(vote *Vote, sendTx, receiveTx *ledger.Transaction) error {
+	if vote == nil || !vote.VerifySignature() || !vote.IsFor(c.chainId, sendTx, receiveTx) {
+		return ErrInvalidVote
+	}
+	if !bytes.Equal(vote.PubKey, c.addr.Keys.PublicKey) && (c.voters == nil || !c.voters.IsVoter(vote.PubKey)) {
+		return ErrUnknownVoter
+	}
 	return nil
 }
 
@@ -36,7 +36,7 @@ func (m *VoteRequest) Reset()         { *m = VoteRequest{} }
 func (m *VoteRequest) String() string { return proto.CompactTextString(m) }
 func (*VoteRequest) ProtoMessage()    {}
 func (*VoteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_consensus_224b1349252bcb32, []int{0}
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{0}
 }
 func (m *VoteRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VoteRequest.Unmarshal(m, b)
@@ -75,6 +75,9 @@ type Vote struct {
 	Reason               string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 	PubKey               []byte   `protobuf:"bytes,3,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
 	Signature            []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	ChainId              string   `protobuf:"bytes,5,opt,name=chainId,proto3" json:"chainId,omitempty"`
+	SendHash             string   `protobuf:"bytes,6,opt,name=sendHash,proto3" json:"sendHash,omitempty"`
+	ReceiveHash          string   `protobuf:"bytes,7,opt,name=receiveHash,proto3" json:"receiveHash,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -84,7 +87,7 @@ func (m *Vote) Reset()         { *m = Vote{} }
 func (m *Vote) String() string { return proto.CompactTextString(m) }
 func (*Vote) ProtoMessage()    {}
 func (*Vote) Descriptor() ([]byte, []int) {
-	return fileDescriptor_consensus_224b1349252bcb32, []int{1}
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{1}
 }
 func (m *Vote) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Vote.Unmarshal(m, b)
@@ -132,6 +135,27 @@ func (m *Vote) GetSignature() []byte {
 	return nil
 }
 
+func (m *Vote) GetChainId() string {
+	if m != nil {
+		return m.ChainId
+	}
+	return ""
+}
+
+func (m *Vote) GetSendHash() string {
+	if m != nil {
+		return m.SendHash
+	}
+	return ""
+}
+
+func (m *Vote) GetReceiveHash() string {
+	if m != nil {
+		return m.ReceiveHash
+	}
+	return ""
+}
+
 type VoteResult struct {
 	Vote                 *Vote    `protobuf:"bytes,1,opt,name=vote,proto3" json:"vote,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -139,120 +163,894 @@ type VoteResult struct {
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *VoteResult) Reset()         { *m = VoteResult{} }
-func (m *VoteResult) String() string { return proto.CompactTextString(m) }
-func (*VoteResult) ProtoMessage()    {}
-func (*VoteResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_consensus_224b1349252bcb32, []int{2}
+func (m *VoteResult) Reset()         { *m = VoteResult{} }
+func (m *VoteResult) String() string { return proto.CompactTextString(m) }
+func (*VoteResult) ProtoMessage()    {}
+func (*VoteResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{2}
+}
+func (m *VoteResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VoteResult.Unmarshal(m, b)
+}
+func (m *VoteResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VoteResult.Marshal(b, m, deterministic)
+}
+func (dst *VoteResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VoteResult.Merge(dst, src)
+}
+func (m *VoteResult) XXX_Size() int {
+	return xxx_messageInfo_VoteResult.Size(m)
+}
+func (m *VoteResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_VoteResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VoteResult proto.InternalMessageInfo
+
+func (m *VoteResult) GetVote() *Vote {
+	if m != nil {
+		return m.Vote
+	}
+	return nil
+}
+
+type AcceptRequest struct {
+	SendTx               *ledger.Transaction `protobuf:"bytes,1,opt,name=sendTx,proto3" json:"sendTx,omitempty"`
+	ReceiveTx            *ledger.Transaction `protobuf:"bytes,2,opt,name=receiveTx,proto3" json:"receiveTx,omitempty"`
+	Votes                []*Vote             `protobuf:"bytes,3,rep,name=votes,proto3" json:"votes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *AcceptRequest) Reset()         { *m = AcceptRequest{} }
+func (m *AcceptRequest) String() string { return proto.CompactTextString(m) }
+func (*AcceptRequest) ProtoMessage()    {}
+func (*AcceptRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{3}
+}
+func (m *AcceptRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AcceptRequest.Unmarshal(m, b)
+}
+func (m *AcceptRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AcceptRequest.Marshal(b, m, deterministic)
+}
+func (dst *AcceptRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AcceptRequest.Merge(dst, src)
+}
+func (m *AcceptRequest) XXX_Size() int {
+	return xxx_messageInfo_AcceptRequest.Size(m)
+}
+func (m *AcceptRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AcceptRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AcceptRequest proto.InternalMessageInfo
+
+func (m *AcceptRequest) GetSendTx() *ledger.Transaction {
+	if m != nil {
+		return m.SendTx
+	}
+	return nil
+}
+
+func (m *AcceptRequest) GetReceiveTx() *ledger.Transaction {
+	if m != nil {
+		return m.ReceiveTx
+	}
+	return nil
+}
+
+func (m *AcceptRequest) GetVotes() []*Vote {
+	if m != nil {
+		return m.Votes
+	}
+	return nil
+}
+
+type AcceptResult struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AcceptResult) Reset()         { *m = AcceptResult{} }
+func (m *AcceptResult) String() string { return proto.CompactTextString(m) }
+func (*AcceptResult) ProtoMessage()    {}
+func (*AcceptResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{4}
+}
+func (m *AcceptResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AcceptResult.Unmarshal(m, b)
+}
+func (m *AcceptResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AcceptResult.Marshal(b, m, deterministic)
+}
+func (dst *AcceptResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AcceptResult.Merge(dst, src)
+}
+func (m *AcceptResult) XXX_Size() int {
+	return xxx_messageInfo_AcceptResult.Size(m)
+}
+func (m *AcceptResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_AcceptResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AcceptResult proto.InternalMessageInfo
+
+type PublishRequest struct {
+	SendTx               *ledger.Transaction `protobuf:"bytes,1,opt,name=sendTx,proto3" json:"sendTx,omitempty"`
+	ReceiveTx            *ledger.Transaction `protobuf:"bytes,2,opt,name=receiveTx,proto3" json:"receiveTx,omitempty"`
+	Votes                []*Vote             `protobuf:"bytes,3,rep,name=votes,proto3" json:"votes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *PublishRequest) Reset()         { *m = PublishRequest{} }
+func (m *PublishRequest) String() string { return proto.CompactTextString(m) }
+func (*PublishRequest) ProtoMessage()    {}
+func (*PublishRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{5}
+}
+func (m *PublishRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PublishRequest.Unmarshal(m, b)
+}
+func (m *PublishRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PublishRequest.Marshal(b, m, deterministic)
+}
+func (dst *PublishRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PublishRequest.Merge(dst, src)
+}
+func (m *PublishRequest) XXX_Size() int {
+	return xxx_messageInfo_PublishRequest.Size(m)
+}
+func (m *PublishRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PublishRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PublishRequest proto.InternalMessageInfo
+
+func (m *PublishRequest) GetSendTx() *ledger.Transaction {
+	if m != nil {
+		return m.SendTx
+	}
+	return nil
+}
+
+func (m *PublishRequest) GetReceiveTx() *ledger.Transaction {
+	if m != nil {
+		return m.ReceiveTx
+	}
+	return nil
+}
+
+func (m *PublishRequest) GetVotes() []*Vote {
+	if m != nil {
+		return m.Votes
+	}
+	return nil
+}
+
+type PublishResult struct {
+	Known                bool     `protobuf:"varint,1,opt,name=known,proto3" json:"known,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PublishResult) Reset()         { *m = PublishResult{} }
+func (m *PublishResult) String() string { return proto.CompactTextString(m) }
+func (*PublishResult) ProtoMessage()    {}
+func (*PublishResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{6}
+}
+func (m *PublishResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PublishResult.Unmarshal(m, b)
+}
+func (m *PublishResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PublishResult.Marshal(b, m, deterministic)
+}
+func (dst *PublishResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PublishResult.Merge(dst, src)
+}
+func (m *PublishResult) XXX_Size() int {
+	return xxx_messageInfo_PublishResult.Size(m)
+}
+func (m *PublishResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_PublishResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PublishResult proto.InternalMessageInfo
+
+func (m *PublishResult) GetKnown() bool {
+	if m != nil {
+		return m.Known
+	}
+	return false
+}
+
+type GetFrontiersRequest struct {
+	Frontiers            map[string]string `protobuf:"bytes,1,rep,name=frontiers,proto3" json:"frontiers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GetFrontiersRequest) Reset()         { *m = GetFrontiersRequest{} }
+func (m *GetFrontiersRequest) String() string { return proto.CompactTextString(m) }
+func (*GetFrontiersRequest) ProtoMessage()    {}
+func (*GetFrontiersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{7}
+}
+func (m *GetFrontiersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetFrontiersRequest.Unmarshal(m, b)
+}
+func (m *GetFrontiersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetFrontiersRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetFrontiersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetFrontiersRequest.Merge(dst, src)
+}
+func (m *GetFrontiersRequest) XXX_Size() int {
+	return xxx_messageInfo_GetFrontiersRequest.Size(m)
+}
+func (m *GetFrontiersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetFrontiersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetFrontiersRequest proto.InternalMessageInfo
+
+func (m *GetFrontiersRequest) GetFrontiers() map[string]string {
+	if m != nil {
+		return m.Frontiers
+	}
+	return nil
+}
+
+type GetFrontiersResult struct {
+	Frontiers            map[string]string `protobuf:"bytes,1,rep,name=frontiers,proto3" json:"frontiers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GetFrontiersResult) Reset()         { *m = GetFrontiersResult{} }
+func (m *GetFrontiersResult) String() string { return proto.CompactTextString(m) }
+func (*GetFrontiersResult) ProtoMessage()    {}
+func (*GetFrontiersResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{8}
+}
+func (m *GetFrontiersResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetFrontiersResult.Unmarshal(m, b)
+}
+func (m *GetFrontiersResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetFrontiersResult.Marshal(b, m, deterministic)
+}
+func (dst *GetFrontiersResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetFrontiersResult.Merge(dst, src)
+}
+func (m *GetFrontiersResult) XXX_Size() int {
+	return xxx_messageInfo_GetFrontiersResult.Size(m)
+}
+func (m *GetFrontiersResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetFrontiersResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetFrontiersResult proto.InternalMessageInfo
+
+func (m *GetFrontiersResult) GetFrontiers() map[string]string {
+	if m != nil {
+		return m.Frontiers
+	}
+	return nil
+}
+
+type GetChainRequest struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	After                string   `protobuf:"bytes,2,opt,name=after,proto3" json:"after,omitempty"`
+	Limit                uint32   `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetChainRequest) Reset()         { *m = GetChainRequest{} }
+func (m *GetChainRequest) String() string { return proto.CompactTextString(m) }
+func (*GetChainRequest) ProtoMessage()    {}
+func (*GetChainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{9}
+}
+func (m *GetChainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetChainRequest.Unmarshal(m, b)
+}
+func (m *GetChainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetChainRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetChainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetChainRequest.Merge(dst, src)
+}
+func (m *GetChainRequest) XXX_Size() int {
+	return xxx_messageInfo_GetChainRequest.Size(m)
+}
+func (m *GetChainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetChainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetChainRequest proto.InternalMessageInfo
+
+func (m *GetChainRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *GetChainRequest) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+func (m *GetChainRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type GetChainResult struct {
+	Txs                  []*ledger.Transaction `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
+	NextHash             string                `protobuf:"bytes,2,opt,name=nextHash,proto3" json:"nextHash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *GetChainResult) Reset()         { *m = GetChainResult{} }
+func (m *GetChainResult) String() string { return proto.CompactTextString(m) }
+func (*GetChainResult) ProtoMessage()    {}
+func (*GetChainResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{10}
+}
+func (m *GetChainResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetChainResult.Unmarshal(m, b)
+}
+func (m *GetChainResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetChainResult.Marshal(b, m, deterministic)
+}
+func (dst *GetChainResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetChainResult.Merge(dst, src)
+}
+func (m *GetChainResult) XXX_Size() int {
+	return xxx_messageInfo_GetChainResult.Size(m)
+}
+func (m *GetChainResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetChainResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetChainResult proto.InternalMessageInfo
+
+func (m *GetChainResult) GetTxs() []*ledger.Transaction {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
+func (m *GetChainResult) GetNextHash() string {
+	if m != nil {
+		return m.NextHash
+	}
+	return ""
+}
+
+type Peer struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	LastSeen             int64    `protobuf:"varint,2,opt,name=lastSeen,proto3" json:"lastSeen,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Peer) Reset()         { *m = Peer{} }
+func (m *Peer) String() string { return proto.CompactTextString(m) }
+func (*Peer) ProtoMessage()    {}
+func (*Peer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{11}
+}
+func (m *Peer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Peer.Unmarshal(m, b)
+}
+func (m *Peer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Peer.Marshal(b, m, deterministic)
+}
+func (dst *Peer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Peer.Merge(dst, src)
+}
+func (m *Peer) XXX_Size() int {
+	return xxx_messageInfo_Peer.Size(m)
+}
+func (m *Peer) XXX_DiscardUnknown() {
+	xxx_messageInfo_Peer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Peer proto.InternalMessageInfo
+
+func (m *Peer) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Peer) GetLastSeen() int64 {
+	if m != nil {
+		return m.LastSeen
+	}
+	return 0
+}
+
+type GetPeersRequest struct {
+	From                 string   `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPeersRequest) Reset()         { *m = GetPeersRequest{} }
+func (m *GetPeersRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPeersRequest) ProtoMessage()    {}
+func (*GetPeersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{12}
+}
+func (m *GetPeersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPeersRequest.Unmarshal(m, b)
+}
+func (m *GetPeersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPeersRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetPeersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPeersRequest.Merge(dst, src)
+}
+func (m *GetPeersRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPeersRequest.Size(m)
+}
+func (m *GetPeersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPeersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPeersRequest proto.InternalMessageInfo
+
+func (m *GetPeersRequest) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+type GetPeersResult struct {
+	Peers                []*Peer  `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPeersResult) Reset()         { *m = GetPeersResult{} }
+func (m *GetPeersResult) String() string { return proto.CompactTextString(m) }
+func (*GetPeersResult) ProtoMessage()    {}
+func (*GetPeersResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{13}
+}
+func (m *GetPeersResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPeersResult.Unmarshal(m, b)
+}
+func (m *GetPeersResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPeersResult.Marshal(b, m, deterministic)
+}
+func (dst *GetPeersResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPeersResult.Merge(dst, src)
+}
+func (m *GetPeersResult) XXX_Size() int {
+	return xxx_messageInfo_GetPeersResult.Size(m)
+}
+func (m *GetPeersResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPeersResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPeersResult proto.InternalMessageInfo
+
+func (m *GetPeersResult) GetPeers() []*Peer {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+type PingRequest struct {
+	Timestamp            int64    `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PingRequest) Reset()         { *m = PingRequest{} }
+func (m *PingRequest) String() string { return proto.CompactTextString(m) }
+func (*PingRequest) ProtoMessage()    {}
+func (*PingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{14}
+}
+func (m *PingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PingRequest.Unmarshal(m, b)
+}
+func (m *PingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PingRequest.Marshal(b, m, deterministic)
+}
+func (dst *PingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PingRequest.Merge(dst, src)
+}
+func (m *PingRequest) XXX_Size() int {
+	return xxx_messageInfo_PingRequest.Size(m)
+}
+func (m *PingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PingRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PingRequest proto.InternalMessageInfo
+
+func (m *PingRequest) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type PingResult struct {
+	Timestamp            int64    `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PingResult) Reset()         { *m = PingResult{} }
+func (m *PingResult) String() string { return proto.CompactTextString(m) }
+func (*PingResult) ProtoMessage()    {}
+func (*PingResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{15}
+}
+func (m *PingResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PingResult.Unmarshal(m, b)
+}
+func (m *PingResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PingResult.Marshal(b, m, deterministic)
+}
+func (dst *PingResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PingResult.Merge(dst, src)
+}
+func (m *PingResult) XXX_Size() int {
+	return xxx_messageInfo_PingResult.Size(m)
+}
+func (m *PingResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_PingResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PingResult proto.InternalMessageInfo
+
+func (m *PingResult) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type NodeInfo struct {
+	PubKey               []byte   `protobuf:"bytes,1,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
+	Version              uint32   `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	ChainId              string   `protobuf:"bytes,3,opt,name=chainId,proto3" json:"chainId,omitempty"`
+	Capabilities         []string `protobuf:"bytes,4,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeInfo) Reset()         { *m = NodeInfo{} }
+func (m *NodeInfo) String() string { return proto.CompactTextString(m) }
+func (*NodeInfo) ProtoMessage()    {}
+func (*NodeInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{16}
+}
+func (m *NodeInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeInfo.Unmarshal(m, b)
+}
+func (m *NodeInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeInfo.Marshal(b, m, deterministic)
+}
+func (dst *NodeInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeInfo.Merge(dst, src)
+}
+func (m *NodeInfo) XXX_Size() int {
+	return xxx_messageInfo_NodeInfo.Size(m)
+}
+func (m *NodeInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeInfo proto.InternalMessageInfo
+
+func (m *NodeInfo) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *NodeInfo) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *NodeInfo) GetChainId() string {
+	if m != nil {
+		return m.ChainId
+	}
+	return ""
+}
+
+func (m *NodeInfo) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+type HandshakeRequest struct {
+	Info                 *NodeInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	Timestamp            int64     `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Nonce                []byte    `protobuf:"bytes,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Signature            []byte    `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *HandshakeRequest) Reset()         { *m = HandshakeRequest{} }
+func (m *HandshakeRequest) String() string { return proto.CompactTextString(m) }
+func (*HandshakeRequest) ProtoMessage()    {}
+func (*HandshakeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{17}
+}
+func (m *HandshakeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HandshakeRequest.Unmarshal(m, b)
+}
+func (m *HandshakeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HandshakeRequest.Marshal(b, m, deterministic)
+}
+func (dst *HandshakeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HandshakeRequest.Merge(dst, src)
+}
+func (m *HandshakeRequest) XXX_Size() int {
+	return xxx_messageInfo_HandshakeRequest.Size(m)
+}
+func (m *HandshakeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HandshakeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HandshakeRequest proto.InternalMessageInfo
+
+func (m *HandshakeRequest) GetInfo() *NodeInfo {
+	if m != nil {
+		return m.Info
+	}
+	return nil
+}
+
+func (m *HandshakeRequest) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *HandshakeRequest) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+func (m *HandshakeRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
 }
-func (m *VoteResult) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_VoteResult.Unmarshal(m, b)
+
+type HandshakeResult struct {
+	Info                 *NodeInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	Session              string    `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
+	Signature            []byte    `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
-func (m *VoteResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_VoteResult.Marshal(b, m, deterministic)
+
+func (m *HandshakeResult) Reset()         { *m = HandshakeResult{} }
+func (m *HandshakeResult) String() string { return proto.CompactTextString(m) }
+func (*HandshakeResult) ProtoMessage()    {}
+func (*HandshakeResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{18}
 }
-func (dst *VoteResult) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_VoteResult.Merge(dst, src)
+func (m *HandshakeResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HandshakeResult.Unmarshal(m, b)
 }
-func (m *VoteResult) XXX_Size() int {
-	return xxx_messageInfo_VoteResult.Size(m)
+func (m *HandshakeResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HandshakeResult.Marshal(b, m, deterministic)
 }
-func (m *VoteResult) XXX_DiscardUnknown() {
-	xxx_messageInfo_VoteResult.DiscardUnknown(m)
+func (dst *HandshakeResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HandshakeResult.Merge(dst, src)
+}
+func (m *HandshakeResult) XXX_Size() int {
+	return xxx_messageInfo_HandshakeResult.Size(m)
+}
+func (m *HandshakeResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_HandshakeResult.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_VoteResult proto.InternalMessageInfo
+var xxx_messageInfo_HandshakeResult proto.InternalMessageInfo
 
-func (m *VoteResult) GetVote() *Vote {
+func (m *HandshakeResult) GetInfo() *NodeInfo {
 	if m != nil {
-		return m.Vote
+		return m.Info
 	}
 	return nil
 }
 
-type AcceptRequest struct {
-	SendTx               *ledger.Transaction `protobuf:"bytes,1,opt,name=sendTx,proto3" json:"sendTx,omitempty"`
-	ReceiveTx            *ledger.Transaction `protobuf:"bytes,2,opt,name=receiveTx,proto3" json:"receiveTx,omitempty"`
-	Votes                []*Vote             `protobuf:"bytes,3,rep,name=votes,proto3" json:"votes,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+func (m *HandshakeResult) GetSession() string {
+	if m != nil {
+		return m.Session
+	}
+	return ""
 }
 
-func (m *AcceptRequest) Reset()         { *m = AcceptRequest{} }
-func (m *AcceptRequest) String() string { return proto.CompactTextString(m) }
-func (*AcceptRequest) ProtoMessage()    {}
-func (*AcceptRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_consensus_224b1349252bcb32, []int{3}
+func (m *HandshakeResult) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
 }
-func (m *AcceptRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_AcceptRequest.Unmarshal(m, b)
+
+type Confirmation struct {
+	SendHash             string   `protobuf:"bytes,1,opt,name=sendHash,proto3" json:"sendHash,omitempty"`
+	ReceiveHash          string   `protobuf:"bytes,2,opt,name=receiveHash,proto3" json:"receiveHash,omitempty"`
+	Votes                []*Vote  `protobuf:"bytes,3,rep,name=votes,proto3" json:"votes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
-func (m *AcceptRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_AcceptRequest.Marshal(b, m, deterministic)
+
+func (m *Confirmation) Reset()         { *m = Confirmation{} }
+func (m *Confirmation) String() string { return proto.CompactTextString(m) }
+func (*Confirmation) ProtoMessage()    {}
+func (*Confirmation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{19}
 }
-func (dst *AcceptRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_AcceptRequest.Merge(dst, src)
+func (m *Confirmation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Confirmation.Unmarshal(m, b)
 }
-func (m *AcceptRequest) XXX_Size() int {
-	return xxx_messageInfo_AcceptRequest.Size(m)
+func (m *Confirmation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Confirmation.Marshal(b, m, deterministic)
 }
-func (m *AcceptRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_AcceptRequest.DiscardUnknown(m)
+func (dst *Confirmation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Confirmation.Merge(dst, src)
+}
+func (m *Confirmation) XXX_Size() int {
+	return xxx_messageInfo_Confirmation.Size(m)
+}
+func (m *Confirmation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Confirmation.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_AcceptRequest proto.InternalMessageInfo
+var xxx_messageInfo_Confirmation proto.InternalMessageInfo
 
-func (m *AcceptRequest) GetSendTx() *ledger.Transaction {
+func (m *Confirmation) GetSendHash() string {
 	if m != nil {
-		return m.SendTx
+		return m.SendHash
 	}
-	return nil
+	return ""
 }
 
-func (m *AcceptRequest) GetReceiveTx() *ledger.Transaction {
+func (m *Confirmation) GetReceiveHash() string {
 	if m != nil {
-		return m.ReceiveTx
+		return m.ReceiveHash
 	}
-	return nil
+	return ""
 }
 
-func (m *AcceptRequest) GetVotes() []*Vote {
+func (m *Confirmation) GetVotes() []*Vote {
 	if m != nil {
 		return m.Votes
 	}
 	return nil
 }
 
-type AcceptResult struct {
+type GetVotesRequest struct {
+	Hash                 string   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *AcceptResult) Reset()         { *m = AcceptResult{} }
-func (m *AcceptResult) String() string { return proto.CompactTextString(m) }
-func (*AcceptResult) ProtoMessage()    {}
-func (*AcceptResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_consensus_224b1349252bcb32, []int{4}
+func (m *GetVotesRequest) Reset()         { *m = GetVotesRequest{} }
+func (m *GetVotesRequest) String() string { return proto.CompactTextString(m) }
+func (*GetVotesRequest) ProtoMessage()    {}
+func (*GetVotesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{20}
 }
-func (m *AcceptResult) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_AcceptResult.Unmarshal(m, b)
+func (m *GetVotesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetVotesRequest.Unmarshal(m, b)
 }
-func (m *AcceptResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_AcceptResult.Marshal(b, m, deterministic)
+func (m *GetVotesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetVotesRequest.Marshal(b, m, deterministic)
 }
-func (dst *AcceptResult) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_AcceptResult.Merge(dst, src)
+func (dst *GetVotesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetVotesRequest.Merge(dst, src)
 }
-func (m *AcceptResult) XXX_Size() int {
-	return xxx_messageInfo_AcceptResult.Size(m)
+func (m *GetVotesRequest) XXX_Size() int {
+	return xxx_messageInfo_GetVotesRequest.Size(m)
 }
-func (m *AcceptResult) XXX_DiscardUnknown() {
-	xxx_messageInfo_AcceptResult.DiscardUnknown(m)
+func (m *GetVotesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetVotesRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_AcceptResult proto.InternalMessageInfo
+var xxx_messageInfo_GetVotesRequest proto.InternalMessageInfo
+
+func (m *GetVotesRequest) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+type GetVotesResult struct {
+	Confirmation         *Confirmation `protobuf:"bytes,1,opt,name=confirmation,proto3" json:"confirmation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *GetVotesResult) Reset()         { *m = GetVotesResult{} }
+func (m *GetVotesResult) String() string { return proto.CompactTextString(m) }
+func (*GetVotesResult) ProtoMessage()    {}
+func (*GetVotesResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_consensus_c52a56b97a01f7a4, []int{21}
+}
+func (m *GetVotesResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetVotesResult.Unmarshal(m, b)
+}
+func (m *GetVotesResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetVotesResult.Marshal(b, m, deterministic)
+}
+func (dst *GetVotesResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetVotesResult.Merge(dst, src)
+}
+func (m *GetVotesResult) XXX_Size() int {
+	return xxx_messageInfo_GetVotesResult.Size(m)
+}
+func (m *GetVotesResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetVotesResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetVotesResult proto.InternalMessageInfo
+
+func (m *GetVotesResult) GetConfirmation() *Confirmation {
+	if m != nil {
+		return m.Confirmation
+	}
+	return nil
+}
 
 func init() {
 	proto.RegisterType((*VoteRequest)(nil), "VoteRequest")
@@ -260,6 +1058,25 @@ func init() {
 	proto.RegisterType((*VoteResult)(nil), "VoteResult")
 	proto.RegisterType((*AcceptRequest)(nil), "AcceptRequest")
 	proto.RegisterType((*AcceptResult)(nil), "AcceptResult")
+	proto.RegisterType((*PublishRequest)(nil), "PublishRequest")
+	proto.RegisterType((*PublishResult)(nil), "PublishResult")
+	proto.RegisterType((*GetFrontiersRequest)(nil), "GetFrontiersRequest")
+	proto.RegisterMapType((map[string]string)(nil), "GetFrontiersRequest.FrontiersEntry")
+	proto.RegisterType((*GetFrontiersResult)(nil), "GetFrontiersResult")
+	proto.RegisterMapType((map[string]string)(nil), "GetFrontiersResult.FrontiersEntry")
+	proto.RegisterType((*GetChainRequest)(nil), "GetChainRequest")
+	proto.RegisterType((*GetChainResult)(nil), "GetChainResult")
+	proto.RegisterType((*Peer)(nil), "Peer")
+	proto.RegisterType((*GetPeersRequest)(nil), "GetPeersRequest")
+	proto.RegisterType((*GetPeersResult)(nil), "GetPeersResult")
+	proto.RegisterType((*PingRequest)(nil), "PingRequest")
+	proto.RegisterType((*PingResult)(nil), "PingResult")
+	proto.RegisterType((*NodeInfo)(nil), "NodeInfo")
+	proto.RegisterType((*HandshakeRequest)(nil), "HandshakeRequest")
+	proto.RegisterType((*HandshakeResult)(nil), "HandshakeResult")
+	proto.RegisterType((*Confirmation)(nil), "Confirmation")
+	proto.RegisterType((*GetVotesRequest)(nil), "GetVotesRequest")
+	proto.RegisterType((*GetVotesResult)(nil), "GetVotesResult")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -276,6 +1093,13 @@ const _ = grpc.SupportPackageIsVersion4
 type ConsensusClient interface {
 	Vote(ctx context.Context, in *VoteRequest, opts ...grpc.CallOption) (*VoteResult, error)
 	Accept(ctx context.Context, in *AcceptRequest, opts ...grpc.CallOption) (*AcceptResult, error)
+	Publish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (*PublishResult, error)
+	GetFrontiers(ctx context.Context, in *GetFrontiersRequest, opts ...grpc.CallOption) (*GetFrontiersResult, error)
+	GetChain(ctx context.Context, in *GetChainRequest, opts ...grpc.CallOption) (*GetChainResult, error)
+	GetPeers(ctx context.Context, in *GetPeersRequest, opts ...grpc.CallOption) (*GetPeersResult, error)
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResult, error)
+	Handshake(ctx context.Context, in *HandshakeRequest, opts ...grpc.CallOption) (*HandshakeResult, error)
+	GetVotes(ctx context.Context, in *GetVotesRequest, opts ...grpc.CallOption) (*GetVotesResult, error)
 }
 
 type consensusClient struct {
@@ -304,10 +1128,80 @@ func (c *consensusClient) Accept(ctx context.Context, in *AcceptRequest, opts ..
 	return out, nil
 }
 
+func (c *consensusClient) Publish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (*PublishResult, error) {
+	out := new(PublishResult)
+	err := c.cc.Invoke(ctx, "/Consensus/Publish", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *consensusClient) GetFrontiers(ctx context.Context, in *GetFrontiersRequest, opts ...grpc.CallOption) (*GetFrontiersResult, error) {
+	out := new(GetFrontiersResult)
+	err := c.cc.Invoke(ctx, "/Consensus/GetFrontiers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *consensusClient) GetChain(ctx context.Context, in *GetChainRequest, opts ...grpc.CallOption) (*GetChainResult, error) {
+	out := new(GetChainResult)
+	err := c.cc.Invoke(ctx, "/Consensus/GetChain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *consensusClient) GetPeers(ctx context.Context, in *GetPeersRequest, opts ...grpc.CallOption) (*GetPeersResult, error) {
+	out := new(GetPeersResult)
+	err := c.cc.Invoke(ctx, "/Consensus/GetPeers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *consensusClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResult, error) {
+	out := new(PingResult)
+	err := c.cc.Invoke(ctx, "/Consensus/Ping", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *consensusClient) Handshake(ctx context.Context, in *HandshakeRequest, opts ...grpc.CallOption) (*HandshakeResult, error) {
+	out := new(HandshakeResult)
+	err := c.cc.Invoke(ctx, "/Consensus/Handshake", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *consensusClient) GetVotes(ctx context.Context, in *GetVotesRequest, opts ...grpc.CallOption) (*GetVotesResult, error) {
+	out := new(GetVotesResult)
+	err := c.cc.Invoke(ctx, "/Consensus/GetVotes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ConsensusServer is the server API for Consensus service.
 type ConsensusServer interface {
 	Vote(context.Context, *VoteRequest) (*VoteResult, error)
 	Accept(context.Context, *AcceptRequest) (*AcceptResult, error)
+	Publish(context.Context, *PublishRequest) (*PublishResult, error)
+	GetFrontiers(context.Context, *GetFrontiersRequest) (*GetFrontiersResult, error)
+	GetChain(context.Context, *GetChainRequest) (*GetChainResult, error)
+	GetPeers(context.Context, *GetPeersRequest) (*GetPeersResult, error)
+	Ping(context.Context, *PingRequest) (*PingResult, error)
+	Handshake(context.Context, *HandshakeRequest) (*HandshakeResult, error)
+	GetVotes(context.Context, *GetVotesRequest) (*GetVotesResult, error)
 }
 
 func RegisterConsensusServer(s *grpc.Server, srv ConsensusServer) {
@@ -350,6 +1244,132 @@ func _Consensus_Accept_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Consensus_Publish_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsensusServer).Publish(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Consensus/Publish",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsensusServer).Publish(ctx, req.(*PublishRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Consensus_GetFrontiers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFrontiersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsensusServer).GetFrontiers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Consensus/GetFrontiers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsensusServer).GetFrontiers(ctx, req.(*GetFrontiersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Consensus_GetChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsensusServer).GetChain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Consensus/GetChain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsensusServer).GetChain(ctx, req.(*GetChainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Consensus_GetPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsensusServer).GetPeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Consensus/GetPeers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsensusServer).GetPeers(ctx, req.(*GetPeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Consensus_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsensusServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Consensus/Ping",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsensusServer).Ping(ctx, req.(*PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Consensus_Handshake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandshakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsensusServer).Handshake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Consensus/Handshake",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsensusServer).Handshake(ctx, req.(*HandshakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Consensus_GetVotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVotesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsensusServer).GetVotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Consensus/GetVotes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsensusServer).GetVotes(ctx, req.(*GetVotesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Consensus_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "Consensus",
 	HandlerType: (*ConsensusServer)(nil),
@@ -362,34 +1382,100 @@ var _Consensus_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Accept",
 			Handler:    _Consensus_Accept_Handler,
 		},
+		{
+			MethodName: "Publish",
+			Handler:    _Consensus_Publish_Handler,
+		},
+		{
+			MethodName: "GetFrontiers",
+			Handler:    _Consensus_GetFrontiers_Handler,
+		},
+		{
+			MethodName: "GetChain",
+			Handler:    _Consensus_GetChain_Handler,
+		},
+		{
+			MethodName: "GetPeers",
+			Handler:    _Consensus_GetPeers_Handler,
+		},
+		{
+			MethodName: "Ping",
+			Handler:    _Consensus_Ping_Handler,
+		},
+		{
+			MethodName: "Handshake",
+			Handler:    _Consensus_Handshake_Handler,
+		},
+		{
+			MethodName: "GetVotes",
+			Handler:    _Consensus_GetVotes_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "consensus/consensus.proto",
 }
 
 func init() {
-	proto.RegisterFile("consensus/consensus.proto", fileDescriptor_consensus_224b1349252bcb32)
-}
-
-var fileDescriptor_consensus_224b1349252bcb32 = []byte{
-	// 298 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x52, 0x4f, 0x4f, 0xbb, 0x40,
-	0x10, 0x2d, 0x85, 0x92, 0x1f, 0x03, 0xe5, 0xb0, 0xbf, 0xc4, 0x6c, 0xd1, 0x03, 0x59, 0x0f, 0xd6,
-	0x98, 0x6c, 0x63, 0xfd, 0x04, 0xc6, 0xa3, 0x37, 0x42, 0xbc, 0x78, 0xa2, 0x30, 0x69, 0x48, 0xeb,
-	0x2e, 0xee, 0x2e, 0x4d, 0xfd, 0x0e, 0x7e, 0x68, 0xc3, 0x42, 0xa5, 0x1e, 0xbc, 0x7a, 0xdb, 0x79,
-	0xf3, 0xe7, 0xbd, 0x37, 0xb3, 0xb0, 0x28, 0xa5, 0xd0, 0x28, 0x74, 0xab, 0x57, 0xdf, 0x2f, 0xde,
-	0x28, 0x69, 0x64, 0x42, 0xf7, 0x58, 0x6d, 0x51, 0xad, 0x8c, 0x2a, 0x84, 0x2e, 0x4a, 0x53, 0x4b,
-	0xd1, 0x67, 0xd8, 0x1b, 0x84, 0x2f, 0xd2, 0x60, 0x86, 0xef, 0x2d, 0x6a, 0x43, 0xee, 0xc0, 0xd7,
-	0x28, 0xaa, 0xfc, 0x48, 0x9d, 0xd4, 0x59, 0x86, 0xeb, 0xff, 0xbc, 0xef, 0xe4, 0xf9, 0xd8, 0x99,
-	0x0d, 0x25, 0xe4, 0x1e, 0x02, 0x85, 0x25, 0xd6, 0x07, 0xcc, 0x8f, 0x74, 0xfa, 0x7b, 0xfd, 0x58,
-	0xc5, 0x2a, 0xf0, 0x3a, 0x3a, 0x12, 0xc3, 0x54, 0xee, 0x2c, 0xc7, 0xbf, 0x6c, 0x2a, 0x77, 0xe4,
-	0x02, 0x7c, 0x85, 0x85, 0x96, 0xc2, 0xce, 0x09, 0xb2, 0x21, 0xea, 0xf0, 0xa6, 0xdd, 0x3c, 0xe3,
-	0x07, 0x75, 0x53, 0x67, 0x19, 0x65, 0x43, 0x44, 0xae, 0x20, 0xd0, 0xf5, 0x56, 0x14, 0xa6, 0x55,
-	0x48, 0x3d, 0x9b, 0x1a, 0x01, 0x76, 0x03, 0xd0, 0x9b, 0xd2, 0xed, 0xde, 0x90, 0x05, 0x78, 0x07,
-	0x69, 0x70, 0x70, 0x34, 0xe3, 0x36, 0x65, 0x21, 0xf6, 0xe9, 0xc0, 0xfc, 0xb1, 0x2c, 0xb1, 0x31,
-	0x7f, 0xb4, 0x00, 0x72, 0x09, 0xb3, 0x8e, 0x59, 0x53, 0x37, 0x75, 0x47, 0x35, 0x3d, 0xc6, 0x62,
-	0x88, 0x4e, 0x6a, 0x3a, 0xe5, 0xeb, 0x57, 0x08, 0x9e, 0x4e, 0x97, 0x24, 0xd7, 0xc3, 0xea, 0x22,
-	0x7e, 0x76, 0xb0, 0x24, 0xe4, 0xa3, 0x53, 0x36, 0x21, 0xb7, 0xe0, 0xf7, 0x13, 0x48, 0xcc, 0x7f,
-	0x18, 0x4b, 0xe6, 0xfc, 0x7c, 0x34, 0x9b, 0x6c, 0x7c, 0xfb, 0x01, 0x1e, 0xbe, 0x02, 0x00, 0x00,
-	0xff, 0xff, 0xff, 0x4f, 0xce, 0x63, 0x37, 0x02, 0x00, 0x00,
+	proto.RegisterFile("consensus/consensus.proto", fileDescriptor_consensus_c52a56b97a01f7a4)
+}
+
+var fileDescriptor_consensus_c52a56b97a01f7a4 = []byte{
+	// 900 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x56, 0x4b, 0x6f, 0x23, 0x45,
+	0x10, 0xf6, 0x78, 0xfc, 0x9a, 0xb2, 0x3d, 0x0e, 0x9d, 0x15, 0x9a, 0x9d, 0x5d, 0x24, 0xab, 0xa3,
+	0x88, 0xc0, 0x42, 0x47, 0x1b, 0x2e, 0x08, 0xf6, 0xc0, 0x2a, 0x82, 0xdd, 0x15, 0x12, 0x8a, 0x66,
+	0x23, 0x38, 0x4f, 0xc6, 0xe5, 0xb8, 0x65, 0xbb, 0xdb, 0x4c, 0xb7, 0x43, 0xc2, 0x95, 0x2b, 0xdc,
+	0x10, 0x3f, 0x84, 0x33, 0x3f, 0x0e, 0x75, 0xcf, 0xdb, 0x09, 0xde, 0x13, 0x88, 0xdb, 0xd4, 0xa3,
+	0xab, 0xbe, 0xae, 0xae, 0xfa, 0x6a, 0xe0, 0x71, 0x22, 0x85, 0x42, 0xa1, 0xb6, 0xea, 0xb4, 0xfc,
+	0x62, 0x9b, 0x54, 0x6a, 0x19, 0x06, 0x2b, 0x9c, 0x5d, 0x63, 0x7a, 0xaa, 0xd3, 0x58, 0xa8, 0x38,
+	0xd1, 0x5c, 0x8a, 0xcc, 0x42, 0xd7, 0x30, 0xfc, 0x5e, 0x6a, 0x8c, 0xf0, 0xc7, 0x2d, 0x2a, 0x4d,
+	0x9e, 0x41, 0x4f, 0xa1, 0x98, 0x5d, 0xde, 0x06, 0xce, 0xd4, 0x39, 0x19, 0x9e, 0x1d, 0xb2, 0xec,
+	0x24, 0xbb, 0xac, 0x4e, 0x46, 0xb9, 0x0b, 0x79, 0x0e, 0x5e, 0x8a, 0x09, 0xf2, 0x1b, 0xbc, 0xbc,
+	0x0d, 0xda, 0xff, 0xec, 0x5f, 0x79, 0xd1, 0xbf, 0x1c, 0xe8, 0x98, 0x7c, 0xc4, 0x87, 0xb6, 0x5c,
+	0xda, 0x24, 0x83, 0xa8, 0x2d, 0x97, 0xe4, 0x7d, 0xe8, 0xa5, 0x18, 0x2b, 0x29, 0x6c, 0x20, 0x2f,
+	0xca, 0x25, 0xa3, 0xdf, 0x6c, 0xaf, 0xbe, 0xc5, 0xbb, 0xc0, 0x9d, 0x3a, 0x27, 0xa3, 0x28, 0x97,
+	0xc8, 0x53, 0xf0, 0x14, 0xbf, 0x16, 0xb1, 0xde, 0xa6, 0x18, 0x74, 0xac, 0xa9, 0x52, 0x90, 0x00,
+	0xfa, 0xc9, 0x22, 0xe6, 0xe2, 0xcd, 0x2c, 0xe8, 0xda, 0x70, 0x85, 0x48, 0x42, 0x18, 0x18, 0xf4,
+	0xaf, 0x63, 0xb5, 0x08, 0x7a, 0xd6, 0x54, 0xca, 0x64, 0x0a, 0xc3, 0x1c, 0xa9, 0x35, 0xf7, 0xad,
+	0xb9, 0xae, 0xa2, 0x1f, 0x02, 0x64, 0xd5, 0x52, 0xdb, 0x95, 0x26, 0x8f, 0xa1, 0x73, 0x23, 0x35,
+	0xe6, 0xa5, 0xea, 0x32, 0x6b, 0xb2, 0x2a, 0xfa, 0xab, 0x03, 0xe3, 0x97, 0x49, 0x82, 0x1b, 0xfd,
+	0x1f, 0x55, 0x96, 0x3c, 0x81, 0xae, 0xc9, 0xac, 0x02, 0x77, 0xea, 0x56, 0x68, 0x32, 0x1d, 0xf5,
+	0x61, 0x54, 0xa0, 0x31, 0xc8, 0xe9, 0x6f, 0x0e, 0xf8, 0x17, 0xdb, 0xab, 0x15, 0x57, 0x8b, 0xff,
+	0x05, 0xbe, 0x63, 0x18, 0x97, 0x70, 0x6c, 0x69, 0x1f, 0x41, 0x77, 0x29, 0xe4, 0x4f, 0x22, 0xef,
+	0x90, 0x4c, 0xa0, 0x7f, 0x38, 0x70, 0xf8, 0x0a, 0xf5, 0x37, 0xa9, 0x14, 0x9a, 0x63, 0xaa, 0x0a,
+	0xec, 0x2f, 0xc1, 0x9b, 0x17, 0xba, 0xc0, 0xb1, 0xf1, 0x8f, 0xd8, 0x03, 0x8e, 0xac, 0x54, 0x7c,
+	0x2d, 0x74, 0x7a, 0x17, 0x55, 0xa7, 0xc2, 0x17, 0xe0, 0x37, 0x8d, 0xe4, 0x00, 0xdc, 0x25, 0xde,
+	0x59, 0x00, 0x5e, 0x64, 0x3e, 0x0d, 0xa8, 0x9b, 0x78, 0xb5, 0xc5, 0xbc, 0x45, 0x33, 0xe1, 0x8b,
+	0xf6, 0xe7, 0x0e, 0xfd, 0xdd, 0x01, 0xd2, 0xcc, 0x67, 0x6f, 0xf1, 0xd5, 0x7d, 0x5c, 0x94, 0xdd,
+	0xf7, 0xfb, 0xd7, 0x60, 0xfd, 0x00, 0x93, 0x57, 0xa8, 0xcf, 0x4d, 0xeb, 0x17, 0xa5, 0x0a, 0xa0,
+	0x1f, 0xcf, 0x66, 0x29, 0x2a, 0x95, 0x87, 0x28, 0x44, 0x13, 0x26, 0x9e, 0x6b, 0x4c, 0x8b, 0x30,
+	0x56, 0x30, 0xda, 0x15, 0x5f, 0x73, 0x6d, 0xc7, 0x6f, 0x1c, 0x65, 0x02, 0x7d, 0x0b, 0x7e, 0x15,
+	0xd8, 0x5e, 0xf5, 0x18, 0x5c, 0x7d, 0x5b, 0x5c, 0xf2, 0xc1, 0x5e, 0x30, 0x76, 0x33, 0x7e, 0x02,
+	0x6f, 0xb5, 0x9d, 0xaf, 0x2c, 0x4f, 0x29, 0xd3, 0x17, 0xd0, 0xb9, 0x40, 0x4c, 0xf7, 0x40, 0x0c,
+	0x61, 0xb0, 0x8a, 0x95, 0x7e, 0x8b, 0x98, 0xd1, 0x84, 0x1b, 0x95, 0x32, 0x3d, 0xb6, 0x77, 0xbd,
+	0xc0, 0xea, 0xb5, 0x09, 0x81, 0xce, 0x3c, 0x95, 0xeb, 0x3c, 0x8a, 0xfd, 0xa6, 0x9f, 0x82, 0x5f,
+	0xb9, 0x59, 0xe4, 0x4f, 0xa0, 0xbb, 0xc1, 0xea, 0x81, 0xba, 0xcc, 0x18, 0xa3, 0x4c, 0x47, 0x9f,
+	0xc1, 0xf0, 0x82, 0x8b, 0xeb, 0x22, 0xe2, 0x53, 0xf0, 0x34, 0x5f, 0xa3, 0xd2, 0xf1, 0x7a, 0x63,
+	0xc3, 0xba, 0x51, 0xa5, 0xa0, 0x1f, 0x03, 0x64, 0xce, 0x36, 0xee, 0x7e, 0xdf, 0x9f, 0x61, 0xf0,
+	0x9d, 0x9c, 0xe1, 0x1b, 0x31, 0x97, 0x35, 0x8e, 0x73, 0x1a, 0x1c, 0x17, 0x40, 0xff, 0x06, 0x53,
+	0xc5, 0x73, 0x52, 0x1c, 0x47, 0x85, 0x58, 0xe7, 0x37, 0xb7, 0xc9, 0x6f, 0x14, 0x46, 0x49, 0xbc,
+	0x89, 0xaf, 0xf8, 0x8a, 0x6b, 0x8e, 0x2a, 0xe8, 0x4c, 0xdd, 0x13, 0x2f, 0x6a, 0xe8, 0xe8, 0x2f,
+	0x0e, 0x1c, 0xbc, 0x8e, 0xc5, 0x4c, 0x2d, 0xe2, 0x65, 0xc9, 0xfc, 0x1f, 0x40, 0x87, 0x8b, 0xb9,
+	0xcc, 0xa7, 0xdf, 0x63, 0x05, 0xba, 0xc8, 0xaa, 0x9b, 0xb7, 0x69, 0xef, 0xdc, 0xc6, 0x74, 0x89,
+	0x90, 0x22, 0xc1, 0x9c, 0xa4, 0x33, 0x61, 0x3f, 0x47, 0xd3, 0x05, 0x4c, 0x6a, 0x20, 0x6c, 0xc9,
+	0xde, 0x81, 0x21, 0x80, 0xbe, 0x42, 0x55, 0xd6, 0xc3, 0x8b, 0x0a, 0xb1, 0x99, 0xc9, 0xdd, 0xcd,
+	0xc4, 0x61, 0x74, 0x2e, 0xc5, 0x9c, 0xa7, 0xeb, 0xd8, 0x74, 0x62, 0x63, 0x07, 0x38, 0xfb, 0x77,
+	0x40, 0xfb, 0xde, 0x0e, 0x78, 0x17, 0x91, 0x99, 0x2e, 0x34, 0x9a, 0x7a, 0x17, 0x2e, 0xaa, 0x4c,
+	0xf6, 0x9b, 0x9e, 0x83, 0x5f, 0xb9, 0xd9, 0xab, 0x3f, 0x87, 0x51, 0x52, 0xc3, 0x98, 0x97, 0x60,
+	0xcc, 0xea, 0xc0, 0xa3, 0x86, 0xcb, 0xd9, 0x9f, 0x2e, 0x78, 0xe7, 0xc5, 0xa2, 0x27, 0x47, 0xf9,
+	0x62, 0x1d, 0xb1, 0xda, 0x3e, 0x0f, 0x87, 0xac, 0xda, 0x57, 0xb4, 0x45, 0x3e, 0x82, 0x5e, 0xb6,
+	0x07, 0x88, 0xcf, 0x1a, 0xeb, 0x29, 0x1c, 0xb3, 0xc6, 0x82, 0x68, 0x91, 0x4f, 0xa0, 0x9f, 0x53,
+	0x32, 0x99, 0xb0, 0xe6, 0xae, 0x08, 0x7d, 0xd6, 0x60, 0x6b, 0xda, 0x22, 0x5f, 0xc2, 0xa8, 0xce,
+	0x6b, 0xe4, 0xd1, 0x43, 0xf4, 0x1b, 0x1e, 0x3e, 0x40, 0x7e, 0xb4, 0x45, 0x4e, 0x61, 0x50, 0xb0,
+	0x09, 0x39, 0x60, 0x3b, 0x8c, 0x15, 0x4e, 0x58, 0x93, 0x6a, 0xca, 0x03, 0x76, 0x88, 0xb3, 0x03,
+	0xf5, 0xb1, 0x0f, 0x27, 0x35, 0x4d, 0x7e, 0xe0, 0x08, 0x3a, 0x66, 0x32, 0xc9, 0x88, 0xd5, 0xa6,
+	0x39, 0x1c, 0xb2, 0x6a, 0x5c, 0x69, 0x8b, 0x9c, 0x81, 0x57, 0x36, 0x24, 0x79, 0x8f, 0xed, 0x4e,
+	0x48, 0x78, 0xc0, 0x76, 0xfa, 0xb5, 0x44, 0x62, 0x1f, 0x32, 0x43, 0x52, 0x7f, 0xfa, 0x70, 0x52,
+	0xd3, 0x64, 0x07, 0xae, 0x7a, 0xf6, 0xb7, 0xeb, 0xb3, 0xbf, 0x07, 0x00, 0xcb, 0x15, 0x14, 0x26,
+	0xad, 0x09, 0x00, 0x00,
 }
@@ -24,7 +24,9 @@ var _ = Describe("Consensus", func() {
 		ld = tests.NewMockLedger(mockCtrl)
 		conAddr, err := address.NewAddressWithKeys()
 		Expect(err).To(BeNil())
-		con = consensus.NewConsensus(ld, conAddr)
+		c := consensus.NewConsensus(ld, conAddr, "test")
+		c.SetVoters(tests.VotersFunc(func(pubKey []byte) bool { return true }))
+		con = c
 
 		genesisTx, genesisAddr := tests.CreateGenesisTransaction(1000)
 
@@ -109,6 +111,10 @@ var _ = Describe("Consensus", func() {
 		Expect(vote.Vote.Reason).To(Equal(ledger.ErrSendReceiveTransactionsNotLinked.Error()))
 		Expect(vote.Vote.Signature).NotTo(BeNil())
 		Expect(crypto.VerifySignature(vote.Vote.Signature, vote.Vote.PubKey, vote.Vote.Hash())).To(BeTrue())
+		Expect(vote.Vote.VerifySignature()).To(BeTrue())
+
+		vote.Vote.Reason = "tampered"
+		Expect(vote.Vote.VerifySignature()).To(BeFalse())
 	})
 
 	It("Should accept voted transactions", func() {
@@ -116,7 +122,7 @@ var _ = Describe("Consensus", func() {
 
 		ld.EXPECT().Register(sendTx, receiveTx)
 
-		votes := [1]*consensus.Vote{{Ok: true}}
+		votes := [1]*consensus.Vote{tests.CreateVote(true, sendTx, receiveTx)}
 		request := &consensus.AcceptRequest{SendTx: sendTx, ReceiveTx: receiveTx, Votes: votes[:]}
 
 		vote, err := con.Accept(request)
@@ -124,10 +130,48 @@ var _ = Describe("Consensus", func() {
 		Expect(vote).NotTo(BeNil())
 	})
 
+	It("Should keep the votes of the accepted transactions", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().Register(sendTx, receiveTx)
+
+		vote := tests.CreateVote(true, sendTx, receiveTx)
+		request := &consensus.AcceptRequest{SendTx: sendTx, ReceiveTx: receiveTx, Votes: []*consensus.Vote{vote}}
+
+		_, err := con.Accept(request)
+		Expect(err).To(BeNil())
+
+		for _, hash := range []string{sendTx.Hash, receiveTx.Hash} {
+			confirmation, err := con.GetVotes(hash)
+			Expect(err).To(BeNil())
+			Expect(confirmation.SendHash).To(Equal(sendTx.Hash))
+			Expect(confirmation.ReceiveHash).To(Equal(receiveTx.Hash))
+			Expect(len(confirmation.Votes)).To(Equal(1))
+			Expect(confirmation.Votes[0].Signature).To(Equal(vote.Signature))
+			Expect(confirmation.Votes[0].VerifySignature()).To(BeTrue())
+		}
+	})
+
+	It("Should NOT keep the votes of the rejected transactions", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().Register(sendTx, receiveTx).Return(ledger.ErrSendReceiveTransactionsNotLinked)
+
+		request := &consensus.AcceptRequest{SendTx: sendTx, ReceiveTx: receiveTx,
+			Votes: []*consensus.Vote{tests.CreateVote(true, sendTx, receiveTx)}}
+
+		_, err := con.Accept(request)
+		Expect(err).To(Equal(ledger.ErrSendReceiveTransactionsNotLinked))
+
+		confirmation, err := con.GetVotes(receiveTx.Hash)
+		Expect(err).To(BeNil())
+		Expect(confirmation).To(BeNil())
+	})
+
 	It("Should NOT accept transactions if not total majority", func() {
 		defer mockCtrl.Finish()
 
-		votes := [2]*consensus.Vote{{Ok: true}, {Ok: false}}
+		votes := [2]*consensus.Vote{tests.CreateVote(true, sendTx, receiveTx), tests.CreateVote(false, sendTx, receiveTx)}
 		request := &consensus.AcceptRequest{SendTx: sendTx, ReceiveTx: receiveTx, Votes: votes[:]}
 
 		vote, err := con.Accept(request)
@@ -146,12 +190,88 @@ var _ = Describe("Consensus", func() {
 		Expect(vote).To(BeNil())
 	})
 
+	It("Should NOT accept transactions with less votes than the quorum", func() {
+		defer mockCtrl.Finish()
+
+		conAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+		c := consensus.NewConsensus(ld, conAddr, "test")
+		c.SetVoters(tests.VotersFunc(func(pubKey []byte) bool { return true }))
+		c.SetQuorum(2)
+
+		request := &consensus.AcceptRequest{SendTx: sendTx, ReceiveTx: receiveTx,
+			Votes: []*consensus.Vote{tests.CreateVote(true, sendTx, receiveTx)}}
+
+		result, err := c.Accept(request)
+		Expect(err).To(Equal(consensus.ErrNotEnoughVotes))
+		Expect(result).To(BeNil())
+
+		ld.EXPECT().Register(sendTx, receiveTx)
+		request.Votes = append(request.Votes, tests.CreateVote(true, sendTx, receiveTx))
+
+		result, err = c.Accept(request)
+		Expect(err).To(BeNil())
+		Expect(result).NotTo(BeNil())
+	})
+
+	It("Should NOT accept transactions with invalid votes", func() {
+		defer mockCtrl.Finish()
+
+		vote := tests.CreateVote(false, sendTx, receiveTx)
+		vote.Ok = true
+		votes := [1]*consensus.Vote{vote}
+		request := &consensus.AcceptRequest{SendTx: sendTx, ReceiveTx: receiveTx, Votes: votes[:]}
+
+		result, err := con.Accept(request)
+		Expect(err).To(Equal(consensus.ErrInvalidVote))
+		Expect(result).To(BeNil())
+	})
+
+	It("Should NOT accept transactions with votes of unknown nodes", func() {
+		defer mockCtrl.Finish()
+
+		conAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+		c := consensus.NewConsensus(ld, conAddr, "test")
+		c.SetVoters(tests.VotersFunc(func(pubKey []byte) bool { return false }))
+
+		request := &consensus.AcceptRequest{SendTx: sendTx, ReceiveTx: receiveTx,
+			Votes: []*consensus.Vote{tests.CreateVote(true, sendTx, receiveTx)}}
+
+		result, err := c.Accept(request)
+		Expect(err).To(Equal(consensus.ErrUnknownVoter))
+		Expect(result).To(BeNil())
+	})
+
+	It("Should NOT accept transactions with votes on another transfer", func() {
+		defer mockCtrl.Finish()
+
+		vote := tests.CreateVote(true, receiveTx, sendTx)
+		request := &consensus.AcceptRequest{SendTx: sendTx, ReceiveTx: receiveTx, Votes: []*consensus.Vote{vote}}
+
+		result, err := con.Accept(request)
+		Expect(err).To(Equal(consensus.ErrInvalidVote))
+		Expect(result).To(BeNil())
+	})
+
+	It("Should NOT accept transactions with more than one vote from the same node", func() {
+		defer mockCtrl.Finish()
+
+		vote := tests.CreateVote(true, sendTx, receiveTx)
+		votes := [2]*consensus.Vote{vote, vote}
+		request := &consensus.AcceptRequest{SendTx: sendTx, ReceiveTx: receiveTx, Votes: votes[:]}
+
+		result, err := con.Accept(request)
+		Expect(err).To(Equal(consensus.ErrDuplicatedVote))
+		Expect(result).To(BeNil())
+	})
+
 	It("Should NOT accept transactions if ledger rejects it", func() {
 		defer mockCtrl.Finish()
 
 		ld.EXPECT().Register(sendTx, receiveTx).Return(ledger.ErrSendReceiveTransactionsNotLinked)
 
-		votes := [1]*consensus.Vote{{Ok: true}}
+		votes := [1]*consensus.Vote{tests.CreateVote(true, sendTx, receiveTx)}
 		request := &consensus.AcceptRequest{SendTx: sendTx, ReceiveTx: receiveTx, Votes: votes[:]}
 
 		vote, err := con.Accept(request)
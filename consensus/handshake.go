@@ -0,0 +1,201 @@
+package consensus
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/msaldanha/realChain/crypto"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/msaldanha/realChain/keypair"
+	"golang.org/x/net/context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ErrInvalidHandshake       = errors.Error("invalid handshake signature")
+	ErrStaleHandshake         = errors.Error("stale handshake")
+	ErrReplayedHandshake      = errors.Error("replayed handshake")
+	ErrChainIdMismatch        = errors.Error("peer chain id differs from local chain id")
+	ErrUnsupportedProtocol    = errors.Error("unsupported protocol version")
+	ErrHandshakeNonceMismatch = errors.Error("handshake result does not answer the request")
+)
+
+const (
+	ProtocolVersion    = 1
+	MinProtocolVersion = 1
+
+	// SessionKey is the metadata key that carries the session created by a handshake.
+	SessionKey = "realchain-session"
+
+	CapabilityVoting       = "voting"
+	CapabilityGossip       = "gossip"
+	CapabilitySync         = "sync"
+	CapabilityPeerExchange = "peerexchange"
+)
+
+const (
+	handshakeMaxAge = time.Minute
+	nonceSize       = 32
+)
+
+// Capabilities are the capabilities of this node version.
+var Capabilities = []string{CapabilityVoting, CapabilityGossip, CapabilitySync, CapabilityPeerExchange}
+
+// Identity proves the ownership of the node key to the peers and checks that they own theirs. In a handshake
+// the requesting node sends its node info, a timestamp and a nonce signed with its key. The answering node
+// replies with its own node info and a new session, signed along with the requesting node nonce, so both sides
+// know the other owns the key it claims.
+type Identity struct {
+	keys    *keypair.KeyPair
+	chainId string
+	mtx     sync.Mutex
+	nonces  map[string]time.Time
+}
+
+func NewIdentity(keys *keypair.KeyPair, chainId string) *Identity {
+	return &Identity{keys: keys, chainId: chainId, nonces: make(map[string]time.Time)}
+}
+
+// Info returns the node info of this node.
+func (i *Identity) Info() *NodeInfo {
+	return &NodeInfo{PubKey: i.keys.PublicKey, Version: ProtocolVersion, ChainId: i.chainId,
+		Capabilities: Capabilities}
+}
+
+// Handshake runs a handshake with peer and returns the peer node info and the session to use in the calls to it.
+func (i *Identity) Handshake(ctx context.Context, peer ConsensusClient) (*NodeInfo, string, error) {
+	request := &HandshakeRequest{Info: i.Info(), Timestamp: time.Now().UnixNano(), Nonce: newNonce()}
+	err := i.sign(request.Hash(), &request.Signature)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result, err := peer.Handshake(ctx, request)
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = i.verifyInfo(result.Info)
+	if err != nil {
+		return nil, "", err
+	}
+	if !crypto.VerifySignature(result.Signature, result.Info.PubKey, result.Hash(request.Nonce)) {
+		return nil, "", ErrHandshakeNonceMismatch
+	}
+	return result.Info, result.Session, nil
+}
+
+// Answer checks the handshake request of a peer and creates a new session for it.
+func (i *Identity) Answer(request *HandshakeRequest) (*HandshakeResult, error) {
+	err := i.verifyInfo(request.Info)
+	if err != nil {
+		return nil, err
+	}
+
+	if !crypto.VerifySignature(request.Signature, request.Info.PubKey, request.Hash()) {
+		return nil, ErrInvalidHandshake
+	}
+
+	age := time.Since(time.Unix(0, request.Timestamp))
+	if age > handshakeMaxAge || age < -handshakeMaxAge {
+		return nil, ErrStaleHandshake
+	}
+
+	if !i.useNonce(request.Nonce) {
+		return nil, ErrReplayedHandshake
+	}
+
+	result := &HandshakeResult{Info: i.Info(), Session: hex.EncodeToString(newNonce())}
+	err = i.sign(result.Hash(request.Nonce), &result.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (i *Identity) verifyInfo(info *NodeInfo) error {
+	if info == nil || len(info.PubKey) == 0 {
+		return ErrInvalidHandshake
+	}
+	if info.Version < MinProtocolVersion {
+		return ErrUnsupportedProtocol
+	}
+	if info.ChainId != i.chainId {
+		return ErrChainIdMismatch
+	}
+	return nil
+}
+
+func (i *Identity) sign(hash []byte, signature *[]byte) error {
+	s, err := crypto.Sign(hash, i.keys.ToEcdsaPrivateKey())
+	if err != nil {
+		return err
+	}
+	*signature = s
+	return nil
+}
+
+// useNonce records a request nonce. It returns false if the nonce was already used.
+func (i *Identity) useNonce(nonce []byte) bool {
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+
+	now := time.Now()
+	for n, expiry := range i.nonces {
+		if now.After(expiry) {
+			delete(i.nonces, n)
+		}
+	}
+
+	key := hex.EncodeToString(nonce)
+	if _, ok := i.nonces[key]; ok {
+		return false
+	}
+	i.nonces[key] = now.Add(2 * handshakeMaxAge)
+	return true
+}
+
+func (m *NodeInfo) Hash() []byte {
+	version := []byte(strconv.FormatUint(uint64(m.Version), 10))
+	capabilities := []byte(strings.Join(m.Capabilities, ","))
+	hashableBytes := [][]byte{m.PubKey, version, []byte(m.ChainId), capabilities}
+	headers := bytes.Join(hashableBytes, []byte{})
+	hash := sha256.Sum256(headers)
+	return []byte(hex.EncodeToString(hash[:]))
+}
+
+// HasCapability tells if the node has the given capability.
+func (m *NodeInfo) HasCapability(capability string) bool {
+	for _, c := range m.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *HandshakeRequest) Hash() []byte {
+	timestamp := []byte(strconv.FormatInt(m.Timestamp, 10))
+	hashableBytes := [][]byte{m.Info.Hash(), timestamp, m.Nonce}
+	headers := bytes.Join(hashableBytes, []byte{})
+	hash := sha256.Sum256(headers)
+	return []byte(hex.EncodeToString(hash[:]))
+}
+
+// Hash returns the hash of the result for the request with the given nonce.
+func (m *HandshakeResult) Hash(nonce []byte) []byte {
+	hashableBytes := [][]byte{m.Info.Hash(), []byte(m.Session), nonce}
+	headers := bytes.Join(hashableBytes, []byte{})
+	hash := sha256.Sum256(headers)
+	return []byte(hex.EncodeToString(hash[:]))
+}
+
+func newNonce() []byte {
+	nonce := make([]byte, nonceSize)
+	_, _ = rand.Read(nonce)
+	return nonce
+}
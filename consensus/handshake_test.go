@@ -0,0 +1,133 @@
+package consensus_test
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/crypto"
+	"github.com/msaldanha/realChain/keypair"
+	"github.com/msaldanha/realChain/tests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+	"time"
+)
+
+var _ = Describe("Handshake", func() {
+
+	var mockCtrl *gomock.Controller
+	var peer *tests.MockConsensusClient
+	var keys *keypair.KeyPair
+	var peerKeys *keypair.KeyPair
+	var id *consensus.Identity
+	var peerId *consensus.Identity
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		peer = tests.NewMockConsensusClient(mockCtrl)
+
+		var err error
+		keys, err = keypair.New()
+		Expect(err).To(BeNil())
+		peerKeys, err = keypair.New()
+		Expect(err).To(BeNil())
+
+		id = consensus.NewIdentity(keys, "test")
+		peerId = consensus.NewIdentity(peerKeys, "test")
+	})
+
+	answer := func(answerer *consensus.Identity) func(ctx context.Context, request *consensus.HandshakeRequest) (*consensus.HandshakeResult, error) {
+		return func(ctx context.Context, request *consensus.HandshakeRequest) (*consensus.HandshakeResult, error) {
+			return answerer.Answer(request)
+		}
+	}
+
+	It("Should authenticate both nodes", func() {
+		defer mockCtrl.Finish()
+
+		var request *consensus.HandshakeRequest
+		peer.EXPECT().Handshake(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, r *consensus.HandshakeRequest) (*consensus.HandshakeResult, error) {
+				request = r
+				return peerId.Answer(r)
+			})
+
+		info, session, err := id.Handshake(context.Background(), peer)
+		Expect(err).To(BeNil())
+		Expect(session).NotTo(BeEmpty())
+		Expect(info.PubKey).To(Equal(peerKeys.PublicKey))
+		Expect(info.Version).To(Equal(uint32(consensus.ProtocolVersion)))
+		Expect(info.HasCapability(consensus.CapabilityVoting)).To(BeTrue())
+		Expect(request.Info.PubKey).To(Equal(keys.PublicKey))
+	})
+
+	It("Should reject peers of other chains", func() {
+		defer mockCtrl.Finish()
+
+		otherId := consensus.NewIdentity(peerKeys, "other")
+		peer.EXPECT().Handshake(gomock.Any(), gomock.Any()).DoAndReturn(answer(otherId))
+
+		_, _, err := id.Handshake(context.Background(), peer)
+		Expect(err).To(Equal(consensus.ErrChainIdMismatch))
+	})
+
+	It("Should reject requests with an invalid signature", func() {
+		defer mockCtrl.Finish()
+
+		peer.EXPECT().Handshake(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, r *consensus.HandshakeRequest) (*consensus.HandshakeResult, error) {
+				r.Info.PubKey = peerKeys.PublicKey
+				return peerId.Answer(r)
+			})
+
+		_, _, err := id.Handshake(context.Background(), peer)
+		Expect(err).To(Equal(consensus.ErrInvalidHandshake))
+	})
+
+	It("Should reject replayed and stale requests", func() {
+		defer mockCtrl.Finish()
+
+		var request *consensus.HandshakeRequest
+		peer.EXPECT().Handshake(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, r *consensus.HandshakeRequest) (*consensus.HandshakeResult, error) {
+				request = r
+				return peerId.Answer(r)
+			})
+
+		_, _, err := id.Handshake(context.Background(), peer)
+		Expect(err).To(BeNil())
+
+		_, err = peerId.Answer(request)
+		Expect(err).To(Equal(consensus.ErrReplayedHandshake))
+
+		request.Timestamp = time.Now().Add(-time.Hour).UnixNano()
+		request.Nonce = []byte("other nonce")
+		request.Signature, err = crypto.Sign(request.Hash(), keys.ToEcdsaPrivateKey())
+		Expect(err).To(BeNil())
+		_, err = peerId.Answer(request)
+		Expect(err).To(Equal(consensus.ErrStaleHandshake))
+	})
+
+	It("Should reject results that do not answer the request", func() {
+		defer mockCtrl.Finish()
+
+		peer.EXPECT().Handshake(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, r *consensus.HandshakeRequest) (*consensus.HandshakeResult, error) {
+				result, err := peerId.Answer(r)
+				result.Session = "other"
+				return result, err
+			})
+
+		_, _, err := id.Handshake(context.Background(), peer)
+		Expect(err).To(Equal(consensus.ErrHandshakeNonceMismatch))
+	})
+
+	It("Should reject unsupported protocol versions", func() {
+		defer mockCtrl.Finish()
+
+		request := &consensus.HandshakeRequest{Info: &consensus.NodeInfo{PubKey: keys.PublicKey, ChainId: "test"},
+			Timestamp: time.Now().UnixNano()}
+
+		_, err := peerId.Answer(request)
+		Expect(err).To(Equal(consensus.ErrUnsupportedProtocol))
+	})
+})
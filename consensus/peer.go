@@ -0,0 +1,14 @@
+package consensus
+
+import "github.com/golang/protobuf/proto"
+
+func (m *Peer) ToBytes() []byte {
+	data, _ := proto.Marshal(m)
+	return data
+}
+
+func NewPeerFromBytes(d []byte) *Peer {
+	peer := &Peer{}
+	_ = proto.Unmarshal(d, peer)
+	return peer
+}
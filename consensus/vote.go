@@ -6,12 +6,15 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"github.com/msaldanha/realChain/crypto"
+	"github.com/msaldanha/realChain/ledger"
 	"strconv"
 )
 
+// Hash returns the hash the vote is signed over, which binds the vote to the chain and to the transactions of
+// the transfer, so a vote cannot be reused for another transfer.
 func (m *Vote) Hash() []byte {
 	ok := []byte(strconv.FormatBool(m.Ok))
-	hashableBytes := [][]byte{ok, []byte(m.Reason)}
+	hashableBytes := [][]byte{ok, []byte(m.Reason), []byte(m.ChainId), []byte(m.SendHash), []byte(m.ReceiveHash)}
 	headers := bytes.Join(hashableBytes, []byte{})
 	hash := sha256.Sum256(headers)
 	return []byte(hex.EncodeToString(hash[:]))
@@ -25,3 +28,16 @@ func (m *Vote) Sign(privateKey *ecdsa.PrivateKey) error {
 	m.Signature = s
 	return nil
 }
+
+func (m *Vote) VerifySignature() bool {
+	if len(m.Signature) == 0 || len(m.PubKey) == 0 {
+		return false
+	}
+	return crypto.VerifySignature(m.Signature, m.PubKey, m.Hash())
+}
+
+// IsFor tells if the vote is on the transfer of sendTx and receiveTx in the chain chainId.
+func (m *Vote) IsFor(chainId string, sendTx, receiveTx *ledger.Transaction) bool {
+	return sendTx != nil && receiveTx != nil && m.ChainId == chainId && m.SendHash == sendTx.Hash &&
+		m.ReceiveHash == receiveTx.Hash
+}
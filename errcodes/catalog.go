@@ -0,0 +1,73 @@
+package errcodes
+
+import (
+	"github.com/msaldanha/realChain/address"
+	"github.com/msaldanha/realChain/auth"
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/ratelimit"
+	"google.golang.org/grpc/codes"
+)
+
+// The catalog of the errors sent over the wire. Numbers are grouped by package: 1xxx for the ledger, 2xxx for
+// the consensus, 3xxx for the server, 4xxx for the addresses, 5xxx for the rate limits and 6xxx for the API
+// tokens. Packages that import this one, such as the server, register their errors themselves. Never reuse a
+// number or a code.
+func init() {
+	Register(ledger.ErrLedgerAlreadyInitialized, 1001, "LEDGER_ALREADY_INITIALIZED", codes.AlreadyExists)
+	Register(ledger.ErrNotEnoughFunds, 1002, "NOT_ENOUGH_FUNDS", codes.FailedPrecondition)
+	Register(ledger.ErrInvalidTransactionSignature, 1003, "INVALID_TRANSACTION_SIGNATURE", codes.InvalidArgument)
+	Register(ledger.ErrInvalidTransactionHash, 1004, "INVALID_TRANSACTION_HASH", codes.InvalidArgument)
+	Register(ledger.ErrTransactionAlreadyInLedger, 1005, "TRANSACTION_ALREADY_IN_LEDGER", codes.AlreadyExists)
+	Register(ledger.ErrTransactionNotFound, 1006, "TRANSACTION_NOT_FOUND", codes.NotFound)
+	Register(ledger.ErrPreviousTransactionNotFound, 1007, "PREVIOUS_TRANSACTION_NOT_FOUND", codes.NotFound)
+	Register(ledger.ErrHeadTransactionNotFound, 1008, "HEAD_TRANSACTION_NOT_FOUND", codes.NotFound)
+	Register(ledger.ErrPreviousTransactionIsNotHead, 1009, "PREVIOUS_TRANSACTION_IS_NOT_HEAD", codes.Aborted)
+	Register(ledger.ErrSendTransactionIsNotPending, 1010, "SEND_TRANSACTION_IS_NOT_PENDING", codes.Aborted)
+	Register(ledger.ErrOpenTransactionNotFound, 1011, "OPEN_TRANSACTION_NOT_FOUND", codes.NotFound)
+	Register(ledger.ErrAddressDoesNotMatchPubKey, 1012, "ADDRESS_DOES_NOT_MATCH_PUBKEY", codes.InvalidArgument)
+	Register(ledger.ErrSendReceiveTransactionsNotLinked, 1013, "SEND_RECEIVE_NOT_LINKED", codes.InvalidArgument)
+	Register(ledger.ErrSendReceiveTransactionsCantBeSameAddress, 1014, "SEND_RECEIVE_SAME_ADDRESS", codes.InvalidArgument)
+	Register(ledger.ErrSentAmountDiffersFromReceivedAmount, 1015, "SENT_AMOUNT_DIFFERS_FROM_RECEIVED", codes.InvalidArgument)
+	Register(ledger.ErrInvalidReceiveTransaction, 1016, "INVALID_RECEIVE_TRANSACTION", codes.InvalidArgument)
+	Register(ledger.ErrInvalidSendTransaction, 1017, "INVALID_SEND_TRANSACTION", codes.InvalidArgument)
+	Register(ledger.ErrTransactionIsNotHead, 1018, "TRANSACTION_IS_NOT_HEAD", codes.Aborted)
+	Register(ledger.ErrInvalidTransactionType, 1019, "INVALID_TRANSACTION_TYPE", codes.InvalidArgument)
+	Register(ledger.ErrInvalidTransactionTimestamp, 1020, "INVALID_TRANSACTION_TIMESTAMP", codes.InvalidArgument)
+	Register(ledger.ErrTransactionAddressCantBeEmpty, 1021, "TRANSACTION_ADDRESS_EMPTY", codes.InvalidArgument)
+	Register(ledger.ErrPreviousTransactionCantBeEmpty, 1022, "PREVIOUS_TRANSACTION_EMPTY", codes.InvalidArgument)
+	Register(ledger.ErrTransactionSignatureCantBeEmpty, 1023, "TRANSACTION_SIGNATURE_EMPTY", codes.InvalidArgument)
+	Register(ledger.ErrTransactionPowNonceCantBeZero, 1024, "TRANSACTION_POW_NONCE_ZERO", codes.InvalidArgument)
+	Register(ledger.ErrTransactionHashCantBeEmpty, 1025, "TRANSACTION_HASH_EMPTY", codes.InvalidArgument)
+	Register(ledger.ErrTransactionLinkCantBeEmpty, 1026, "TRANSACTION_LINK_EMPTY", codes.InvalidArgument)
+	Register(ledger.ErrDestinationNotFound, 1027, "DESTINATION_NOT_FOUND", codes.NotFound)
+	Register(ledger.ErrSourceNotFound, 1028, "SOURCE_NOT_FOUND", codes.NotFound)
+	Register(ledger.ErrInvalidSourceType, 1029, "INVALID_SOURCE_TYPE", codes.InvalidArgument)
+	Register(ledger.ErrPubKeyCantBeEmpty, 1030, "TRANSACTION_PUBKEY_EMPTY", codes.InvalidArgument)
+	Register(ledger.ErrSubscriberTooSlow, 1031, "SUBSCRIBER_TOO_SLOW", codes.ResourceExhausted)
+	Register(ledger.ErrStatementStartNotInChain, 1032, "STATEMENT_START_NOT_IN_CHAIN", codes.InvalidArgument)
+	Register(ledger.ErrHeightNotInChain, 1033, "HEIGHT_NOT_IN_CHAIN", codes.InvalidArgument)
+	Register(ledger.ErrInvalidBalanceHistory, 1034, "INVALID_BALANCE_HISTORY", codes.InvalidArgument)
+	Register(ledger.ErrTooManyBalanceBuckets, 1035, "TOO_MANY_BALANCE_BUCKETS", codes.InvalidArgument)
+
+	Register(consensus.ErrInvalidVotingResult, 2001, "INVALID_VOTING_RESULT", codes.InvalidArgument)
+	Register(consensus.ErrInvalidVote, 2002, "INVALID_VOTE", codes.InvalidArgument)
+	Register(consensus.ErrDuplicatedVote, 2003, "DUPLICATED_VOTE", codes.InvalidArgument)
+	Register(consensus.ErrInvalidHandshake, 2004, "INVALID_HANDSHAKE", codes.Unauthenticated)
+	Register(consensus.ErrStaleHandshake, 2005, "STALE_HANDSHAKE", codes.Unauthenticated)
+	Register(consensus.ErrReplayedHandshake, 2006, "REPLAYED_HANDSHAKE", codes.Unauthenticated)
+	Register(consensus.ErrChainIdMismatch, 2007, "CHAIN_ID_MISMATCH", codes.FailedPrecondition)
+	Register(consensus.ErrUnsupportedProtocol, 2008, "UNSUPPORTED_PROTOCOL", codes.FailedPrecondition)
+	Register(consensus.ErrHandshakeNonceMismatch, 2009, "HANDSHAKE_NONCE_MISMATCH", codes.Unauthenticated)
+	Register(consensus.ErrUnknownVoter, 2010, "UNKNOWN_VOTER", codes.PermissionDenied)
+	Register(consensus.ErrNotEnoughVotes, 2011, "NOT_ENOUGH_VOTES", codes.FailedPrecondition)
+
+	Register(address.ErrInvalidChecksum, 4001, "INVALID_ADDRESS_CHECKSUM", codes.InvalidArgument)
+
+	Register(ratelimit.ErrRateLimited, 5001, "RATE_LIMITED", codes.ResourceExhausted)
+	Register(ratelimit.ErrTooManyCalls, 5002, "TOO_MANY_CONCURRENT_CALLS", codes.ResourceExhausted)
+
+	Register(auth.ErrMissingToken, 6001, "MISSING_API_TOKEN", codes.Unauthenticated)
+	Register(auth.ErrInvalidToken, 6002, "INVALID_API_TOKEN", codes.Unauthenticated)
+	Register(auth.ErrPermissionDenied, 6003, "PERMISSION_DENIED", codes.PermissionDenied)
+}
@@ -0,0 +1,160 @@
+package errcodes
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+)
+
+//go:generate protoc -I.. errcodes/errcodes.proto --go_out=../
+
+// Entry is a catalogued error. Number and Code identify the error on the wire and must never change, even if
+// the error message does.
+type Entry struct {
+	Err    error
+	Number int32
+	Code   string
+	Status codes.Code
+}
+
+var (
+	mtx    sync.RWMutex
+	byErr  = make(map[error]Entry)
+	byCode = make(map[string]Entry)
+)
+
+// Register adds an error to the catalog. It panics if the error, its number or its code is already registered.
+func Register(err error, number int32, code string, statusCode codes.Code) {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if _, ok := byErr[err]; ok {
+		panic(fmt.Sprintf("error %q already registered", err))
+	}
+	if _, ok := byCode[code]; ok {
+		panic(fmt.Sprintf("error code %s already registered", code))
+	}
+	for _, entry := range byErr {
+		if entry.Number == number {
+			panic(fmt.Sprintf("error number %d already registered", number))
+		}
+	}
+
+	entry := Entry{Err: err, Number: number, Code: code, Status: statusCode}
+	byErr[err] = entry
+	byCode[code] = entry
+}
+
+// Lookup returns the catalog entry of err.
+func Lookup(err error) (Entry, bool) {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	entry, ok := byErr[err]
+	return entry, ok
+}
+
+// LookupCode returns the catalog entry with the given code.
+func LookupCode(code string) (Entry, bool) {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	entry, ok := byCode[code]
+	return entry, ok
+}
+
+// Code returns the gRPC status code of err: the code of a catalogued error, the code of a gRPC status error,
+// or codes.Unknown.
+func Code(err error) codes.Code {
+	if entry, ok := Lookup(err); ok {
+		return entry.Status
+	}
+	return status.Code(err)
+}
+
+// ToStatus converts a catalogued error to a gRPC status error whose details identify the error. Other errors are
+// returned unchanged.
+func ToStatus(err error) error {
+	entry, ok := Lookup(err)
+	if !ok {
+		return err
+	}
+
+	st, detailsErr := status.New(entry.Status, err.Error()).
+		WithDetails(&ErrorDetail{Code: entry.Code, Number: entry.Number})
+	if detailsErr != nil {
+		return status.Error(entry.Status, err.Error())
+	}
+	return st.Err()
+}
+
+// FromStatus converts a gRPC status error that identifies a catalogued error back to that error, so it can be
+// compared with the error value. Other errors are returned unchanged.
+func FromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st == nil {
+		return err
+	}
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*ErrorDetail); ok {
+			if entry, ok := LookupCode(d.Code); ok {
+				return entry.Err
+			}
+		}
+	}
+	return err
+}
+
+// UnaryServerInterceptor converts the catalogued errors returned by the unary calls to gRPC status errors.
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, ToStatus(err)
+	}
+	return resp, nil
+}
+
+// StreamServerInterceptor converts the catalogued errors returned by the streaming calls to gRPC status errors.
+func StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	return ToStatus(handler(srv, ss))
+}
+
+// UnaryClientInterceptor converts the gRPC status errors of catalogued errors back to the errors.
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return FromStatus(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+// StreamClientInterceptor converts the gRPC status errors of catalogued errors received in a stream back to
+// the errors.
+func StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+	streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, FromStatus(err)
+	}
+	return &clientStream{ClientStream: stream}, nil
+}
+
+// DialOptions returns the dial options that install the client interceptors.
+func DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(StreamClientInterceptor),
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	return FromStatus(s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	return FromStatus(s.ClientStream.SendMsg(m))
+}
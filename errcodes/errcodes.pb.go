@@ -0,0 +1,82 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: errcodes/errcodes.proto
+
+package errcodes
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ErrorDetail struct {
+	Code                 string   `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Number               int32    `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ErrorDetail) Reset()         { *m = ErrorDetail{} }
+func (m *ErrorDetail) String() string { return proto.CompactTextString(m) }
+func (*ErrorDetail) ProtoMessage()    {}
+func (*ErrorDetail) Descriptor() ([]byte, []int) {
+	return fileDescriptor_errcodes_6d7b8b867da71382, []int{0}
+}
+func (m *ErrorDetail) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ErrorDetail.Unmarshal(m, b)
+}
+func (m *ErrorDetail) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ErrorDetail.Marshal(b, m, deterministic)
+}
+func (dst *ErrorDetail) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ErrorDetail.Merge(dst, src)
+}
+func (m *ErrorDetail) XXX_Size() int {
+	return xxx_messageInfo_ErrorDetail.Size(m)
+}
+func (m *ErrorDetail) XXX_DiscardUnknown() {
+	xxx_messageInfo_ErrorDetail.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ErrorDetail proto.InternalMessageInfo
+
+func (m *ErrorDetail) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *ErrorDetail) GetNumber() int32 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*ErrorDetail)(nil), "errcodes.ErrorDetail")
+}
+
+func init() { proto.RegisterFile("errcodes/errcodes.proto", fileDescriptor_errcodes_6d7b8b867da71382) }
+
+var fileDescriptor_errcodes_6d7b8b867da71382 = []byte{
+	// 102 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x4f, 0x2d, 0x2a, 0x4a,
+	0xce, 0x4f, 0x49, 0x2d, 0xd6, 0x87, 0x31, 0xf4, 0x0a, 0x8a, 0xf2, 0x4b, 0xf2, 0x85, 0x38, 0x60,
+	0x7c, 0x25, 0x4b, 0x2e, 0x6e, 0xd7, 0xa2, 0xa2, 0xfc, 0x22, 0x97, 0xd4, 0x92, 0xc4, 0xcc, 0x1c,
+	0x21, 0x21, 0x2e, 0x16, 0x90, 0xb8, 0x04, 0xa3, 0x02, 0xa3, 0x06, 0x67, 0x10, 0x98, 0x2d, 0x24,
+	0xc6, 0xc5, 0x96, 0x57, 0x9a, 0x9b, 0x94, 0x5a, 0x24, 0xc1, 0xa4, 0xc0, 0xa8, 0xc1, 0x1a, 0x04,
+	0xe5, 0x39, 0x71, 0x45, 0xc1, 0x8d, 0x49, 0x62, 0x03, 0x9b, 0x6b, 0x0c, 0x18, 0x00, 0xbe, 0x95,
+	0xc1, 0x61, 0x72, 0x00, 0x00, 0x00,
+}
@@ -0,0 +1,13 @@
+package errcodes_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestErrcodes(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Errcodes Suite")
+}
@@ -0,0 +1,87 @@
+package errcodes_test
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/msaldanha/realChain/errcodes"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/server"
+	"github.com/msaldanha/realChain/tests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net"
+)
+
+var _ = Describe("Errcodes", func() {
+
+	It("Should convert catalogued errors to gRPC status errors and back", func() {
+		err := errcodes.ToStatus(ledger.ErrNotEnoughFunds)
+
+		st, ok := status.FromError(err)
+		Expect(ok).To(BeTrue())
+		Expect(st.Code()).To(Equal(codes.FailedPrecondition))
+		Expect(st.Message()).To(Equal(ledger.ErrNotEnoughFunds.Error()))
+		Expect(st.Details()).To(ConsistOf(&errcodes.ErrorDetail{Code: "NOT_ENOUGH_FUNDS", Number: 1002}))
+
+		Expect(errcodes.FromStatus(err)).To(Equal(ledger.ErrNotEnoughFunds))
+	})
+
+	It("Should leave the errors that are not catalogued unchanged", func() {
+		someErr := errors.Error("some error")
+		Expect(errcodes.ToStatus(someErr)).To(Equal(someErr))
+
+		statusErr := status.Error(codes.Internal, "some error")
+		Expect(errcodes.FromStatus(statusErr)).To(Equal(statusErr))
+		Expect(errcodes.FromStatus(nil)).To(BeNil())
+	})
+
+	It("Should return the gRPC code of an error", func() {
+		Expect(errcodes.Code(ledger.ErrTransactionAlreadyInLedger)).To(Equal(codes.AlreadyExists))
+		Expect(errcodes.Code(status.Error(codes.Unavailable, "down"))).To(Equal(codes.Unavailable))
+		Expect(errcodes.Code(errors.Error("some error"))).To(Equal(codes.Unknown))
+	})
+
+	It("Should not register an error, number or code twice", func() {
+		Expect(func() {
+			errcodes.Register(ledger.ErrNotEnoughFunds, 9001, "TEST_DUPLICATED_ERROR", codes.Internal)
+		}).To(Panic())
+		Expect(func() {
+			errcodes.Register(errors.Error("test error 1"), 1002, "TEST_DUPLICATED_NUMBER", codes.Internal)
+		}).To(Panic())
+		Expect(func() {
+			errcodes.Register(errors.Error("test error 2"), 9002, "NOT_ENOUGH_FUNDS", codes.Internal)
+		}).To(Panic())
+	})
+
+	It("Should return typed errors across the wire", func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		defer mockCtrl.Finish()
+
+		ld := tests.NewMockLedger(mockCtrl)
+		ld.EXPECT().GetTransaction("hash").Return(nil, ledger.ErrPreviousTransactionNotFound).Times(2)
+
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		srv := server.New(ld, nil, nil, nil, lis)
+		go srv.Run()
+
+		opts := append([]grpc.DialOption{grpc.WithInsecure()}, errcodes.DialOptions()...)
+		conn, err := grpc.Dial(lis.Addr().String(), opts...)
+		Expect(err).To(BeNil())
+		defer conn.Close()
+
+		_, err = ledger.NewLedgerClient(conn).GetTransaction(context.Background(), &ledger.GetTransactionRequest{Hash: "hash"})
+		Expect(err).To(Equal(ledger.ErrPreviousTransactionNotFound))
+
+		plain, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+		Expect(err).To(BeNil())
+		defer plain.Close()
+
+		_, err = ledger.NewLedgerClient(plain).GetTransaction(context.Background(), &ledger.GetTransactionRequest{Hash: "hash"})
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+})
@@ -0,0 +1,581 @@
+package gateway
+
+import (
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/msaldanha/realChain/auth"
+	"github.com/msaldanha/realChain/errcodes"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/logging"
+	"github.com/msaldanha/realChain/ratelimit"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ErrNotFound         = errors.Error("not found")
+	ErrMethodNotAllowed = errors.Error("method not allowed")
+	ErrInvalidRequest   = errors.Error("invalid request")
+	ErrInvalidType      = errors.Error("invalid transaction type")
+	ErrInvalidState     = errors.Error("invalid confirmation state")
+	ErrInvalidCursor    = errors.Error("invalid cursor")
+	ErrInvalidQuery     = errors.Error("invalid query parameter")
+)
+
+const (
+	jsonContentType   = "application/json"
+	streamContentType = "application/x-ndjson"
+)
+
+// statusCodes maps the gateway errors to HTTP status codes. The ledger service errors are mapped from their gRPC
+// status code in the error catalog.
+var statusCodes = map[error]int{
+	ErrNotFound:         http.StatusNotFound,
+	ErrMethodNotAllowed: http.StatusMethodNotAllowed,
+	ErrInvalidRequest:   http.StatusBadRequest,
+	ErrInvalidType:      http.StatusBadRequest,
+	ErrInvalidState:     http.StatusBadRequest,
+	ErrInvalidCursor:    http.StatusBadRequest,
+	ErrInvalidQuery:     http.StatusBadRequest,
+}
+
+var httpStatusCodes = map[codes.Code]int{
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.Aborted:            http.StatusConflict,
+	codes.FailedPrecondition: http.StatusUnprocessableEntity,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.Unimplemented:      http.StatusNotImplemented,
+}
+
+// Gateway serves the ledger service as an HTTP/JSON API. Messages are encoded with the protobuf JSON mapping
+// and the transactions streamed by the subscriptions are sent as newline delimited JSON.
+//
+//	GET  /v1/transactions/{hash}             GetTransaction
+//	GET  /v1/transactions/{hash}/receive     GetReceiveForSend
+//	POST /v1/transactions/verify             VerifyTransaction
+//	GET  /v1/addresses/{address}/last        GetLastTransaction
+//	GET  /v1/addresses/{address}/statement   GetAddressStatement, selected by the start, height, limit,
+//	                                         direction, type, from and to parameters
+//	GET  /v1/addresses/{address}/export      StreamAddressStatement, selected by the same parameters
+//	GET  /v1/addresses/{address}/incoming    GetIncoming, only the pending ones with the pending parameter
+//	GET  /v1/addresses/{address}/balance     GetBalanceAt, at the timestamp or height parameter
+//	GET  /v1/addresses/{address}/history     GetBalanceHistory, from the from to the to parameter by bucket, a
+//	                                         duration such as 24h
+//	POST /v1/register                        Register
+//	POST /v1/verify                          Verify
+//	GET  /v1/subscribe                       Subscribe, filtered by the address, type, state and cursor parameters
+type Gateway struct {
+	ls         ledger.LedgerServer
+	lis        net.Listener
+	marshaler  *jsonpb.Marshaler
+	srv        *http.Server
+	logger     *log.Entry
+	limiter    *ratelimit.Limiter
+	authorizer *auth.Authorizer
+}
+
+func New(ls ledger.LedgerServer, lis net.Listener) *Gateway {
+	g := &Gateway{ls: ls, lis: lis, marshaler: &jsonpb.Marshaler{},
+		logger: logging.Component(log.StandardLogger(), "gateway")}
+	g.srv = &http.Server{Handler: g.Handler()}
+	return g
+}
+
+// SetAuthorizer sets the authorizer of the API tokens of the requests, sent in the Authorization header as
+// "Bearer <token>" and checked as the ones of the gRPC calls of the same methods. Without an authorizer the
+// requests need no token.
+func (g *Gateway) SetAuthorizer(authorizer *auth.Authorizer) {
+	g.authorizer = authorizer
+}
+
+// SetLimiter sets the limiter of the requests, which are limited by API token or remote IP as the gRPC calls of
+// the same methods. Without a limiter the requests are not limited.
+func (g *Gateway) SetLimiter(limiter *ratelimit.Limiter) {
+	g.limiter = limiter
+}
+
+// SetLogger sets the logger of the gateway.
+func (g *Gateway) SetLogger(logger log.FieldLogger) {
+	g.logger = logging.Component(logger, "gateway")
+}
+
+// Run serves the API until the gateway is shut down.
+func (g *Gateway) Run() error {
+	err := g.srv.Serve(g.lis)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops accepting requests and waits for the in-flight ones to finish. If ctx is done first, the open
+// connections are closed and ctx.Err() is returned.
+func (g *Gateway) Shutdown(ctx context.Context) error {
+	err := g.srv.Shutdown(ctx)
+	if err != nil {
+		g.srv.Close()
+	}
+	return err
+}
+
+// Handler returns the HTTP handler of the API. Every request gets a request ID, which the ledger service logs.
+func (g *Gateway) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/transactions/", g.transactions)
+	mux.HandleFunc("/v1/addresses/", g.addresses)
+	mux.HandleFunc("/v1/register", g.register)
+	mux.HandleFunc("/v1/verify", g.verify)
+	mux.HandleFunc("/v1/subscribe", g.subscribe)
+	return logging.RequestIdHandler(mux)
+}
+
+func (g *Gateway) transactions(w http.ResponseWriter, r *http.Request) {
+	hash := strings.TrimPrefix(r.URL.Path, "/v1/transactions/")
+	if hash == "verify" {
+		request := &ledger.VerifyTransactionRequest{}
+		g.post(w, r, "VerifyTransaction", request, func(ctx context.Context) (proto.Message, error) {
+			return g.ls.VerifyTransaction(ctx, request)
+		})
+		return
+	}
+
+	if strings.HasSuffix(hash, "/receive") {
+		hash = strings.TrimSuffix(hash, "/receive")
+		g.get(w, r, "GetReceiveForSend", hash, func(ctx context.Context) (proto.Message, error) {
+			result, err := g.ls.GetReceiveForSend(ctx, &ledger.GetReceiveForSendRequest{Hash: hash})
+			if err == nil && result.Tx == nil {
+				return nil, ErrNotFound
+			}
+			return result, err
+		})
+		return
+	}
+
+	g.get(w, r, "GetTransaction", hash, func(ctx context.Context) (proto.Message, error) {
+		result, err := g.ls.GetTransaction(ctx, &ledger.GetTransactionRequest{Hash: hash})
+		if err == nil && result.Tx == nil {
+			return nil, ErrNotFound
+		}
+		return result, err
+	})
+}
+
+func (g *Gateway) addresses(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/addresses/"), "/")
+	if len(parts) != 2 {
+		g.writeError(w, ErrNotFound)
+		return
+	}
+	addr := parts[0]
+
+	switch parts[1] {
+	case "last":
+		g.get(w, r, "GetLastTransaction", addr, func(ctx context.Context) (proto.Message, error) {
+			result, err := g.ls.GetLastTransaction(ctx, &ledger.GetLastTransactionRequest{Address: addr})
+			if err == nil && result.Tx == nil {
+				return nil, ErrNotFound
+			}
+			return result, err
+		})
+	case "statement":
+		request, err := statementRequest(r, addr)
+		if err != nil {
+			g.writeError(w, err)
+			return
+		}
+		g.get(w, r, "GetAddressStatement", addr, func(ctx context.Context) (proto.Message, error) {
+			return g.ls.GetAddressStatement(ctx, request)
+		})
+	case "export":
+		g.export(w, r, addr)
+	case "incoming":
+		pending := false
+		if value := r.URL.Query().Get("pending"); value != "" {
+			var err error
+			pending, err = strconv.ParseBool(value)
+			if err != nil {
+				g.writeError(w, ErrInvalidQuery)
+				return
+			}
+		}
+		g.get(w, r, "GetIncoming", addr, func(ctx context.Context) (proto.Message, error) {
+			return g.ls.GetIncoming(ctx, &ledger.GetIncomingRequest{Address: addr, Pending: pending})
+		})
+	case "balance":
+		request, err := balanceAtRequest(r, addr)
+		if err != nil {
+			g.writeError(w, err)
+			return
+		}
+		g.get(w, r, "GetBalanceAt", addr, func(ctx context.Context) (proto.Message, error) {
+			return g.ls.GetBalanceAt(ctx, request)
+		})
+	case "history":
+		request, err := balanceHistoryRequest(r, addr)
+		if err != nil {
+			g.writeError(w, err)
+			return
+		}
+		g.get(w, r, "GetBalanceHistory", addr, func(ctx context.Context) (proto.Message, error) {
+			return g.ls.GetBalanceHistory(ctx, request)
+		})
+	default:
+		g.writeError(w, ErrNotFound)
+	}
+}
+
+func (g *Gateway) register(w http.ResponseWriter, r *http.Request) {
+	request := &ledger.RegisterRequest{}
+	g.post(w, r, "Register", request, func(ctx context.Context) (proto.Message, error) {
+		return g.ls.Register(ctx, request)
+	})
+}
+
+func (g *Gateway) verify(w http.ResponseWriter, r *http.Request) {
+	request := &ledger.VerifyRequest{}
+	g.post(w, r, "Verify", request, func(ctx context.Context) (proto.Message, error) {
+		return g.ls.Verify(ctx, request)
+	})
+}
+
+func (g *Gateway) subscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		g.writeMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	request, err := subscribeRequest(r)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+
+	release, err := g.admit(r, "Subscribe")
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	defer release()
+
+	w.Header().Set("Content-Type", streamContentType)
+	w.WriteHeader(http.StatusOK)
+	stream := &subscribeStream{jsonStream{ctx: r.Context(), w: w, marshaler: g.marshaler}}
+	stream.flush()
+
+	// The status is already sent, so an error can only end the stream.
+	err = g.ls.Subscribe(request, stream)
+	if err != nil && r.Context().Err() == nil {
+		logging.FromContext(r.Context(), g.logger).Debugf("Subscription ended: %s", err)
+	}
+}
+
+// export streams the statement of addr as JSON lines.
+func (g *Gateway) export(w http.ResponseWriter, r *http.Request, addr string) {
+	if r.Method != http.MethodGet {
+		g.writeMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	request, err := statementRequest(r, addr)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+
+	release, err := g.admit(r, "StreamAddressStatement")
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	defer release()
+
+	w.Header().Set("Content-Type", streamContentType)
+	w.WriteHeader(http.StatusOK)
+	stream := &exportStream{jsonStream{ctx: r.Context(), w: w, marshaler: g.marshaler}}
+	stream.flush()
+
+	// The status is already sent, so an error can only end the stream.
+	err = g.ls.StreamAddressStatement(request, stream)
+	if err != nil && r.Context().Err() == nil {
+		logging.FromContext(r.Context(), g.logger).Debugf("Export ended: %s", err)
+	}
+}
+
+// get answers a GET request whose path parameter is param with the result of fn, which calls method.
+func (g *Gateway) get(w http.ResponseWriter, r *http.Request, method, param string, fn func(ctx context.Context) (proto.Message, error)) {
+	if r.Method != http.MethodGet {
+		g.writeMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+	if param == "" || strings.Contains(param, "/") {
+		g.writeError(w, ErrNotFound)
+		return
+	}
+	g.call(w, r, method, fn)
+}
+
+// post decodes the body of a POST request into request and answers it with the result of fn, which calls
+// method. Bodies larger than the maximum message size of the limiter are refused.
+func (g *Gateway) post(w http.ResponseWriter, r *http.Request, method string, request proto.Message, fn func(ctx context.Context) (proto.Message, error)) {
+	if r.Method != http.MethodPost {
+		g.writeMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+	body := r.Body
+	if g.limiter != nil && g.limiter.MaxRecvSize() > 0 {
+		body = http.MaxBytesReader(w, r.Body, int64(g.limiter.MaxRecvSize()))
+	}
+	err := jsonpb.Unmarshal(body, request)
+	if err != nil {
+		g.writeError(w, ErrInvalidRequest)
+		return
+	}
+	g.call(w, r, method, fn)
+}
+
+func (g *Gateway) call(w http.ResponseWriter, r *http.Request, method string, fn func(ctx context.Context) (proto.Message, error)) {
+	release, err := g.admit(r, method)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	defer release()
+
+	result, err := fn(r.Context())
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", jsonContentType)
+	err = g.marshaler.Marshal(w, result)
+	if err != nil {
+		logging.FromContext(r.Context(), g.logger).Warnf("Failed to write %s response: %s", r.URL.Path, err)
+	}
+}
+
+// admit authorizes a request to call method and applies the limits of the method to it, returning the function
+// that ends it.
+func (g *Gateway) admit(r *http.Request, method string) (func(), error) {
+	var identity *auth.Identity
+	if g.authorizer != nil {
+		var err error
+		identity, err = g.authorizer.Authorize(auth.Token(r.Header.Get(auth.AuthorizationKey)), method)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if g.limiter == nil {
+		return func() {}, nil
+	}
+
+	client := ""
+	if identity != nil {
+		client = "token:" + identity.Name
+	} else {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		client = "ip:" + host
+	}
+	err := g.limiter.Allow(method, client)
+	if err != nil {
+		return nil, err
+	}
+	return g.limiter.Acquire(method)
+}
+
+func (g *Gateway) writeMethodNotAllowed(w http.ResponseWriter, allowed string) {
+	w.Header().Set("Allow", allowed)
+	g.writeError(w, ErrMethodNotAllowed)
+}
+
+func (g *Gateway) writeError(w http.ResponseWriter, err error) {
+	code, ok := statusCodes[err]
+	if !ok {
+		code = httpStatus(errcodes.Code(err))
+	}
+	w.Header().Set("Content-Type", jsonContentType)
+	w.WriteHeader(code)
+	_, _ = w.Write([]byte(`{"error":` + strconv.Quote(err.Error()) + "}\n"))
+}
+
+func httpStatus(code codes.Code) int {
+	if status, ok := httpStatusCodes[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+func subscribeRequest(r *http.Request) (*ledger.SubscribeRequest, error) {
+	query := r.URL.Query()
+	request := &ledger.SubscribeRequest{Addresses: query["address"]}
+
+	if txType := query.Get("type"); txType != "" {
+		value, ok := ledger.Transaction_Type_value[strings.ToUpper(txType)]
+		if !ok {
+			return nil, ErrInvalidType
+		}
+		request.Type = ledger.Transaction_Type(value)
+	}
+
+	if state := query.Get("state"); state != "" {
+		value, ok := ledger.ConfirmationState_value[strings.ToUpper(state)]
+		if !ok {
+			return nil, ErrInvalidState
+		}
+		request.State = ledger.ConfirmationState(value)
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		value, err := strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		request.Cursor = value
+		request.Resume = true
+	}
+	return request, nil
+}
+
+func statementRequest(r *http.Request, addr string) (*ledger.GetAddressStatementRequest, error) {
+	query := r.URL.Query()
+	request := &ledger.GetAddressStatementRequest{Address: addr, StartHash: query.Get("start")}
+
+	ints := &intParams{query: query}
+	request.StartHeight = ints.get("height", 64)
+	request.Limit = uint32(ints.get("limit", 32))
+	request.FromTimestamp = ints.get("from", 64)
+	request.ToTimestamp = ints.get("to", 64)
+	if ints.err != nil {
+		return nil, ErrInvalidQuery
+	}
+
+	if direction := query.Get("direction"); direction != "" {
+		value, ok := ledger.Direction_value[strings.ToUpper(direction)]
+		if !ok {
+			return nil, ErrInvalidQuery
+		}
+		request.Direction = ledger.Direction(value)
+	}
+
+	if txType := query.Get("type"); txType != "" {
+		value, ok := ledger.Transaction_Type_value[strings.ToUpper(txType)]
+		if !ok {
+			return nil, ErrInvalidType
+		}
+		request.Type = ledger.Transaction_Type(value)
+	}
+	return request, nil
+}
+
+func balanceAtRequest(r *http.Request, addr string) (*ledger.GetBalanceAtRequest, error) {
+	ints := &intParams{query: r.URL.Query()}
+	request := &ledger.GetBalanceAtRequest{Address: addr, Timestamp: ints.get("timestamp", 64),
+		Height: ints.get("height", 64)}
+	if ints.err != nil {
+		return nil, ErrInvalidQuery
+	}
+	return request, nil
+}
+
+func balanceHistoryRequest(r *http.Request, addr string) (*ledger.GetBalanceHistoryRequest, error) {
+	query := r.URL.Query()
+	ints := &intParams{query: query}
+	request := &ledger.GetBalanceHistoryRequest{Address: addr, FromTimestamp: ints.get("from", 64),
+		ToTimestamp: ints.get("to", 64)}
+	if ints.err != nil {
+		return nil, ErrInvalidQuery
+	}
+
+	bucket, err := time.ParseDuration(query.Get("bucket"))
+	if err != nil {
+		return nil, ErrInvalidQuery
+	}
+	request.Bucket = int64(bucket)
+	return request, nil
+}
+
+// intParams parses integer query parameters, which may not be negative, keeping the first error. Missing
+// parameters are 0.
+type intParams struct {
+	query url.Values
+	err   error
+}
+
+func (p *intParams) get(name string, bits int) int64 {
+	value := p.query.Get(name)
+	if value == "" || p.err != nil {
+		return 0
+	}
+	n, err := strconv.ParseInt(value, 10, bits)
+	if err == nil && n < 0 {
+		err = ErrInvalidQuery
+	}
+	p.err = err
+	return n
+}
+
+// jsonStream writes the messages of a stream to an HTTP response, one JSON message per line.
+type jsonStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	w         http.ResponseWriter
+	marshaler *jsonpb.Marshaler
+}
+
+// subscribeStream writes the transactions of a subscription to an HTTP response.
+type subscribeStream struct {
+	jsonStream
+}
+
+// exportStream writes the transactions of a statement to an HTTP response.
+type exportStream struct {
+	jsonStream
+}
+
+func (s *subscribeStream) Send(event *ledger.TransactionEvent) error {
+	return s.write(event)
+}
+
+func (s *exportStream) Send(tx *ledger.Transaction) error {
+	return s.write(tx)
+}
+
+func (s *jsonStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *jsonStream) write(msg proto.Message) error {
+	err := s.marshaler.Marshal(s.w, msg)
+	if err != nil {
+		return err
+	}
+	_, err = s.w.Write([]byte("\n"))
+	if err != nil {
+		return err
+	}
+	s.flush()
+	return nil
+}
+
+func (s *jsonStream) flush() {
+	if flusher, ok := s.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
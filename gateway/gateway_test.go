@@ -0,0 +1,375 @@
+package gateway_test
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/msaldanha/realChain/address"
+	"github.com/msaldanha/realChain/auth"
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/msaldanha/realChain/gateway"
+	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/ratelimit"
+	"github.com/msaldanha/realChain/tests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe("Gateway", func() {
+
+	var mockCtrl *gomock.Controller
+	var ls *tests.MockLedgerServer
+	var httpServer *httptest.Server
+	var sendTx *ledger.Transaction
+	var receiveTx *ledger.Transaction
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		ls = tests.NewMockLedgerServer(mockCtrl)
+		httpServer = httptest.NewServer(gateway.New(ls, nil).Handler())
+
+		genesisTx, genesisAddr := tests.CreateGenesisTransaction(1000)
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+		sendTx, err = ledger.CreateSendTransaction(genesisTx, genesisAddr, receiveAddr.Address, 300)
+		Expect(err).To(BeNil())
+		receiveTx, err = ledger.CreateReceiveTransaction(sendTx, 300, receiveAddr, nil)
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		httpServer.Close()
+		mockCtrl.Finish()
+	})
+
+	get := func(path string) (int, string) {
+		response, err := http.Get(httpServer.URL + path)
+		Expect(err).To(BeNil())
+		defer response.Body.Close()
+		body, err := ioutil.ReadAll(response.Body)
+		Expect(err).To(BeNil())
+		return response.StatusCode, string(body)
+	}
+
+	post := func(path string, body string) (int, string) {
+		response, err := http.Post(httpServer.URL+path, "application/json", bytes.NewBufferString(body))
+		Expect(err).To(BeNil())
+		defer response.Body.Close()
+		result, err := ioutil.ReadAll(response.Body)
+		Expect(err).To(BeNil())
+		return response.StatusCode, string(result)
+	}
+
+	It("Should get a transaction", func() {
+		ls.EXPECT().GetTransaction(gomock.Any(), &ledger.GetTransactionRequest{Hash: sendTx.Hash}).
+			Return(&ledger.GetTransactionResult{Tx: sendTx}, nil)
+
+		code, body := get("/v1/transactions/" + sendTx.Hash)
+		Expect(code).To(Equal(http.StatusOK))
+
+		result := &ledger.GetTransactionResult{}
+		Expect(jsonpb.UnmarshalString(body, result)).To(BeNil())
+		Expect(result.Tx.Hash).To(Equal(sendTx.Hash))
+		Expect(result.Tx.Type).To(Equal(ledger.Transaction_SEND))
+	})
+
+	It("Should return not found for unknown transactions", func() {
+		ls.EXPECT().GetTransaction(gomock.Any(), gomock.Any()).Return(&ledger.GetTransactionResult{}, nil)
+
+		code, body := get("/v1/transactions/unknown")
+		Expect(code).To(Equal(http.StatusNotFound))
+		Expect(body).To(MatchJSON(`{"error": "not found"}`))
+	})
+
+	It("Should get the last transaction of an address", func() {
+		ls.EXPECT().GetLastTransaction(gomock.Any(), &ledger.GetLastTransactionRequest{Address: receiveTx.Address}).
+			Return(&ledger.GetLastTransactionResult{Tx: receiveTx}, nil)
+
+		code, body := get("/v1/addresses/" + receiveTx.Address + "/last")
+		Expect(code).To(Equal(http.StatusOK))
+
+		result := &ledger.GetLastTransactionResult{}
+		Expect(jsonpb.UnmarshalString(body, result)).To(BeNil())
+		Expect(result.Tx.Hash).To(Equal(receiveTx.Hash))
+	})
+
+	It("Should get the statement of an address", func() {
+		ls.EXPECT().GetAddressStatement(gomock.Any(), &ledger.GetAddressStatementRequest{Address: receiveTx.Address}).
+			Return(&ledger.GetAddressStatementResult{Txs: []*ledger.Transaction{receiveTx}}, nil)
+
+		code, body := get("/v1/addresses/" + receiveTx.Address + "/statement")
+		Expect(code).To(Equal(http.StatusOK))
+
+		result := &ledger.GetAddressStatementResult{}
+		Expect(jsonpb.UnmarshalString(body, result)).To(BeNil())
+		Expect(result.Txs).To(HaveLen(1))
+		Expect(result.Txs[0].Hash).To(Equal(receiveTx.Hash))
+	})
+
+	It("Should select the statement page from the query parameters", func() {
+		request := &ledger.GetAddressStatementRequest{Address: receiveTx.Address, StartHash: sendTx.Hash,
+			StartHeight: 3, Limit: 10, Direction: ledger.Direction_BACKWARD, Type: ledger.Transaction_RECEIVE,
+			FromTimestamp: 100, ToTimestamp: 200}
+		ls.EXPECT().GetAddressStatement(gomock.Any(), request).
+			Return(&ledger.GetAddressStatementResult{Txs: []*ledger.Transaction{receiveTx}, NextHash: sendTx.Hash}, nil)
+
+		code, body := get("/v1/addresses/" + receiveTx.Address + "/statement?start=" + sendTx.Hash +
+			"&height=3&limit=10&direction=backward&type=receive&from=100&to=200")
+		Expect(code).To(Equal(http.StatusOK))
+
+		result := &ledger.GetAddressStatementResult{}
+		Expect(jsonpb.UnmarshalString(body, result)).To(BeNil())
+		Expect(result.NextHash).To(Equal(sendTx.Hash))
+	})
+
+	It("Should get the transfers to an address and the receive transaction of a send", func() {
+		ls.EXPECT().GetIncoming(gomock.Any(), &ledger.GetIncomingRequest{Address: receiveTx.Address, Pending: true}).
+			Return(&ledger.GetIncomingResult{Txs: []*ledger.Transaction{sendTx}}, nil)
+		ls.EXPECT().GetReceiveForSend(gomock.Any(), &ledger.GetReceiveForSendRequest{Hash: sendTx.Hash}).
+			Return(&ledger.GetReceiveForSendResult{Tx: receiveTx}, nil)
+		ls.EXPECT().GetReceiveForSend(gomock.Any(), &ledger.GetReceiveForSendRequest{Hash: "pending"}).
+			Return(&ledger.GetReceiveForSendResult{}, nil)
+
+		code, body := get("/v1/addresses/" + receiveTx.Address + "/incoming?pending=true")
+		Expect(code).To(Equal(http.StatusOK))
+		incoming := &ledger.GetIncomingResult{}
+		Expect(jsonpb.UnmarshalString(body, incoming)).To(BeNil())
+		Expect(incoming.Txs).To(HaveLen(1))
+		Expect(incoming.Txs[0].Hash).To(Equal(sendTx.Hash))
+
+		code, body = get("/v1/transactions/" + sendTx.Hash + "/receive")
+		Expect(code).To(Equal(http.StatusOK))
+		receive := &ledger.GetReceiveForSendResult{}
+		Expect(jsonpb.UnmarshalString(body, receive)).To(BeNil())
+		Expect(receive.Tx.Hash).To(Equal(receiveTx.Hash))
+
+		code, _ = get("/v1/transactions/pending/receive")
+		Expect(code).To(Equal(http.StatusNotFound))
+
+		code, _ = get("/v1/addresses/" + receiveTx.Address + "/incoming?pending=maybe")
+		Expect(code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("Should get the balance and the balance history of an address", func() {
+		ls.EXPECT().GetBalanceAt(gomock.Any(), &ledger.GetBalanceAtRequest{Address: receiveTx.Address, Height: 2}).
+			Return(&ledger.GetBalanceAtResult{Balance: &ledger.Balance{Height: 2, Balance: 300}}, nil)
+		request := &ledger.GetBalanceHistoryRequest{Address: receiveTx.Address, FromTimestamp: 100,
+			ToTimestamp: 200, Bucket: int64(time.Hour)}
+		ls.EXPECT().GetBalanceHistory(gomock.Any(), request).
+			Return(&ledger.GetBalanceHistoryResult{Balances: []*ledger.Balance{{Timestamp: 200, Balance: 300}}}, nil)
+
+		code, body := get("/v1/addresses/" + receiveTx.Address + "/balance?height=2")
+		Expect(code).To(Equal(http.StatusOK))
+		balance := &ledger.GetBalanceAtResult{}
+		Expect(jsonpb.UnmarshalString(body, balance)).To(BeNil())
+		Expect(balance.Balance.Balance).To(Equal(float64(300)))
+
+		code, body = get("/v1/addresses/" + receiveTx.Address + "/history?from=100&to=200&bucket=1h")
+		Expect(code).To(Equal(http.StatusOK))
+		history := &ledger.GetBalanceHistoryResult{}
+		Expect(jsonpb.UnmarshalString(body, history)).To(BeNil())
+		Expect(history.Balances).To(HaveLen(1))
+
+		code, _ = get("/v1/addresses/" + receiveTx.Address + "/history?from=100")
+		Expect(code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("Should export the statement of an address as JSON lines", func() {
+		ls.EXPECT().StreamAddressStatement(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(request *ledger.GetAddressStatementRequest, stream ledger.Ledger_StreamAddressStatementServer) error {
+				Expect(request.Address).To(Equal(receiveTx.Address))
+				Expect(request.Type).To(Equal(ledger.Transaction_SEND))
+
+				Expect(stream.Send(sendTx)).To(BeNil())
+				Expect(stream.Send(receiveTx)).To(BeNil())
+				return nil
+			})
+
+		response, err := http.Get(httpServer.URL + "/v1/addresses/" + receiveTx.Address + "/export?type=send")
+		Expect(err).To(BeNil())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+		scanner := bufio.NewScanner(response.Body)
+		for _, expected := range []*ledger.Transaction{sendTx, receiveTx} {
+			Expect(scanner.Scan()).To(BeTrue())
+			tx := &ledger.Transaction{}
+			Expect(jsonpb.UnmarshalString(scanner.Text(), tx)).To(BeNil())
+			Expect(tx.Hash).To(Equal(expected.Hash))
+		}
+		Expect(scanner.Scan()).To(BeFalse())
+	})
+
+	It("Should register transactions", func() {
+		request := &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx}
+		json, err := (&jsonpb.Marshaler{}).MarshalToString(request)
+		Expect(err).To(BeNil())
+
+		ls.EXPECT().Register(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx interface{}, r *ledger.RegisterRequest) (*ledger.RegisterResult, error) {
+				Expect(r.SendTx.Hash).To(Equal(sendTx.Hash))
+				Expect(r.ReceiveTx.Hash).To(Equal(receiveTx.Hash))
+				return &ledger.RegisterResult{}, nil
+			})
+
+		code, body := post("/v1/register", json)
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON("{}"))
+	})
+
+	It("Should map the ledger errors to HTTP status codes", func() {
+		ls.EXPECT().Verify(gomock.Any(), gomock.Any()).Return(nil, ledger.ErrNotEnoughFunds)
+		code, body := post("/v1/verify", "{}")
+		Expect(code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(body).To(MatchJSON(`{"error": "not enough funds"}`))
+
+		ls.EXPECT().VerifyTransaction(gomock.Any(), gomock.Any()).Return(nil, ledger.ErrTransactionAlreadyInLedger)
+		code, _ = post("/v1/transactions/verify", "{}")
+		Expect(code).To(Equal(http.StatusConflict))
+
+		ls.EXPECT().Verify(gomock.Any(), gomock.Any()).Return(nil, errors.Error("some error"))
+		code, _ = post("/v1/verify", "{}")
+		Expect(code).To(Equal(http.StatusInternalServerError))
+	})
+
+	It("Should reject invalid requests", func() {
+		code, _ := post("/v1/register", "not json")
+		Expect(code).To(Equal(http.StatusBadRequest))
+
+		code, _ = get("/v1/register")
+		Expect(code).To(Equal(http.StatusMethodNotAllowed))
+
+		code, _ = get("/v1/addresses/" + receiveTx.Address + "/unknown")
+		Expect(code).To(Equal(http.StatusNotFound))
+
+		code, _ = get("/v1/subscribe?type=unknown")
+		Expect(code).To(Equal(http.StatusBadRequest))
+
+		code, _ = get("/v1/addresses/" + receiveTx.Address + "/statement?limit=-1")
+		Expect(code).To(Equal(http.StatusBadRequest))
+
+		code, _ = get("/v1/addresses/" + receiveTx.Address + "/statement?direction=sideways")
+		Expect(code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("Should limit the requests of the clients", func() {
+		cfg := viper.New()
+		cfg.Set(config.CfgLimitMethods+".gettransaction.rate", 0.001)
+		cfg.Set(config.CfgLimitMethods+".gettransaction.burst", 1)
+		cfg.Set(config.CfgLimitMaxRecvSize, 16)
+		gw := gateway.New(ls, nil)
+		gw.SetLimiter(ratelimit.New(cfg))
+		limited := httptest.NewServer(gw.Handler())
+		defer limited.Close()
+
+		ls.EXPECT().GetTransaction(gomock.Any(), gomock.Any()).Return(&ledger.GetTransactionResult{Tx: sendTx}, nil)
+		response, err := http.Get(limited.URL + "/v1/transactions/" + sendTx.Hash)
+		Expect(err).To(BeNil())
+		response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+		response, err = http.Get(limited.URL + "/v1/transactions/" + sendTx.Hash)
+		Expect(err).To(BeNil())
+		body, err := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		Expect(err).To(BeNil())
+		Expect(response.StatusCode).To(Equal(http.StatusTooManyRequests))
+		Expect(string(body)).To(MatchJSON(`{"error": "rate limit exceeded"}`))
+
+		response, err = http.Post(limited.URL+"/v1/register", "application/json",
+			bytes.NewBufferString(`{"sendTx": {"address": "`+sendTx.Address+`"}}`))
+		Expect(err).To(BeNil())
+		response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+
+	It("Should refuse the requests whose API token may not call the method", func() {
+		cfg := viper.New()
+		cfg.Set(config.CfgAuth, true)
+		cfg.Set(config.CfgAuthTokens+".reader", []string{"reader-token"})
+		authorizer, err := auth.New(cfg)
+		Expect(err).To(BeNil())
+		gw := gateway.New(ls, nil)
+		gw.SetAuthorizer(authorizer)
+		secured := httptest.NewServer(gw.Handler())
+		defer secured.Close()
+
+		request := func(method, path, token string) int {
+			req, err := http.NewRequest(method, secured.URL+path, bytes.NewBufferString("{}"))
+			Expect(err).To(BeNil())
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			response, err := http.DefaultClient.Do(req)
+			Expect(err).To(BeNil())
+			response.Body.Close()
+			return response.StatusCode
+		}
+
+		Expect(request(http.MethodGet, "/v1/transactions/"+sendTx.Hash, "")).To(Equal(http.StatusUnauthorized))
+		Expect(request(http.MethodGet, "/v1/transactions/"+sendTx.Hash, "unknown")).To(Equal(http.StatusUnauthorized))
+
+		ls.EXPECT().GetTransaction(gomock.Any(), gomock.Any()).Return(&ledger.GetTransactionResult{Tx: sendTx}, nil)
+		Expect(request(http.MethodGet, "/v1/transactions/"+sendTx.Hash, "reader-token")).To(Equal(http.StatusOK))
+
+		Expect(request(http.MethodPost, "/v1/register", "reader-token")).To(Equal(http.StatusForbidden))
+	})
+
+	It("Should stream the subscribed transactions as JSON lines", func() {
+		ls.EXPECT().Subscribe(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(request *ledger.SubscribeRequest, stream ledger.Ledger_SubscribeServer) error {
+				Expect(request.Addresses).To(Equal([]string{receiveTx.Address}))
+				Expect(request.Type).To(Equal(ledger.Transaction_RECEIVE))
+				Expect(request.State).To(Equal(ledger.ConfirmationState_CONFIRMED))
+				Expect(request.Resume).To(BeTrue())
+				Expect(request.Cursor).To(Equal(uint64(7)))
+
+				err := stream.Send(&ledger.TransactionEvent{Cursor: 8, State: ledger.ConfirmationState_CONFIRMED, Tx: receiveTx})
+				Expect(err).To(BeNil())
+				return nil
+			})
+
+		response, err := http.Get(httpServer.URL + "/v1/subscribe?address=" + receiveTx.Address +
+			"&type=receive&state=confirmed&cursor=7")
+		Expect(err).To(BeNil())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+		scanner := bufio.NewScanner(response.Body)
+		Expect(scanner.Scan()).To(BeTrue())
+		event := &ledger.TransactionEvent{}
+		Expect(jsonpb.UnmarshalString(scanner.Text(), event)).To(BeNil())
+		Expect(event.Cursor).To(Equal(uint64(8)))
+		Expect(event.Tx.Hash).To(Equal(receiveTx.Hash))
+		Expect(scanner.Scan()).To(BeFalse())
+	})
+
+	It("Should stop serving when shut down", func() {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		gw := gateway.New(ls, lis)
+		done := make(chan error, 1)
+		go func() { done <- gw.Run() }()
+
+		ls.EXPECT().GetTransaction(gomock.Any(), gomock.Any()).Return(&ledger.GetTransactionResult{Tx: sendTx}, nil)
+		response, err := http.Get("http://" + lis.Addr().String() + "/v1/transactions/" + sendTx.Hash)
+		Expect(err).To(BeNil())
+		response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+		Expect(gw.Shutdown(context.Background())).To(BeNil())
+		Eventually(done).Should(Receive(BeNil()))
+
+		_, err = http.Get("http://" + lis.Addr().String() + "/v1/transactions/" + sendTx.Hash)
+		Expect(err).NotTo(BeNil())
+	})
+})
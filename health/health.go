@@ -0,0 +1,225 @@
+// Package health reports whether the node is alive and whether it is ready to take wallet traffic, over HTTP and
+// with the gRPC health checking protocol.
+package health
+
+import (
+	"encoding/json"
+	"github.com/msaldanha/realChain/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	ErrProbeTimedOut = errors.Error("probe timed out")
+	ErrShuttingDown  = errors.Error("shutting down")
+)
+
+const (
+	// LedgerService is the gRPC service whose health is the node readiness. The empty service name reports the
+	// node liveness.
+	LedgerService = "ledger.Ledger"
+
+	watchInterval = time.Second
+)
+
+// Probe checks a part of the node, returning an error if it is not healthy.
+type Probe func() error
+
+// Report is the result of the probes, keyed by probe name. Healthy probes are reported as "ok".
+type Report struct {
+	Ok     bool              `json:"ok"`
+	Checks map[string]string `json:"checks"`
+}
+
+type namedProbe struct {
+	name  string
+	probe Probe
+}
+
+// Health runs the liveness and readiness probes of the node. A node is alive if its liveness probes pass, and
+// ready if it is alive and its readiness probes pass. Probes that do not answer within the timeout fail.
+type Health struct {
+	timeout   time.Duration
+	mtx       sync.Mutex
+	liveness  []namedProbe
+	readiness []namedProbe
+	done      chan struct{}
+	closed    bool
+}
+
+func New(timeout time.Duration) *Health {
+	return &Health{timeout: timeout, done: make(chan struct{})}
+}
+
+// AddLiveness adds a probe to the liveness (and so to the readiness) of the node.
+func (h *Health) AddLiveness(name string, probe Probe) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.liveness = append(h.liveness, namedProbe{name: name, probe: probe})
+}
+
+// AddReadiness adds a probe to the readiness of the node.
+func (h *Health) AddReadiness(name string, probe Probe) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.readiness = append(h.readiness, namedProbe{name: name, probe: probe})
+}
+
+// Live runs the liveness probes.
+func (h *Health) Live() *Report {
+	h.mtx.Lock()
+	probes := append([]namedProbe{}, h.liveness...)
+	h.mtx.Unlock()
+	return h.run(probes)
+}
+
+// Ready runs the liveness and readiness probes. A node that is shutting down is not ready.
+func (h *Health) Ready() *Report {
+	h.mtx.Lock()
+	probes := append(append([]namedProbe{}, h.liveness...), h.readiness...)
+	closed := h.closed
+	h.mtx.Unlock()
+
+	if closed {
+		probes = append(probes, namedProbe{name: "node", probe: func() error { return ErrShuttingDown }})
+	}
+	return h.run(probes)
+}
+
+// Shutdown makes the node not ready and ends the gRPC health watches, so they do not hold up the server
+// shutdown.
+func (h *Health) Shutdown() {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if !h.closed {
+		h.closed = true
+		close(h.done)
+	}
+}
+
+// LiveHandler serves the liveness report, with status 200 if the node is alive and 503 if it is not.
+func (h *Health) LiveHandler() http.Handler {
+	return reportHandler(h.Live)
+}
+
+// ReadyHandler serves the readiness report, with status 200 if the node is ready and 503 if it is not.
+func (h *Health) ReadyHandler() http.Handler {
+	return reportHandler(h.Ready)
+}
+
+// Check answers the gRPC health checks: the empty service reports the liveness and LedgerService the
+// readiness of the node.
+func (h *Health) Check(ctx context.Context, request *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	report, err := h.report(request.Service)
+	if err != nil {
+		return nil, err
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: servingStatus(report)}, nil
+}
+
+// Watch sends the health of the service, then every change of it, until the client goes away or the health
+// shuts down.
+func (h *Health) Watch(request *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	if _, err := h.report(request.Service); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	last := grpc_health_v1.HealthCheckResponse_UNKNOWN
+	for {
+		report, _ := h.report(request.Service)
+		current := servingStatus(report)
+		if current != last {
+			err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: current})
+			if err != nil {
+				return err
+			}
+			last = current
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-h.done:
+			return stream.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING})
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Health) report(service string) (*Report, error) {
+	switch service {
+	case "":
+		return h.Live(), nil
+	case LedgerService:
+		return h.Ready(), nil
+	default:
+		return nil, status.Errorf(codes.NotFound, "unknown service %s", service)
+	}
+}
+
+// run runs the probes concurrently, failing the ones that do not answer within the timeout.
+func (h *Health) run(probes []namedProbe) *Report {
+	results := make([]error, len(probes))
+	wg := sync.WaitGroup{}
+	for i, p := range probes {
+		wg.Add(1)
+		go func(i int, probe Probe) {
+			defer wg.Done()
+			results[i] = h.runProbe(probe)
+		}(i, p.probe)
+	}
+	wg.Wait()
+
+	report := &Report{Ok: true, Checks: make(map[string]string)}
+	for i, p := range probes {
+		if results[i] != nil {
+			report.Ok = false
+			report.Checks[p.name] = results[i].Error()
+		} else {
+			report.Checks[p.name] = "ok"
+		}
+	}
+	return report
+}
+
+func (h *Health) runProbe(probe Probe) error {
+	result := make(chan error, 1)
+	go func() { result <- probe() }()
+
+	timer := time.NewTimer(h.timeout)
+	defer timer.Stop()
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		return ErrProbeTimedOut
+	}
+}
+
+func servingStatus(report *Report) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if report.Ok {
+		return grpc_health_v1.HealthCheckResponse_SERVING
+	}
+	return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+}
+
+func reportHandler(fn func() *Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := fn()
+		w.Header().Set("Content-Type", "application/json")
+		if report.Ok {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
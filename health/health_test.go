@@ -0,0 +1,145 @@
+package health_test
+
+import (
+	"encoding/json"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/msaldanha/realChain/health"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+type watchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan grpc_health_v1.HealthCheckResponse_ServingStatus
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *watchStream) Send(response *grpc_health_v1.HealthCheckResponse) error {
+	s.sent <- response.Status
+	return nil
+}
+
+var _ = Describe("Health", func() {
+
+	const ErrFailing = errors.Error("failing")
+
+	var h *health.Health
+
+	BeforeEach(func() {
+		h = health.New(100 * time.Millisecond)
+	})
+
+	get := func(handler http.Handler) (int, *health.Report) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+		report := &health.Report{}
+		Expect(json.NewDecoder(recorder.Body).Decode(report)).To(BeNil())
+		return recorder.Code, report
+	}
+
+	It("Should be alive and ready when the probes pass", func() {
+		h.AddLiveness("store", func() error { return nil })
+		h.AddReadiness("peers", func() error { return nil })
+
+		code, report := get(h.LiveHandler())
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(report).To(Equal(&health.Report{Ok: true, Checks: map[string]string{"store": "ok"}}))
+
+		code, report = get(h.ReadyHandler())
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(report).To(Equal(&health.Report{Ok: true, Checks: map[string]string{"store": "ok", "peers": "ok"}}))
+	})
+
+	It("Should be alive but not ready when a readiness probe fails", func() {
+		h.AddLiveness("store", func() error { return nil })
+		h.AddReadiness("peers", func() error { return ErrFailing })
+
+		code, _ := get(h.LiveHandler())
+		Expect(code).To(Equal(http.StatusOK))
+
+		code, report := get(h.ReadyHandler())
+		Expect(code).To(Equal(http.StatusServiceUnavailable))
+		Expect(report.Ok).To(BeFalse())
+		Expect(report.Checks).To(Equal(map[string]string{"store": "ok", "peers": ErrFailing.Error()}))
+	})
+
+	It("Should fail the probes that do not answer in time", func() {
+		block := make(chan struct{})
+		defer close(block)
+		h.AddLiveness("store", func() error {
+			<-block
+			return nil
+		})
+
+		start := time.Now()
+		report := h.Live()
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		Expect(report.Ok).To(BeFalse())
+		Expect(report.Checks["store"]).To(Equal(health.ErrProbeTimedOut.Error()))
+	})
+
+	It("Should not be ready once shut down", func() {
+		h.AddLiveness("store", func() error { return nil })
+		h.Shutdown()
+
+		Expect(h.Live().Ok).To(BeTrue())
+		report := h.Ready()
+		Expect(report.Ok).To(BeFalse())
+		Expect(report.Checks["node"]).To(Equal(health.ErrShuttingDown.Error()))
+	})
+
+	It("Should answer the gRPC health checks", func() {
+		h.AddLiveness("store", func() error { return nil })
+		h.AddReadiness("peers", func() error { return ErrFailing })
+
+		response, err := h.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		Expect(err).To(BeNil())
+		Expect(response.Status).To(Equal(grpc_health_v1.HealthCheckResponse_SERVING))
+
+		response, err = h.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: health.LedgerService})
+		Expect(err).To(BeNil())
+		Expect(response.Status).To(Equal(grpc_health_v1.HealthCheckResponse_NOT_SERVING))
+
+		_, err = h.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "unknown"})
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+
+	It("Should watch the readiness until shut down", func() {
+		h.AddLiveness("store", func() error { return nil })
+
+		stream := &watchStream{ctx: context.Background(),
+			sent: make(chan grpc_health_v1.HealthCheckResponse_ServingStatus, 10)}
+		done := make(chan error, 1)
+		go func() {
+			done <- h.Watch(&grpc_health_v1.HealthCheckRequest{Service: health.LedgerService}, stream)
+		}()
+
+		Eventually(stream.sent).Should(Receive(Equal(grpc_health_v1.HealthCheckResponse_SERVING)))
+		h.Shutdown()
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(stream.sent).To(Receive(Equal(grpc_health_v1.HealthCheckResponse_NOT_SERVING)))
+	})
+
+	It("Should end the watch when the client goes away", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := &watchStream{ctx: ctx, sent: make(chan grpc_health_v1.HealthCheckResponse_ServingStatus, 10)}
+		done := make(chan error, 1)
+		go func() { done <- h.Watch(&grpc_health_v1.HealthCheckRequest{}, stream) }()
+
+		Eventually(stream.sent).Should(Receive(Equal(grpc_health_v1.HealthCheckResponse_SERVING)))
+		cancel()
+		Eventually(done).Should(Receive(Equal(context.Canceled)))
+	})
+})
@@ -3,6 +3,7 @@ package keyvaluestore
 import (
 	"github.com/coreos/bbolt"
 	"github.com/msaldanha/realChain/errors"
+	"github.com/msaldanha/realChain/metrics"
 	"time"
 )
 
@@ -11,6 +12,9 @@ const (
 	ErrInvalidBucketName                = errors.Error("invalid bucket name")
 )
 
+var operations = metrics.NewHistogram("realchain_store_operation_duration_seconds",
+	"Duration of the bolt store operations, by bucket and operation.", metrics.DefaultBuckets, "bucket", "op")
+
 type BoltKeyValueStoreOptions struct {
 	BucketName string
 	DbFile     string
@@ -53,6 +57,7 @@ func (st *BoltKeyValueStore) Init(options interface{}) error {
 }
 
 func (st *BoltKeyValueStore) Put(key string, value []byte) (error) {
+	defer st.observe("put", time.Now())
 	return st.db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte(st.BucketName))
 		err := b.Put([]byte(key), value)
@@ -61,6 +66,7 @@ func (st *BoltKeyValueStore) Put(key string, value []byte) (error) {
 }
 
 func (st *BoltKeyValueStore) Get(key string) (ret []byte, ok bool, err error) {
+	defer st.observe("get", time.Now())
 	ok = false
 	ret = nil
 	err = st.db.View(func(tx *bolt.Tx) error {
@@ -77,6 +83,14 @@ func (st *BoltKeyValueStore) Get(key string) (ret []byte, ok bool, err error) {
 	return
 }
 
+func (st *BoltKeyValueStore) Delete(key string) (error) {
+	defer st.observe("delete", time.Now())
+	return st.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(st.BucketName))
+		return b.Delete([]byte(key))
+	})
+}
+
 func (st *BoltKeyValueStore) GetTip(key string) ([]byte, bool, error) {
 	return nil, false, nil
 }
@@ -110,6 +124,7 @@ func (st *BoltKeyValueStore) Size() (size int) {
 }
 
 func (st *BoltKeyValueStore) GetAll() ([][]byte, error) {
+	defer st.observe("getall", time.Now())
 	all := make([][]byte, 0)
 	err := st.db.View(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte(st.BucketName))
@@ -123,3 +138,27 @@ func (st *BoltKeyValueStore) GetAll() ([][]byte, error) {
 	})
 	return all, err
 }
+
+func (st *BoltKeyValueStore) ForEach(fn func(key string, value []byte) error) error {
+	defer st.observe("foreach", time.Now())
+	return st.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(st.BucketName))
+		return b.ForEach(func(k, v []byte) error {
+			value := make([]byte, len(v))
+			copy(value, v)
+			return fn(string(k), value)
+		})
+	})
+}
+
+// Close closes the bolt database, releasing its file lock.
+func (st *BoltKeyValueStore) Close() error {
+	if st.db == nil {
+		return nil
+	}
+	return st.db.Close()
+}
+
+func (st *BoltKeyValueStore) observe(op string, start time.Time) {
+	operations.Since(start, st.BucketName, op)
+}
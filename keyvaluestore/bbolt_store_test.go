@@ -56,6 +56,31 @@ var _ = Describe("BoltKeyValueStore", func() {
 		Expect(txChain[11].Type).To(Equal(ledger.Transaction_RECEIVE))
 		Expect(txChain[11].Balance).To(Equal(float64(1000)))
 	})
+
+	It("Should release the database file when closed", func() {
+		path, err := os.Getwd()
+		Expect(err).To(BeNil())
+
+		options := prepareOptions("TxChain", filepath.Join(path, "test.db"))
+		store := keyvaluestore.NewBoltKeyValueStore()
+		err = store.Init(options)
+		Expect(err).To(BeNil())
+		err = store.Put("key", []byte("value"))
+		Expect(err).To(BeNil())
+
+		err = store.Close()
+		Expect(err).To(BeNil())
+
+		reopened := keyvaluestore.NewBoltKeyValueStore()
+		err = reopened.Init(&keyvaluestore.BoltKeyValueStoreOptions{DbFile: options.DbFile, BucketName: "TxChain"})
+		Expect(err).To(BeNil())
+		defer reopened.Close()
+
+		value, ok, err := reopened.Get("key")
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal([]byte("value")))
+	})
 })
 
 func prepareOptions(bucketName, filepath string) *keyvaluestore.BoltKeyValueStoreOptions {
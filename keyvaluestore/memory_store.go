@@ -1,8 +1,15 @@
 package keyvaluestore
 
+import (
+	"sort"
+	"sync"
+)
+
+// MemoryKeyValueStore keeps the pairs in memory. It is safe for concurrent use.
 type MemoryKeyValueStore struct {
 	pairs map[string][]byte
 	tip []byte
+	mtx sync.RWMutex
 }
 
 func NewMemoryKeyValueStore() (*MemoryKeyValueStore) {
@@ -15,17 +22,30 @@ func (st *MemoryKeyValueStore) Init(options interface{}) (error) {
 }
 
 func (st *MemoryKeyValueStore) Put(key string, value []byte) (error) {
+	st.mtx.Lock()
+	defer st.mtx.Unlock()
 	st.tip = value
 	st.pairs[key] = value
 	return nil
 }
 
 func (st *MemoryKeyValueStore) Get(key string) ([]byte, bool, error) {
+	st.mtx.RLock()
+	defer st.mtx.RUnlock()
 	value, found := st.pairs[key]
 	return value, found, nil
 }
 
+func (st *MemoryKeyValueStore) Delete(key string) (error) {
+	st.mtx.Lock()
+	defer st.mtx.Unlock()
+	delete(st.pairs, key)
+	return nil
+}
+
 func (st *MemoryKeyValueStore) GetTip(key string) ([]byte, bool, error) {
+	st.mtx.RLock()
+	defer st.mtx.RUnlock()
 	if st.tip == nil {
 		return nil, false, nil
 	}
@@ -33,17 +53,47 @@ func (st *MemoryKeyValueStore) GetTip(key string) ([]byte, bool, error) {
 }
 
 func (st *MemoryKeyValueStore) IsEmpty() (bool) {
+	st.mtx.RLock()
+	defer st.mtx.RUnlock()
 	return len(st.pairs) == 0
 }
 
 func (st *MemoryKeyValueStore) Size() (int) {
+	st.mtx.RLock()
+	defer st.mtx.RUnlock()
 	return len(st.pairs)
 }
 
 func (st *MemoryKeyValueStore) GetAll() ([][]byte, error) {
+	st.mtx.RLock()
+	defer st.mtx.RUnlock()
 	all := make([][]byte, 0)
 	for _, v := range st.pairs {
 		all = append(all, v)
 	}
 	return all, nil
-}
\ No newline at end of file
+}
+
+// ForEach calls fn for a copy of the pairs in the order of their keys, so fn may change the store.
+func (st *MemoryKeyValueStore) ForEach(fn func(key string, value []byte) error) error {
+	st.mtx.RLock()
+	keys := make([]string, 0, len(st.pairs))
+	values := make(map[string][]byte, len(st.pairs))
+	for k, v := range st.pairs {
+		keys = append(keys, k)
+		values[k] = v
+	}
+	st.mtx.RUnlock()
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := fn(k, values[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (st *MemoryKeyValueStore) Close() (error) {
+	return nil
+}
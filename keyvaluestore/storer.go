@@ -4,8 +4,11 @@ type Storer interface {
 	Init(options interface{}) (error)
 	Put(key string, value []byte) (error)
 	Get(key string) ([]byte, bool, error)
+	Delete(key string) (error)
 	GetAll() ([][]byte, error)
+	ForEach(fn func(key string, value []byte) error) error
 	GetTip(key string) ([]byte, bool, error)
 	IsEmpty() (bool)
 	Size() (int)
+	Close() (error)
 }
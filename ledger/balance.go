@@ -0,0 +1,94 @@
+package ledger
+
+import (
+	"github.com/msaldanha/realChain/errors"
+)
+
+const (
+	ErrHeightNotInChain      = errors.Error("height beyond the head of the address chain")
+	ErrInvalidBalanceHistory = errors.Error("invalid balance history range or bucket")
+	ErrTooManyBalanceBuckets = errors.Error("too many balance history buckets")
+)
+
+// MaxBalanceBuckets is the maximum number of balances of a balance history.
+const MaxBalanceBuckets = 10000
+
+// GetBalanceAt returns the balance of address after the transaction at height or, if height is 0, at timestamp,
+// after the transactions older than it. Without both, it returns the balance after the head of the chain.
+func (ts *TransactionStore) GetBalanceAt(address string, timestamp, height int64) (*Balance, error) {
+	head, err := ts.GetHeadHeight(address)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case height > head:
+		return nil, ErrHeightNotInChain
+	case height > 0:
+		return ts.getBalance(address, height)
+	case timestamp > 0:
+		height, err = ts.getHeightBefore(address, head, timestamp)
+		if err != nil {
+			return nil, err
+		}
+		balance, err := ts.getBalance(address, height)
+		if err != nil {
+			return nil, err
+		}
+		balance.Timestamp = timestamp
+		return balance, nil
+	default:
+		return ts.getBalance(address, head)
+	}
+}
+
+// GetBalanceHistory returns the balances of address at the end of every bucket, in nanoseconds, from the timestamp
+// from up to to. The last bucket ends at to even if it is shorter.
+func (ts *TransactionStore) GetBalanceHistory(address string, from, to, bucket int64) ([]*Balance, error) {
+	if bucket <= 0 || from < 0 || to <= from {
+		return nil, ErrInvalidBalanceHistory
+	}
+	if (to-from-1)/bucket+1 > MaxBalanceBuckets {
+		return nil, ErrTooManyBalanceBuckets
+	}
+
+	head, err := ts.GetHeadHeight(address)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]*Balance, 0)
+	for end := from; end < to; {
+		if bucket < to-end {
+			end += bucket
+		} else {
+			end = to
+		}
+		height, err := ts.getHeightBefore(address, head, end)
+		if err != nil {
+			return nil, err
+		}
+		balance, err := ts.getBalance(address, height)
+		if err != nil {
+			return nil, err
+		}
+		balance.Timestamp = end
+		balances = append(balances, balance)
+	}
+	return balances, nil
+}
+
+// getBalance returns the balance of address after the transaction at height, 0 before the open transaction.
+func (ts *TransactionStore) getBalance(address string, height int64) (*Balance, error) {
+	if height == 0 {
+		return &Balance{}, nil
+	}
+	tx, ok, err := ts.GetTransactionAt(address, height)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPreviousTransactionNotFound
+	}
+	return &Balance{Timestamp: tx.Timestamp, Height: height, Hash: tx.Hash, Balance: tx.Balance}, nil
+}
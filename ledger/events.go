@@ -0,0 +1,333 @@
+package ledger
+
+import (
+	"fmt"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/msaldanha/realChain/keyvaluestore"
+	"github.com/msaldanha/realChain/logging"
+	log "github.com/sirupsen/logrus"
+	"strconv"
+	"sync"
+)
+
+const (
+	ErrSubscriberTooSlow = errors.Error("subscriber too slow to receive the ledger events")
+)
+
+const lastSeqKey = "last"
+
+// EventType is the kind of a ledger event.
+type EventType int
+
+const (
+	// BlockStored is fired for every transaction stored in the ledger.
+	BlockStored EventType = iota + 1
+	// BlockPending is fired for every transaction submitted to the node, while it waits for the voting.
+	BlockPending
+	// SendPending is fired for a stored send transaction, whose amount is waiting to be received.
+	SendPending
+	// ReceiveClaimed is fired for a stored receive (or open) transaction. Related is the send transaction
+	// it claims.
+	ReceiveClaimed
+	// ForkDetected is fired for a transaction rejected because its previous transaction is no longer the head of
+	// its chain. Related is the current head.
+	ForkDetected
+	// Rollback is fired for a transaction removed from the ledger because the rest of its transfer failed to be
+	// stored. The transactions of a transfer are published only once both are stored, so the rolled back
+	// transaction never had a BlockStored event.
+	Rollback
+)
+
+var eventTypeNames = map[EventType]string{
+	BlockStored:    "BlockStored",
+	BlockPending:   "BlockPending",
+	SendPending:    "SendPending",
+	ReceiveClaimed: "ReceiveClaimed",
+	ForkDetected:   "ForkDetected",
+	Rollback:       "Rollback",
+}
+
+func (t EventType) String() string {
+	if name, ok := eventTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("EventType(%d)", int(t))
+}
+
+// Event is a ledger event about the transaction Tx. Stored transactions are numbered by Seq in the order they were
+// stored, starting at 1. The other events have the Seq of the last stored transaction.
+type Event struct {
+	Type    EventType
+	Seq     uint64
+	Tx      *Transaction
+	Related *Transaction
+}
+
+// Account returns the address whose chain the event is about.
+func (e *Event) Account() string {
+	return e.Tx.Address
+}
+
+// Handler handles the ledger events it was registered for.
+type Handler func(event *Event)
+
+// EventBus delivers the ledger events to the registered handlers and to the subscribers of the stored and pending
+// transactions. The stored transactions are also kept in a log, so a subscriber that missed some of them, e.g.
+// after a reconnection, can replay them from the last Seq it got.
+//
+// Events are delivered in the order they are fired. Synchronous handlers run before the event is delivered to the
+// next handler or subscriber, so they must be quick and must not write to the ledger. Asynchronous handlers run in
+// the background, in order for the events of the same account and concurrently for different accounts.
+type EventBus struct {
+	log keyvaluestore.Storer
+	// pubMtx serializes the delivery of the events, mtx guards the bus state.
+	pubMtx   sync.Mutex
+	mtx      sync.Mutex
+	subs     map[*Subscription]bool
+	handlers map[EventType][]Handler
+	queues   map[string][]func()
+	workers  sync.WaitGroup
+	logger   *log.Entry
+}
+
+// Subscription receives the stored and pending transactions published after it was created. Start is the Seq
+// of the last stored transaction at that time.
+type Subscription struct {
+	Start uint64
+	bus   *EventBus
+	ch    chan *Event
+	err   error
+}
+
+func NewEventBus(store keyvaluestore.Storer) *EventBus {
+	return &EventBus{
+		log:      store,
+		subs:     make(map[*Subscription]bool),
+		handlers: make(map[EventType][]Handler),
+		queues:   make(map[string][]func()),
+		logger:   logging.Component(log.StandardLogger(), "events"),
+	}
+}
+
+// Handle registers a synchronous handler for the events of the given type.
+func (b *EventBus) Handle(eventType EventType, handler Handler) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// HandleAsync registers an asynchronous handler for the events of the given type.
+func (b *EventBus) HandleAsync(eventType EventType, handler Handler) {
+	b.Handle(eventType, func(event *Event) {
+		b.enqueue(event.Account(), func() { b.call(handler, event) })
+	})
+}
+
+// Publish logs a stored transaction and fires its BlockStored event.
+func (b *EventBus) Publish(tx *Transaction) error {
+	b.pubMtx.Lock()
+	defer b.pubMtx.Unlock()
+
+	b.mtx.Lock()
+	seq, err := b.lastSeq()
+	if err == nil {
+		seq++
+		err = b.append(seq, tx)
+	}
+	b.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+
+	b.dispatch(&Event{Type: BlockStored, Seq: seq, Tx: tx})
+	return nil
+}
+
+// PublishPending fires the BlockPending event of a transaction that is not stored yet. Pending transactions are
+// not logged.
+func (b *EventBus) PublishPending(tx *Transaction) error {
+	return b.Fire(BlockPending, tx, nil)
+}
+
+// Fire fires an event that is not logged.
+func (b *EventBus) Fire(eventType EventType, tx *Transaction, related *Transaction) error {
+	b.pubMtx.Lock()
+	defer b.pubMtx.Unlock()
+
+	seq, err := b.LastSeq()
+	if err != nil {
+		return err
+	}
+
+	b.dispatch(&Event{Type: eventType, Seq: seq, Tx: tx, Related: related})
+	return nil
+}
+
+// Subscribe creates a subscription that buffers up to buffer events. A subscriber that lets the buffer fill up
+// is dropped with ErrSubscriberTooSlow.
+func (b *EventBus) Subscribe(buffer int) (*Subscription, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	seq, err := b.lastSeq()
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &Subscription{Start: seq, bus: b, ch: make(chan *Event, buffer)}
+	b.subs[sub] = true
+	return sub, nil
+}
+
+// Replay calls fn with the logged events after from, up to and including to.
+func (b *EventBus) Replay(from, to uint64, fn func(*Event) error) error {
+	for seq := from + 1; seq <= to; seq++ {
+		value, ok, err := b.log.Get(seqKey(seq))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		err = fn(&Event{Type: BlockStored, Seq: seq, Tx: NewTransactionFromBytes(value)})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LastSeq returns the Seq of the last stored transaction.
+func (b *EventBus) LastSeq() (uint64, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.lastSeq()
+}
+
+func (b *EventBus) lastSeq() (uint64, error) {
+	value, ok, err := b.log.Get(lastSeqKey)
+	if err != nil || !ok {
+		return 0, err
+	}
+	return strconv.ParseUint(string(value), 10, 64)
+}
+
+func (b *EventBus) append(seq uint64, tx *Transaction) error {
+	err := b.log.Put(seqKey(seq), tx.ToBytes())
+	if err != nil {
+		return err
+	}
+	return b.log.Put(lastSeqKey, []byte(strconv.FormatUint(seq, 10)))
+}
+
+func (b *EventBus) dispatch(event *Event) {
+	b.mtx.Lock()
+	handlers := b.handlers[event.Type]
+	if event.Type == BlockStored || event.Type == BlockPending {
+		b.deliver(event)
+	}
+	b.mtx.Unlock()
+
+	for _, handler := range handlers {
+		b.call(handler, event)
+	}
+}
+
+func (b *EventBus) deliver(event *Event) {
+	for sub := range b.subs {
+		select {
+		case sub.ch <- event:
+		default:
+			sub.close(ErrSubscriberTooSlow)
+		}
+	}
+}
+
+// enqueue queues fn after the asynchronous calls already queued for account, starting a worker for the account
+// if it has none.
+func (b *EventBus) enqueue(account string, fn func()) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	queue, running := b.queues[account]
+	b.queues[account] = append(queue, fn)
+	if !running {
+		b.workers.Add(1)
+		go b.drain(account)
+	}
+}
+
+// Wait waits for the asynchronous handlers to handle the events fired so far.
+func (b *EventBus) Wait() {
+	b.workers.Wait()
+}
+
+func (b *EventBus) drain(account string) {
+	defer b.workers.Done()
+	for {
+		b.mtx.Lock()
+		queue := b.queues[account]
+		if len(queue) == 0 {
+			delete(b.queues, account)
+			b.mtx.Unlock()
+			return
+		}
+		fn := queue[0]
+		b.queues[account] = queue[1:]
+		b.mtx.Unlock()
+
+		fn()
+	}
+}
+
+func (b *EventBus) unsubscribe(sub *Subscription) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	sub.close(nil)
+}
+
+// SetLogger sets the logger of the bus.
+func (b *EventBus) SetLogger(logger log.FieldLogger) {
+	b.logger = logging.Component(logger, "events")
+}
+
+// call runs a handler, so a failing handler does not fail the ledger write that fired the event.
+func (b *EventBus) call(handler Handler, event *Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.WithFields(log.Fields{logging.TxField: event.Tx.Hash, logging.AddressField: event.Tx.Address}).
+				Errorf("Handler of %s event failed: %v", event.Type, r)
+		}
+	}()
+	handler(event)
+}
+
+// Events returns the channel the events are delivered to. It is closed when the subscription ends.
+func (s *Subscription) Events() <-chan *Event {
+	return s.ch
+}
+
+// Err returns the reason the subscription was ended by the bus, if any.
+func (s *Subscription) Err() error {
+	s.bus.mtx.Lock()
+	defer s.bus.mtx.Unlock()
+	return s.err
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	s.bus.unsubscribe(s)
+}
+
+func (s *Subscription) close(err error) {
+	if !s.bus.subs[s] {
+		return
+	}
+	delete(s.bus.subs, s)
+	s.err = err
+	close(s.ch)
+}
+
+func seqKey(seq uint64) string {
+	return fmt.Sprintf("seq-%020d", seq)
+}
@@ -0,0 +1,279 @@
+package ledger_test
+
+import (
+	"github.com/msaldanha/realChain/address"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/msaldanha/realChain/keyvaluestore"
+	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/tests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var _ = Describe("EventBus", func() {
+
+	var log *keyvaluestore.MemoryKeyValueStore
+	var events *ledger.EventBus
+	var ld *ledger.LocalLedger
+
+	BeforeEach(func() {
+		log = keyvaluestore.NewMemoryKeyValueStore()
+		events = ledger.NewEventBus(log)
+		ts := ledger.NewTransactionStore(keyvaluestore.NewMemoryKeyValueStore(), ledger.NewValidatorCreator())
+		ld = ledger.NewLocalLedgerWithEvents(ts, events)
+	})
+
+	It("Should number and log the stored transactions", func() {
+		genesisTx, genesisAddr := tests.CreateGenesisTransaction(1000)
+		err := ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+		sendTx, receiveTx := tests.SendFunds(ld, genesisAddr, genesisTx, nil, receiveAddr, 300)
+
+		seq, err := events.LastSeq()
+		Expect(err).To(BeNil())
+		Expect(seq).To(Equal(uint64(3)))
+
+		hashes := make([]string, 0)
+		err = events.Replay(1, seq, func(event *ledger.Event) error {
+			Expect(event.Type).To(Equal(ledger.BlockStored))
+			hashes = append(hashes, event.Tx.Hash)
+			return nil
+		})
+		Expect(err).To(BeNil())
+		Expect(hashes).To(Equal([]string{sendTx.Hash, receiveTx.Hash}))
+	})
+
+	It("Should deliver the stored transactions to the subscribers", func() {
+		sub, err := events.Subscribe(10)
+		Expect(err).To(BeNil())
+		defer sub.Close()
+		Expect(sub.Start).To(Equal(uint64(0)))
+
+		genesisTx, _ := tests.CreateGenesisTransaction(1000)
+		err = ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		var event *ledger.Event
+		Expect(sub.Events()).To(Receive(&event))
+		Expect(event.Seq).To(Equal(uint64(1)))
+		Expect(event.Type).To(Equal(ledger.BlockStored))
+		Expect(event.Tx.Hash).To(Equal(genesisTx.Hash))
+	})
+
+	It("Should deliver pending transactions without logging them", func() {
+		genesisTx, _ := tests.CreateGenesisTransaction(1000)
+		err := ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		sub, err := events.Subscribe(10)
+		Expect(err).To(BeNil())
+		defer sub.Close()
+
+		pendingTx, _ := tests.CreateGenesisTransaction(500)
+		err = events.PublishPending(pendingTx)
+		Expect(err).To(BeNil())
+
+		var event *ledger.Event
+		Expect(sub.Events()).To(Receive(&event))
+		Expect(event.Seq).To(Equal(uint64(1)))
+		Expect(event.Type).To(Equal(ledger.BlockPending))
+		Expect(event.Tx.Hash).To(Equal(pendingTx.Hash))
+
+		seq, err := events.LastSeq()
+		Expect(err).To(BeNil())
+		Expect(seq).To(Equal(uint64(1)))
+	})
+
+	It("Should drop the subscribers that fall behind", func() {
+		sub, err := events.Subscribe(1)
+		Expect(err).To(BeNil())
+
+		first, _ := tests.CreateGenesisTransaction(1000)
+		second, _ := tests.CreateGenesisTransaction(1000)
+		Expect(events.Publish(first)).To(BeNil())
+		Expect(events.Publish(second)).To(BeNil())
+
+		Expect(sub.Events()).To(Receive())
+		Expect(sub.Events()).To(BeClosed())
+		Expect(sub.Err()).To(Equal(ledger.ErrSubscriberTooSlow))
+	})
+
+	It("Should continue the sequence of an existing log", func() {
+		tx, _ := tests.CreateGenesisTransaction(1000)
+		Expect(events.Publish(tx)).To(BeNil())
+
+		reopened := ledger.NewEventBus(log)
+		Expect(reopened.Publish(tx)).To(BeNil())
+
+		seq, err := reopened.LastSeq()
+		Expect(err).To(BeNil())
+		Expect(seq).To(Equal(uint64(2)))
+	})
+
+	It("Should call the handlers with the events of a transfer", func() {
+		genesisTx, genesisAddr := tests.CreateGenesisTransaction(1000)
+		err := ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		fired := make([]string, 0)
+		record := func(event *ledger.Event) {
+			fired = append(fired, event.Type.String()+" "+event.Tx.Hash)
+		}
+		events.Handle(ledger.BlockStored, record)
+		events.Handle(ledger.SendPending, record)
+		events.Handle(ledger.ReceiveClaimed, record)
+
+		var claimed *ledger.Event
+		events.Handle(ledger.ReceiveClaimed, func(event *ledger.Event) { claimed = event })
+
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+		sendTx, receiveTx := tests.SendFunds(ld, genesisAddr, genesisTx, nil, receiveAddr, 300)
+
+		Expect(fired).To(Equal([]string{
+			"BlockStored " + sendTx.Hash,
+			"SendPending " + sendTx.Hash,
+			"BlockStored " + receiveTx.Hash,
+			"ReceiveClaimed " + receiveTx.Hash,
+		}))
+		Expect(claimed.Related.Hash).To(Equal(sendTx.Hash))
+		Expect(claimed.Seq).To(Equal(uint64(3)))
+	})
+
+	It("Should call the asynchronous handlers in order for each account", func() {
+		first, _ := tests.CreateGenesisTransaction(1000)
+		second, _ := tests.CreateGenesisTransaction(1000)
+
+		mtx := sync.Mutex{}
+		seqs := make(map[string][]uint64)
+		events.HandleAsync(ledger.BlockStored, func(event *ledger.Event) {
+			time.Sleep(time.Millisecond)
+			mtx.Lock()
+			defer mtx.Unlock()
+			seqs[event.Account()] = append(seqs[event.Account()], event.Seq)
+		})
+
+		for i := 0; i < 5; i++ {
+			Expect(events.Publish(first)).To(BeNil())
+			Expect(events.Publish(second)).To(BeNil())
+		}
+
+		received := func() map[string][]uint64 {
+			mtx.Lock()
+			defer mtx.Unlock()
+			return map[string][]uint64{first.Address: seqs[first.Address], second.Address: seqs[second.Address]}
+		}
+		Eventually(received).Should(Equal(map[string][]uint64{
+			first.Address:  {1, 3, 5, 7, 9},
+			second.Address: {2, 4, 6, 8, 10},
+		}))
+	})
+
+	It("Should wait for the asynchronous handlers", func() {
+		tx, _ := tests.CreateGenesisTransaction(1000)
+
+		handled := int32(0)
+		events.HandleAsync(ledger.BlockStored, func(event *ledger.Event) {
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&handled, 1)
+		})
+
+		Expect(events.Publish(tx)).To(BeNil())
+		Expect(events.Publish(tx)).To(BeNil())
+		events.Wait()
+		Expect(atomic.LoadInt32(&handled)).To(Equal(int32(2)))
+	})
+
+	It("Should fire ForkDetected for transactions that do not follow the chain head", func() {
+		genesisTx, genesisAddr := tests.CreateGenesisTransaction(1000)
+		err := ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+		headTx, receiveTx := tests.SendFunds(ld, genesisAddr, genesisTx, nil, receiveAddr, 300)
+
+		var fork *ledger.Event
+		events.Handle(ledger.ForkDetected, func(event *ledger.Event) { fork = event })
+
+		forkTx, err := ledger.CreateSendTransaction(genesisTx, genesisAddr, receiveAddr.Address, 100)
+		Expect(err).To(BeNil())
+		forkReceiveTx, err := ledger.CreateReceiveTransaction(forkTx, 100, receiveAddr, receiveTx)
+		Expect(err).To(BeNil())
+
+		err = ld.Register(forkTx, forkReceiveTx)
+		Expect(err).To(Equal(ledger.ErrPreviousTransactionIsNotHead))
+		Expect(fork).NotTo(BeNil())
+		Expect(fork.Tx.Hash).To(Equal(forkTx.Hash))
+		Expect(fork.Related.Hash).To(Equal(headTx.Hash))
+	})
+
+	It("Should roll back the send transaction if the receive transaction fails to be stored", func() {
+		store := &failingStore{MemoryKeyValueStore: keyvaluestore.NewMemoryKeyValueStore()}
+		ts := ledger.NewTransactionStore(store, ledger.NewValidatorCreator())
+		ld = ledger.NewLocalLedgerWithEvents(ts, events)
+
+		genesisTx, genesisAddr := tests.CreateGenesisTransaction(1000)
+		err := ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		var rollback *ledger.Event
+		events.Handle(ledger.Rollback, func(event *ledger.Event) { rollback = event })
+		stored := make([]string, 0)
+		events.Handle(ledger.BlockStored, func(event *ledger.Event) { stored = append(stored, event.Tx.Hash) })
+
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+		sendTx, err := ledger.CreateSendTransaction(genesisTx, genesisAddr, receiveAddr.Address, 300)
+		Expect(err).To(BeNil())
+		receiveTx, err := ledger.CreateReceiveTransaction(sendTx, 300, receiveAddr, nil)
+		Expect(err).To(BeNil())
+
+		store.failKey = receiveTx.Hash
+		err = ld.Register(sendTx, receiveTx)
+		Expect(err).To(Equal(errStoreFailed))
+
+		Expect(rollback).NotTo(BeNil())
+		Expect(rollback.Tx.Hash).To(Equal(sendTx.Hash))
+		Expect(stored).To(BeEmpty())
+		seq, err := events.LastSeq()
+		Expect(err).To(BeNil())
+		Expect(seq).To(Equal(uint64(1)))
+
+		head, err := ld.GetLastTransaction(genesisTx.Address)
+		Expect(err).To(BeNil())
+		Expect(head.Hash).To(Equal(genesisTx.Hash))
+		tx, err := ld.GetTransaction(sendTx.Hash)
+		Expect(err).To(BeNil())
+		Expect(tx).To(BeNil())
+	})
+
+	It("Should not fail the ledger write if a handler fails", func() {
+		events.Handle(ledger.BlockStored, func(event *ledger.Event) { panic("handler failed") })
+
+		genesisTx, _ := tests.CreateGenesisTransaction(1000)
+		err := ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+	})
+})
+
+const errStoreFailed = errors.Error("store failed")
+
+// failingStore fails to store the value of failKey.
+type failingStore struct {
+	*keyvaluestore.MemoryKeyValueStore
+	failKey string
+}
+
+func (s *failingStore) Put(key string, value []byte) error {
+	if key == s.failKey {
+		return errStoreFailed
+	}
+	return s.MemoryKeyValueStore.Put(key, value)
+}
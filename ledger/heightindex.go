@@ -0,0 +1,200 @@
+package ledger
+
+import (
+	"strconv"
+)
+
+// The height index gives every stored transaction its height in its address chain, the open transaction being at
+// height 1, and maps the heights of every chain back to the transactions, so any part of a chain is read without
+// following the previous transactions from the head. Every height also keeps the latest timestamp of the chain up to
+// it, which never decreases along the chain, so the height of the chain at any time is found by binary search.
+
+// indexVersion is the version of the keys kept in the index, bumped whenever a key is added for the indexed
+// transactions.
+const indexVersion = 1
+
+const versionKey = "version"
+
+func heightKey(hash string) string {
+	return "height/" + hash
+}
+
+func chainKey(address string, height int64) string {
+	return "chain/" + address + "/" + strconv.FormatInt(height, 10)
+}
+
+func timeKey(address string, height int64) string {
+	return "time/" + address + "/" + strconv.FormatInt(height, 10)
+}
+
+// GetHeight returns the height of the transaction hash in its address chain.
+func (ts *TransactionStore) GetHeight(hash string) (int64, bool, error) {
+	value, ok, err := ts.index.Get(heightKey(hash))
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	height, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return height, true, nil
+}
+
+// GetTransactionAt returns the transaction at height in the chain of address.
+func (ts *TransactionStore) GetTransactionAt(address string, height int64) (*Transaction, bool, error) {
+	hash, ok, err := ts.index.Get(chainKey(address, height))
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return ts.GetTransaction(string(hash))
+}
+
+// GetHeadHeight returns the height of the head of the chain of address, 0 if the address has no chain.
+func (ts *TransactionStore) GetHeadHeight(address string) (int64, error) {
+	head, ok, err := ts.GetTransaction(address)
+	if err != nil || !ok {
+		return 0, err
+	}
+	return ts.indexChain(head)
+}
+
+// indexChain indexes the chain of tx up to it and returns its height. Only the transactions not indexed yet are
+// read, the ones stored since the last indexed transaction of the chain or, for ledgers stored before the index
+// existed, the whole chain.
+func (ts *TransactionStore) indexChain(tx *Transaction) (int64, error) {
+	height, ok, err := ts.GetHeight(tx.Hash)
+	if err != nil || ok {
+		return height, err
+	}
+
+	ts.mtx.Lock()
+	defer ts.mtx.Unlock()
+	height, ok, err = ts.GetHeight(tx.Hash)
+	if err != nil || ok {
+		return height, err
+	}
+
+	var latest int64
+	unindexed := []*Transaction{tx}
+	for tx.Previous != "" {
+		tx, ok, err = ts.GetTransaction(tx.Previous)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, ErrPreviousTransactionNotFound
+		}
+		height, ok, err = ts.GetHeight(tx.Hash)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			latest, err = ts.getLatestTimestamp(tx.Address, height)
+			if err != nil {
+				return 0, err
+			}
+			break
+		}
+		unindexed = append(unindexed, tx)
+	}
+
+	for i := len(unindexed) - 1; i >= 0; i-- {
+		height++
+		tx = unindexed[i]
+		err = ts.index.Put(chainKey(tx.Address, height), []byte(tx.Hash))
+		if err != nil {
+			return 0, err
+		}
+		if tx.Timestamp > latest {
+			latest = tx.Timestamp
+		}
+		err = ts.index.Put(timeKey(tx.Address, height), []byte(strconv.FormatInt(latest, 10)))
+		if err != nil {
+			return 0, err
+		}
+		err = ts.indexLink(tx)
+		if err != nil {
+			return 0, err
+		}
+		err = ts.index.Put(heightKey(tx.Hash), []byte(strconv.FormatInt(height, 10)))
+		if err != nil {
+			return 0, err
+		}
+	}
+	return height, nil
+}
+
+// unindex removes the head transaction tx from the index.
+func (ts *TransactionStore) unindex(tx *Transaction) error {
+	ts.mtx.Lock()
+	defer ts.mtx.Unlock()
+	height, ok, err := ts.GetHeight(tx.Hash)
+	if err != nil || !ok {
+		return err
+	}
+	err = ts.index.Delete(chainKey(tx.Address, height))
+	if err != nil {
+		return err
+	}
+	err = ts.index.Delete(timeKey(tx.Address, height))
+	if err != nil {
+		return err
+	}
+	err = ts.unindexLink(tx)
+	if err != nil {
+		return err
+	}
+	return ts.index.Delete(heightKey(tx.Hash))
+}
+
+// clearIndex removes every key from the index.
+func (ts *TransactionStore) clearIndex() error {
+	ts.mtx.Lock()
+	defer ts.mtx.Unlock()
+	keys := make([]string, 0)
+	err := ts.index.ForEach(func(key string, value []byte) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		err = ts.index.Delete(key)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getLatestTimestamp returns the latest timestamp of the chain of address up to height.
+func (ts *TransactionStore) getLatestTimestamp(address string, height int64) (int64, error) {
+	value, ok, err := ts.index.Get(timeKey(address, height))
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrPreviousTransactionNotFound
+	}
+	return strconv.ParseInt(string(value), 10, 64)
+}
+
+// getHeightBefore returns the last height of the chain of address, whose head is at head, up to which every
+// transaction is older than timestamp, 0 if the open transaction is not.
+func (ts *TransactionStore) getHeightBefore(address string, head, timestamp int64) (int64, error) {
+	low, high := int64(0), head
+	for low < high {
+		middle := low + (high-low+1)/2
+		latest, err := ts.getLatestTimestamp(address, middle)
+		if err != nil {
+			return 0, err
+		}
+		if latest < timestamp {
+			low = middle
+		} else {
+			high = middle - 1
+		}
+	}
+	return low, nil
+}
@@ -22,17 +22,26 @@ const (
 	ErrSentAmountDiffersFromReceivedAmount      = errors.Error("sent amount differs from received amount")
 	ErrInvalidReceiveTransaction                = errors.Error("invalid receive transaction")
 	ErrInvalidSendTransaction                   = errors.Error("invalid send transaction")
+	ErrTransactionIsNotHead                     = errors.Error("transaction is not the chain head")
 )
 
 //go:generate protoc -I.. ledger/ledgerserver.proto --go_out=plugins=grpc:../
 //go:generate mockgen -destination=../tests/mock_ledger.go -package=tests github.com/msaldanha/realChain/ledger Ledger
 //go:generate mockgen -destination=../tests/mock_ledgerclient.go -package=tests github.com/msaldanha/realChain/ledger LedgerClient
+//go:generate mockgen -destination=../tests/mock_ledgerserver.go -package=tests github.com/msaldanha/realChain/ledger LedgerServer
 
 type Ledger interface {
 	Initialize(genesisTx *Transaction) error
 	GetLastTransaction(address string) (*Transaction, error)
 	GetTransaction(hash string) (*Transaction, error)
 	GetAddressStatement(address string) ([]*Transaction, error)
+	GetAddressStatementPage(address string, query StatementQuery) ([]*Transaction, string, error)
+	GetBalanceAt(address string, timestamp, height int64) (*Balance, error)
+	GetBalanceHistory(address string, from, to, bucket int64) ([]*Balance, error)
+	GetIncoming(address string, pending bool) ([]*Transaction, error)
+	GetReceiveForSend(sendHash string) (*Transaction, error)
+	GetFrontiers() (map[string]string, error)
+	GetStats() (*Stats, error)
 	Register(sendTx *Transaction, receiveTx *Transaction) error
 	VerifyTransaction(tx *Transaction, isNew bool) error
 	Verify(sendTx *Transaction, receiveTx *Transaction) error
@@ -9,6 +9,8 @@ import (
 	"github.com/msaldanha/realChain/tests"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"strings"
+	"sync"
 )
 
 var _ = Describe("Ledger", func() {
@@ -358,6 +360,386 @@ var _ = Describe("Ledger", func() {
 		Expect(txChain[9].Balance).To(Equal(float64(1000)))
 	})
 
+	It("Should page and filter the address statement", func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		defer mockCtrl.Finish()
+
+		err := ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+
+		var prevReceiveTx *ledger.Transaction
+		prevSendTx := genesisTx
+		for x := 1; x <= 5; x++ {
+			prevSendTx, prevReceiveTx = tests.SendFunds(ld, genesisAddr, prevSendTx, prevReceiveTx, receiveAddr, 100)
+		}
+		addr := prevSendTx.Address
+		chain, err := ld.GetAddressStatement(addr)
+		Expect(err).To(BeNil())
+		Expect(len(chain)).To(Equal(6))
+
+		txs, next, err := ld.GetAddressStatementPage(addr, ledger.StatementQuery{Limit: 4})
+		Expect(err).To(BeNil())
+		Expect(txs).To(Equal(chain[:4]))
+		Expect(next).To(Equal(chain[4].Hash))
+
+		txs, next, err = ld.GetAddressStatementPage(addr, ledger.StatementQuery{StartHash: next, Limit: 4})
+		Expect(err).To(BeNil())
+		Expect(txs).To(Equal(chain[4:]))
+		Expect(next).To(BeEmpty())
+
+		txs, next, err = ld.GetAddressStatementPage(addr, ledger.StatementQuery{Direction: ledger.Direction_BACKWARD, Limit: 2})
+		Expect(err).To(BeNil())
+		Expect(txs).To(Equal([]*ledger.Transaction{chain[5], chain[4]}))
+		Expect(next).To(Equal(chain[3].Hash))
+
+		txs, next, err = ld.GetAddressStatementPage(addr, ledger.StatementQuery{StartHeight: 2,
+			Direction: ledger.Direction_BACKWARD})
+		Expect(err).To(BeNil())
+		Expect(txs).To(Equal([]*ledger.Transaction{chain[1], chain[0]}))
+		Expect(next).To(BeEmpty())
+
+		txs, _, err = ld.GetAddressStatementPage(addr, ledger.StatementQuery{Type: ledger.Transaction_OPEN})
+		Expect(err).To(BeNil())
+		Expect(txs).To(Equal(chain[:1]))
+
+		txs, _, err = ld.GetAddressStatementPage(addr, ledger.StatementQuery{From: chain[2].Timestamp,
+			To: chain[2].Timestamp + 1})
+		Expect(err).To(BeNil())
+		for _, tx := range txs {
+			Expect(tx.Timestamp).To(Equal(chain[2].Timestamp))
+		}
+		Expect(txs).To(ContainElement(chain[2]))
+
+		_, _, err = ld.GetAddressStatementPage(addr, ledger.StatementQuery{StartHash: prevReceiveTx.Hash})
+		Expect(err).To(Equal(ledger.ErrStatementStartNotInChain))
+		_, _, err = ld.GetAddressStatementPage(addr, ledger.StatementQuery{StartHash: prevReceiveTx.Hash,
+			Direction: ledger.Direction_BACKWARD})
+		Expect(err).To(Equal(ledger.ErrStatementStartNotInChain))
+	})
+
+	It("Should index the height of the transactions in their address chain", func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		defer mockCtrl.Finish()
+
+		err := ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+
+		var prevReceiveTx *ledger.Transaction
+		prevSendTx := genesisTx
+		for x := 1; x <= 3; x++ {
+			prevSendTx, prevReceiveTx = tests.SendFunds(ld, genesisAddr, prevSendTx, prevReceiveTx, receiveAddr, 100)
+		}
+		chain, err := ld.GetAddressStatement(prevSendTx.Address)
+		Expect(err).To(BeNil())
+
+		// A store over the same transactions without an index indexes the chains when they are read.
+		unindexed := ledger.NewTransactionStore(ms, ledger.NewValidatorCreator())
+		for _, ts := range []*ledger.TransactionStore{bs, unindexed} {
+			height, err := ts.GetHeadHeight(prevSendTx.Address)
+			Expect(err).To(BeNil())
+			Expect(height).To(Equal(int64(4)))
+
+			for i, expected := range chain {
+				height, ok, err := ts.GetHeight(expected.Hash)
+				Expect(err).To(BeNil())
+				Expect(ok).To(BeTrue())
+				Expect(height).To(Equal(int64(i + 1)))
+
+				tx, ok, err := ts.GetTransactionAt(prevSendTx.Address, height)
+				Expect(err).To(BeNil())
+				Expect(ok).To(BeTrue())
+				Expect(tx.Hash).To(Equal(expected.Hash))
+			}
+		}
+
+		height, err := bs.GetHeadHeight(prevReceiveTx.Address)
+		Expect(err).To(BeNil())
+		Expect(height).To(Equal(int64(3)))
+
+		Expect(bs.Remove(prevReceiveTx)).To(BeNil())
+		_, ok, err := bs.GetHeight(prevReceiveTx.Hash)
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeFalse())
+		_, ok, err = bs.GetTransactionAt(prevReceiveTx.Address, 3)
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeFalse())
+		height, err = bs.GetHeadHeight(prevReceiveTx.Address)
+		Expect(err).To(BeNil())
+		Expect(height).To(Equal(int64(2)))
+
+		height, err = bs.GetHeadHeight("unknown")
+		Expect(err).To(BeNil())
+		Expect(height).To(BeZero())
+	})
+
+	It("Should return the balance of an address at a time or height", func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		defer mockCtrl.Finish()
+
+		err := ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+
+		var prevReceiveTx *ledger.Transaction
+		prevSendTx := genesisTx
+		for x := 1; x <= 3; x++ {
+			prevSendTx, prevReceiveTx = tests.SendFunds(ld, genesisAddr, prevSendTx, prevReceiveTx, receiveAddr, 100)
+		}
+		chain, err := ld.GetAddressStatement(prevReceiveTx.Address)
+		Expect(err).To(BeNil())
+		Expect(chain).To(HaveLen(3))
+
+		balance, err := ld.GetBalanceAt(prevReceiveTx.Address, 0, 0)
+		Expect(err).To(BeNil())
+		Expect(balance.Balance).To(Equal(float64(300)))
+		Expect(balance.Height).To(Equal(int64(3)))
+		Expect(balance.Hash).To(Equal(prevReceiveTx.Hash))
+
+		balance, err = ld.GetBalanceAt(prevReceiveTx.Address, 0, 2)
+		Expect(err).To(BeNil())
+		Expect(balance.Balance).To(Equal(float64(200)))
+		Expect(balance.Hash).To(Equal(chain[1].Hash))
+
+		balance, err = ld.GetBalanceAt(prevReceiveTx.Address, chain[1].Timestamp, 0)
+		Expect(err).To(BeNil())
+		Expect(balance.Balance).To(Equal(float64(100)))
+		Expect(balance.Height).To(Equal(int64(1)))
+		Expect(balance.Timestamp).To(Equal(chain[1].Timestamp))
+
+		balance, err = ld.GetBalanceAt(prevReceiveTx.Address, chain[0].Timestamp, 0)
+		Expect(err).To(BeNil())
+		Expect(balance.Balance).To(BeZero())
+		Expect(balance.Height).To(BeZero())
+
+		_, err = ld.GetBalanceAt(prevReceiveTx.Address, 0, 4)
+		Expect(err).To(Equal(ledger.ErrHeightNotInChain))
+
+		from := chain[0].Timestamp
+		bucket := chain[2].Timestamp - chain[0].Timestamp
+		balances, err := ld.GetBalanceHistory(prevReceiveTx.Address, from-bucket, from+bucket+1, bucket)
+		Expect(err).To(BeNil())
+		Expect(balances).To(HaveLen(3))
+		Expect(balances[0].Timestamp).To(Equal(from))
+		Expect(balances[0].Balance).To(BeZero())
+		Expect(balances[1].Timestamp).To(Equal(from + bucket))
+		Expect(balances[1].Balance).To(Equal(float64(200)))
+		Expect(balances[2].Timestamp).To(Equal(from + bucket + 1))
+		Expect(balances[2].Balance).To(Equal(float64(300)))
+
+		_, err = ld.GetBalanceHistory(prevReceiveTx.Address, from, from, bucket)
+		Expect(err).To(Equal(ledger.ErrInvalidBalanceHistory))
+		_, err = ld.GetBalanceHistory(prevReceiveTx.Address, from, from+ledger.MaxBalanceBuckets+1, 1)
+		Expect(err).To(Equal(ledger.ErrTooManyBalanceBuckets))
+	})
+
+	It("Should rebuild an index built by another version", func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		defer mockCtrl.Finish()
+
+		err := ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+
+		var prevReceiveTx *ledger.Transaction
+		prevSendTx := genesisTx
+		for x := 1; x <= 2; x++ {
+			prevSendTx, prevReceiveTx = tests.SendFunds(ld, genesisAddr, prevSendTx, prevReceiveTx, receiveAddr, 100)
+		}
+
+		// An index built before the latest timestamps were kept has the heights only, and no version.
+		index := keyvaluestore.NewMemoryKeyValueStore()
+		Expect(ledger.NewTransactionStoreWithIndex(ms, index, ledger.NewValidatorCreator()).IndexChains()).To(BeNil())
+		err = index.ForEach(func(key string, value []byte) error {
+			if strings.HasPrefix(key, "time/") || key == "version" {
+				return index.Delete(key)
+			}
+			return nil
+		})
+		Expect(err).To(BeNil())
+
+		ts := ledger.NewTransactionStoreWithIndex(ms, index, ledger.NewValidatorCreator())
+		Expect(ts.IndexChains()).To(BeNil())
+		balance, err := ts.GetBalanceAt(prevReceiveTx.Address, prevReceiveTx.Timestamp+1, 0)
+		Expect(err).To(BeNil())
+		Expect(balance.Balance).To(Equal(float64(200)))
+
+		// The transactions stored next are indexed on the rebuilt chains.
+		sendTx, err := ledger.CreateSendTransaction(prevSendTx, genesisAddr, receiveAddr.Address, 100)
+		Expect(err).To(BeNil())
+		_, err = ts.Store(sendTx)
+		Expect(err).To(BeNil())
+		height, ok, err := ts.GetHeight(sendTx.Hash)
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+		Expect(height).To(Equal(int64(4)))
+	})
+
+	It("Should index the transfers to an address", func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		defer mockCtrl.Finish()
+
+		err := ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+
+		var prevReceiveTx *ledger.Transaction
+		prevSendTx := genesisTx
+		sends := make([]string, 0)
+		for x := 1; x <= 3; x++ {
+			prevSendTx, prevReceiveTx = tests.SendFunds(ld, genesisAddr, prevSendTx, prevReceiveTx, receiveAddr, 100)
+			sends = append(sends, prevSendTx.Hash)
+		}
+		pendingTx, err := ledger.CreateSendTransaction(prevSendTx, genesisAddr, receiveAddr.Address, 100)
+		Expect(err).To(BeNil())
+		_, err = bs.Store(pendingTx)
+		Expect(err).To(BeNil())
+
+		hashes := func(txs []*ledger.Transaction) []string {
+			result := make([]string, 0)
+			for _, tx := range txs {
+				result = append(result, tx.Hash)
+			}
+			return result
+		}
+
+		// A store over the same transactions without an index indexes the transfers of every chain at once.
+		unindexed := ledger.NewTransactionStore(ms, ledger.NewValidatorCreator())
+		Expect(unindexed.IndexChains()).To(BeNil())
+		for _, ts := range []*ledger.TransactionStore{bs, unindexed} {
+			txs, err := ts.GetIncoming(receiveAddr.Address, false)
+			Expect(err).To(BeNil())
+			Expect(hashes(txs)).To(Equal([]string{sends[0], sends[1], sends[2], pendingTx.Hash}))
+
+			txs, err = ts.GetIncoming(receiveAddr.Address, true)
+			Expect(err).To(BeNil())
+			Expect(hashes(txs)).To(Equal([]string{pendingTx.Hash}))
+
+			tx, ok, err := ts.GetReceiveForSend(sends[2])
+			Expect(err).To(BeNil())
+			Expect(ok).To(BeTrue())
+			Expect(tx.Hash).To(Equal(prevReceiveTx.Hash))
+
+			_, ok, err = ts.GetReceiveForSend(pendingTx.Hash)
+			Expect(err).To(BeNil())
+			Expect(ok).To(BeFalse())
+		}
+
+		tx, err := ld.GetReceiveForSend(sends[0])
+		Expect(err).To(BeNil())
+		Expect(tx.Type).To(Equal(ledger.Transaction_OPEN))
+		tx, err = ld.GetReceiveForSend(prevReceiveTx.Hash)
+		Expect(err).To(BeNil())
+		Expect(tx).To(BeNil())
+
+		Expect(bs.Remove(pendingTx)).To(BeNil())
+		Expect(bs.Remove(prevReceiveTx)).To(BeNil())
+		txs, err := ld.GetIncoming(receiveAddr.Address, true)
+		Expect(err).To(BeNil())
+		Expect(hashes(txs)).To(Equal(sends[2:]))
+		txs, err = ld.GetIncoming(genesisAddr.Address, false)
+		Expect(err).To(BeNil())
+		Expect(txs).To(BeEmpty())
+	})
+
+	It("Should rebuild an index built without the links of the transfers", func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		defer mockCtrl.Finish()
+
+		err := ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+
+		sendTx, receiveTx := tests.SendFunds(ld, genesisAddr, genesisTx, nil, receiveAddr, 100)
+
+		// An index built before the links were kept has the heights of every chain and no receive transactions.
+		index := keyvaluestore.NewMemoryKeyValueStore()
+		Expect(ledger.NewTransactionStoreWithIndex(ms, index, ledger.NewValidatorCreator()).IndexChains()).To(BeNil())
+		removeLinks := func() {
+			err := index.ForEach(func(key string, value []byte) error {
+				if strings.HasPrefix(key, "receive/") || strings.HasPrefix(key, "incoming") || key == "version" {
+					return index.Delete(key)
+				}
+				return nil
+			})
+			Expect(err).To(BeNil())
+			Expect(index.Put("indexed", []byte("2"))).To(BeNil())
+		}
+		removeLinks()
+
+		ts := ledger.NewTransactionStoreWithIndex(ms, index, ledger.NewValidatorCreator())
+		Expect(ts.IndexChains()).To(BeNil())
+		txs, err := ts.GetIncoming(receiveAddr.Address, false)
+		Expect(err).To(BeNil())
+		Expect(txs).To(HaveLen(1))
+		Expect(txs[0].Hash).To(Equal(sendTx.Hash))
+		txs, err = ts.GetIncoming(receiveAddr.Address, true)
+		Expect(err).To(BeNil())
+		Expect(txs).To(BeEmpty())
+		tx, ok, err := ts.GetReceiveForSend(sendTx.Hash)
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+		Expect(tx.Hash).To(Equal(receiveTx.Hash))
+
+		// The received send transaction is not pending for the votes even before the index is rebuilt.
+		removeLinks()
+		_ = ms.Put(string(sendTx.Hash), nil)
+		_ = ms.Put(string(sendTx.Address), genesisTx.ToBytes())
+		otherReceiveTx, err := ledger.CreateReceiveTransaction(sendTx, 100, receiveAddr, nil)
+		Expect(err).To(BeNil())
+		err = ledger.NewLocalLedger(ts).Verify(sendTx, otherReceiveTx)
+		Expect(err).To(Equal(ledger.ErrSendTransactionIsNotPending))
+	})
+
+	It("Should index a chain once when it is read concurrently", func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		defer mockCtrl.Finish()
+
+		err := ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+
+		var prevReceiveTx *ledger.Transaction
+		prevSendTx := genesisTx
+		for x := 1; x <= 3; x++ {
+			prevSendTx, prevReceiveTx = tests.SendFunds(ld, genesisAddr, prevSendTx, prevReceiveTx, receiveAddr, 100)
+		}
+
+		unindexed := ledger.NewTransactionStore(ms, ledger.NewValidatorCreator())
+		wg := sync.WaitGroup{}
+		for x := 0; x < 10; x++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer GinkgoRecover()
+				_, err := unindexed.GetIncoming(receiveAddr.Address, false)
+				Expect(err).To(BeNil())
+				_, err = unindexed.GetHeadHeight(genesisAddr.Address)
+				Expect(err).To(BeNil())
+			}()
+		}
+		wg.Wait()
+
+		txs, err := unindexed.GetIncoming(receiveAddr.Address, false)
+		Expect(err).To(BeNil())
+		Expect(txs).To(HaveLen(3))
+	})
+
 	It("Should return correct balance", func() {
 		mockCtrl := gomock.NewController(GinkgoT())
 		defer mockCtrl.Finish()
@@ -385,6 +767,98 @@ var _ = Describe("Ledger", func() {
 		Expect(tx.Balance).To(Equal(float64(200)))
 	})
 
+	It("Should return the head of every address chain as frontiers", func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		defer mockCtrl.Finish()
+
+		err := ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+
+		var prevReceiveTx *ledger.Transaction
+		prevSendTx := genesisTx
+		for x := 1; x <= 2; x++ {
+			prevSendTx, prevReceiveTx = tests.SendFunds(ld, genesisAddr, prevSendTx, prevReceiveTx, receiveAddr, 100)
+		}
+
+		frontiers, err := ld.GetFrontiers()
+		Expect(err).To(BeNil())
+		Expect(frontiers).To(Equal(map[string]string{
+			genesisAddr.Address: prevSendTx.Hash,
+			receiveAddr.Address: prevReceiveTx.Hash,
+		}))
+	})
+
+	It("Should count the transactions, the accounts and the pending sends", func() {
+		err := ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+
+		var prevReceiveTx *ledger.Transaction
+		prevSendTx := genesisTx
+		for x := 1; x <= 2; x++ {
+			prevSendTx, prevReceiveTx = tests.SendFunds(ld, genesisAddr, prevSendTx, prevReceiveTx, receiveAddr, 100)
+		}
+
+		stats, err := ld.GetStats()
+		Expect(err).To(BeNil())
+		Expect(stats).To(Equal(&ledger.Stats{Blocks: 5, Accounts: 2, PendingSends: 0}))
+
+		sendTx, err := ledger.CreateSendTransaction(prevSendTx, genesisAddr, receiveAddr.Address, 100)
+		Expect(err).To(BeNil())
+		_, err = bs.Store(sendTx)
+		Expect(err).To(BeNil())
+
+		stats, err = ld.GetStats()
+		Expect(err).To(BeNil())
+		Expect(stats).To(Equal(&ledger.Stats{Blocks: 6, Accounts: 2, PendingSends: 1}))
+	})
+
+	It("Should keep the counters up to date with the writes", func() {
+		stats, err := ld.GetStats()
+		Expect(err).To(BeNil())
+		Expect(stats).To(Equal(&ledger.Stats{}))
+
+		err = ld.Initialize(genesisTx)
+		Expect(err).To(BeNil())
+
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+
+		var prevReceiveTx *ledger.Transaction
+		prevSendTx := genesisTx
+		for x := 1; x <= 2; x++ {
+			prevSendTx, prevReceiveTx = tests.SendFunds(ld, genesisAddr, prevSendTx, prevReceiveTx, receiveAddr, 100)
+		}
+		sendTx, err := ledger.CreateSendTransaction(prevSendTx, genesisAddr, receiveAddr.Address, 100)
+		Expect(err).To(BeNil())
+		_, err = bs.Store(sendTx)
+		Expect(err).To(BeNil())
+
+		stats, err = ld.GetStats()
+		Expect(err).To(BeNil())
+		Expect(stats).To(Equal(&ledger.Stats{Blocks: 6, Accounts: 2, PendingSends: 1}))
+		counted, err := ledger.NewTransactionStore(ms, ledger.NewValidatorCreator()).GetStats()
+		Expect(err).To(BeNil())
+		Expect(counted).To(Equal(stats))
+
+		err = bs.Remove(sendTx)
+		Expect(err).To(BeNil())
+		err = bs.Remove(prevReceiveTx)
+		Expect(err).To(BeNil())
+
+		stats, err = ld.GetStats()
+		Expect(err).To(BeNil())
+		Expect(stats).To(Equal(&ledger.Stats{Blocks: 4, Accounts: 2, PendingSends: 1}))
+		counted, err = ledger.NewTransactionStore(ms, ledger.NewValidatorCreator()).GetStats()
+		Expect(err).To(BeNil())
+		Expect(counted).To(Equal(stats))
+	})
+
 	It("Should verify transaction's pow", func() {
 		mockCtrl := gomock.NewController(GinkgoT())
 		defer mockCtrl.Finish()
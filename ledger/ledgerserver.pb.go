@@ -23,6 +23,55 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type Direction int32
+
+const (
+	Direction_FORWARD  Direction = 0
+	Direction_BACKWARD Direction = 1
+)
+
+var Direction_name = map[int32]string{
+	0: "FORWARD",
+	1: "BACKWARD",
+}
+var Direction_value = map[string]int32{
+	"FORWARD":  0,
+	"BACKWARD": 1,
+}
+
+func (x Direction) String() string {
+	return proto.EnumName(Direction_name, int32(x))
+}
+func (Direction) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{0}
+}
+
+type ConfirmationState int32
+
+const (
+	ConfirmationState_ANY_STATE ConfirmationState = 0
+	ConfirmationState_PENDING   ConfirmationState = 1
+	ConfirmationState_CONFIRMED ConfirmationState = 2
+)
+
+var ConfirmationState_name = map[int32]string{
+	0: "ANY_STATE",
+	1: "PENDING",
+	2: "CONFIRMED",
+}
+var ConfirmationState_value = map[string]int32{
+	"ANY_STATE": 0,
+	"PENDING":   1,
+	"CONFIRMED": 2,
+}
+
+func (x ConfirmationState) String() string {
+	return proto.EnumName(ConfirmationState_name, int32(x))
+}
+func (ConfirmationState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{1}
+}
+
 type RegisterRequest struct {
 	SendTx               *Transaction `protobuf:"bytes,1,opt,name=sendTx,proto3" json:"sendTx,omitempty"`
 	ReceiveTx            *Transaction `protobuf:"bytes,2,opt,name=receiveTx,proto3" json:"receiveTx,omitempty"`
@@ -35,7 +84,7 @@ func (m *RegisterRequest) Reset()         { *m = RegisterRequest{} }
 func (m *RegisterRequest) String() string { return proto.CompactTextString(m) }
 func (*RegisterRequest) ProtoMessage()    {}
 func (*RegisterRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ledgerserver_fe3ec0588a3116af, []int{0}
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{0}
 }
 func (m *RegisterRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RegisterRequest.Unmarshal(m, b)
@@ -79,7 +128,7 @@ func (m *RegisterResult) Reset()         { *m = RegisterResult{} }
 func (m *RegisterResult) String() string { return proto.CompactTextString(m) }
 func (*RegisterResult) ProtoMessage()    {}
 func (*RegisterResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ledgerserver_fe3ec0588a3116af, []int{1}
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{1}
 }
 func (m *RegisterResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RegisterResult.Unmarshal(m, b)
@@ -110,7 +159,7 @@ func (m *GetLastTransactionRequest) Reset()         { *m = GetLastTransactionReq
 func (m *GetLastTransactionRequest) String() string { return proto.CompactTextString(m) }
 func (*GetLastTransactionRequest) ProtoMessage()    {}
 func (*GetLastTransactionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ledgerserver_fe3ec0588a3116af, []int{2}
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{2}
 }
 func (m *GetLastTransactionRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetLastTransactionRequest.Unmarshal(m, b)
@@ -148,7 +197,7 @@ func (m *GetLastTransactionResult) Reset()         { *m = GetLastTransactionResu
 func (m *GetLastTransactionResult) String() string { return proto.CompactTextString(m) }
 func (*GetLastTransactionResult) ProtoMessage()    {}
 func (*GetLastTransactionResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ledgerserver_fe3ec0588a3116af, []int{3}
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{3}
 }
 func (m *GetLastTransactionResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetLastTransactionResult.Unmarshal(m, b)
@@ -186,7 +235,7 @@ func (m *GetTransactionRequest) Reset()         { *m = GetTransactionRequest{} }
 func (m *GetTransactionRequest) String() string { return proto.CompactTextString(m) }
 func (*GetTransactionRequest) ProtoMessage()    {}
 func (*GetTransactionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ledgerserver_fe3ec0588a3116af, []int{4}
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{4}
 }
 func (m *GetTransactionRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetTransactionRequest.Unmarshal(m, b)
@@ -224,7 +273,7 @@ func (m *GetTransactionResult) Reset()         { *m = GetTransactionResult{} }
 func (m *GetTransactionResult) String() string { return proto.CompactTextString(m) }
 func (*GetTransactionResult) ProtoMessage()    {}
 func (*GetTransactionResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ledgerserver_fe3ec0588a3116af, []int{5}
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{5}
 }
 func (m *GetTransactionResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetTransactionResult.Unmarshal(m, b)
@@ -253,6 +302,7 @@ func (m *GetTransactionResult) GetTx() *Transaction {
 
 type VerifyTransactionRequest struct {
 	Tx                   *Transaction `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
+	Stored               bool         `protobuf:"varint,2,opt,name=stored,proto3" json:"stored,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
 	XXX_unrecognized     []byte       `json:"-"`
 	XXX_sizecache        int32        `json:"-"`
@@ -262,7 +312,7 @@ func (m *VerifyTransactionRequest) Reset()         { *m = VerifyTransactionReque
 func (m *VerifyTransactionRequest) String() string { return proto.CompactTextString(m) }
 func (*VerifyTransactionRequest) ProtoMessage()    {}
 func (*VerifyTransactionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ledgerserver_fe3ec0588a3116af, []int{6}
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{6}
 }
 func (m *VerifyTransactionRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VerifyTransactionRequest.Unmarshal(m, b)
@@ -289,6 +339,13 @@ func (m *VerifyTransactionRequest) GetTx() *Transaction {
 	return nil
 }
 
+func (m *VerifyTransactionRequest) GetStored() bool {
+	if m != nil {
+		return m.Stored
+	}
+	return false
+}
+
 type VerifyTransactionResult struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -299,7 +356,7 @@ func (m *VerifyTransactionResult) Reset()         { *m = VerifyTransactionResult
 func (m *VerifyTransactionResult) String() string { return proto.CompactTextString(m) }
 func (*VerifyTransactionResult) ProtoMessage()    {}
 func (*VerifyTransactionResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ledgerserver_fe3ec0588a3116af, []int{7}
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{7}
 }
 func (m *VerifyTransactionResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VerifyTransactionResult.Unmarshal(m, b)
@@ -331,7 +388,7 @@ func (m *VerifyRequest) Reset()         { *m = VerifyRequest{} }
 func (m *VerifyRequest) String() string { return proto.CompactTextString(m) }
 func (*VerifyRequest) ProtoMessage()    {}
 func (*VerifyRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ledgerserver_fe3ec0588a3116af, []int{8}
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{8}
 }
 func (m *VerifyRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VerifyRequest.Unmarshal(m, b)
@@ -375,7 +432,7 @@ func (m *VerifyResult) Reset()         { *m = VerifyResult{} }
 func (m *VerifyResult) String() string { return proto.CompactTextString(m) }
 func (*VerifyResult) ProtoMessage()    {}
 func (*VerifyResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ledgerserver_fe3ec0588a3116af, []int{9}
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{9}
 }
 func (m *VerifyResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VerifyResult.Unmarshal(m, b)
@@ -396,17 +453,24 @@ func (m *VerifyResult) XXX_DiscardUnknown() {
 var xxx_messageInfo_VerifyResult proto.InternalMessageInfo
 
 type GetAddressStatementRequest struct {
-	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Address              string           `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	StartHash            string           `protobuf:"bytes,2,opt,name=startHash,proto3" json:"startHash,omitempty"`
+	StartHeight          int64            `protobuf:"varint,3,opt,name=startHeight,proto3" json:"startHeight,omitempty"`
+	Limit                uint32           `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Direction            Direction        `protobuf:"varint,5,opt,name=direction,proto3,enum=ledger.Direction" json:"direction,omitempty"`
+	Type                 Transaction_Type `protobuf:"varint,6,opt,name=type,proto3,enum=ledger.Transaction_Type" json:"type,omitempty"`
+	FromTimestamp        int64            `protobuf:"varint,7,opt,name=fromTimestamp,proto3" json:"fromTimestamp,omitempty"`
+	ToTimestamp          int64            `protobuf:"varint,8,opt,name=toTimestamp,proto3" json:"toTimestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
 func (m *GetAddressStatementRequest) Reset()         { *m = GetAddressStatementRequest{} }
 func (m *GetAddressStatementRequest) String() string { return proto.CompactTextString(m) }
 func (*GetAddressStatementRequest) ProtoMessage()    {}
 func (*GetAddressStatementRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ledgerserver_fe3ec0588a3116af, []int{10}
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{10}
 }
 func (m *GetAddressStatementRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetAddressStatementRequest.Unmarshal(m, b)
@@ -433,8 +497,58 @@ func (m *GetAddressStatementRequest) GetAddress() string {
 	return ""
 }
 
+func (m *GetAddressStatementRequest) GetStartHash() string {
+	if m != nil {
+		return m.StartHash
+	}
+	return ""
+}
+
+func (m *GetAddressStatementRequest) GetStartHeight() int64 {
+	if m != nil {
+		return m.StartHeight
+	}
+	return 0
+}
+
+func (m *GetAddressStatementRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *GetAddressStatementRequest) GetDirection() Direction {
+	if m != nil {
+		return m.Direction
+	}
+	return Direction_FORWARD
+}
+
+func (m *GetAddressStatementRequest) GetType() Transaction_Type {
+	if m != nil {
+		return m.Type
+	}
+	return Transaction_ZERO
+}
+
+func (m *GetAddressStatementRequest) GetFromTimestamp() int64 {
+	if m != nil {
+		return m.FromTimestamp
+	}
+	return 0
+}
+
+func (m *GetAddressStatementRequest) GetToTimestamp() int64 {
+	if m != nil {
+		return m.ToTimestamp
+	}
+	return 0
+}
+
 type GetAddressStatementResult struct {
 	Txs                  []*Transaction `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
+	NextHash             string         `protobuf:"bytes,2,opt,name=nextHash,proto3" json:"nextHash,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
 	XXX_unrecognized     []byte         `json:"-"`
 	XXX_sizecache        int32          `json:"-"`
@@ -444,7 +558,7 @@ func (m *GetAddressStatementResult) Reset()         { *m = GetAddressStatementRe
 func (m *GetAddressStatementResult) String() string { return proto.CompactTextString(m) }
 func (*GetAddressStatementResult) ProtoMessage()    {}
 func (*GetAddressStatementResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ledgerserver_fe3ec0588a3116af, []int{11}
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{11}
 }
 func (m *GetAddressStatementResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetAddressStatementResult.Unmarshal(m, b)
@@ -471,6 +585,554 @@ func (m *GetAddressStatementResult) GetTxs() []*Transaction {
 	return nil
 }
 
+func (m *GetAddressStatementResult) GetNextHash() string {
+	if m != nil {
+		return m.NextHash
+	}
+	return ""
+}
+
+type SubscribeRequest struct {
+	Addresses            []string          `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Type                 Transaction_Type  `protobuf:"varint,2,opt,name=type,proto3,enum=ledger.Transaction_Type" json:"type,omitempty"`
+	State                ConfirmationState `protobuf:"varint,3,opt,name=state,proto3,enum=ledger.ConfirmationState" json:"state,omitempty"`
+	Resume               bool              `protobuf:"varint,4,opt,name=resume,proto3" json:"resume,omitempty"`
+	Cursor               uint64            `protobuf:"varint,5,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *SubscribeRequest) Reset()         { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()    {}
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{12}
+}
+func (m *SubscribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeRequest.Unmarshal(m, b)
+}
+func (m *SubscribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeRequest.Marshal(b, m, deterministic)
+}
+func (dst *SubscribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeRequest.Merge(dst, src)
+}
+func (m *SubscribeRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeRequest.Size(m)
+}
+func (m *SubscribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeRequest proto.InternalMessageInfo
+
+func (m *SubscribeRequest) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+func (m *SubscribeRequest) GetType() Transaction_Type {
+	if m != nil {
+		return m.Type
+	}
+	return Transaction_ZERO
+}
+
+func (m *SubscribeRequest) GetState() ConfirmationState {
+	if m != nil {
+		return m.State
+	}
+	return ConfirmationState_ANY_STATE
+}
+
+func (m *SubscribeRequest) GetResume() bool {
+	if m != nil {
+		return m.Resume
+	}
+	return false
+}
+
+func (m *SubscribeRequest) GetCursor() uint64 {
+	if m != nil {
+		return m.Cursor
+	}
+	return 0
+}
+
+type TransactionEvent struct {
+	Cursor               uint64            `protobuf:"varint,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	State                ConfirmationState `protobuf:"varint,2,opt,name=state,proto3,enum=ledger.ConfirmationState" json:"state,omitempty"`
+	Tx                   *Transaction      `protobuf:"bytes,3,opt,name=tx,proto3" json:"tx,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *TransactionEvent) Reset()         { *m = TransactionEvent{} }
+func (m *TransactionEvent) String() string { return proto.CompactTextString(m) }
+func (*TransactionEvent) ProtoMessage()    {}
+func (*TransactionEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{13}
+}
+func (m *TransactionEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactionEvent.Unmarshal(m, b)
+}
+func (m *TransactionEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactionEvent.Marshal(b, m, deterministic)
+}
+func (dst *TransactionEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionEvent.Merge(dst, src)
+}
+func (m *TransactionEvent) XXX_Size() int {
+	return xxx_messageInfo_TransactionEvent.Size(m)
+}
+func (m *TransactionEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionEvent proto.InternalMessageInfo
+
+func (m *TransactionEvent) GetCursor() uint64 {
+	if m != nil {
+		return m.Cursor
+	}
+	return 0
+}
+
+func (m *TransactionEvent) GetState() ConfirmationState {
+	if m != nil {
+		return m.State
+	}
+	return ConfirmationState_ANY_STATE
+}
+
+func (m *TransactionEvent) GetTx() *Transaction {
+	if m != nil {
+		return m.Tx
+	}
+	return nil
+}
+
+// Balance is the balance of an address after the transaction at height in its chain, taken at timestamp, which
+// is the timestamp of the transaction for balances taken at a height. Before the open transaction, the height and
+// the balance are 0.
+type Balance struct {
+	Timestamp            int64    `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Height               int64    `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Hash                 string   `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	Balance              float64  `protobuf:"fixed64,4,opt,name=balance,proto3" json:"balance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Balance) Reset()         { *m = Balance{} }
+func (m *Balance) String() string { return proto.CompactTextString(m) }
+func (*Balance) ProtoMessage()    {}
+func (*Balance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{14}
+}
+func (m *Balance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Balance.Unmarshal(m, b)
+}
+func (m *Balance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Balance.Marshal(b, m, deterministic)
+}
+func (dst *Balance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Balance.Merge(dst, src)
+}
+func (m *Balance) XXX_Size() int {
+	return xxx_messageInfo_Balance.Size(m)
+}
+func (m *Balance) XXX_DiscardUnknown() {
+	xxx_messageInfo_Balance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Balance proto.InternalMessageInfo
+
+func (m *Balance) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Balance) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *Balance) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *Balance) GetBalance() float64 {
+	if m != nil {
+		return m.Balance
+	}
+	return 0
+}
+
+type GetBalanceAtRequest struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Timestamp            int64    `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Height               int64    `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBalanceAtRequest) Reset()         { *m = GetBalanceAtRequest{} }
+func (m *GetBalanceAtRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBalanceAtRequest) ProtoMessage()    {}
+func (*GetBalanceAtRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{15}
+}
+func (m *GetBalanceAtRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBalanceAtRequest.Unmarshal(m, b)
+}
+func (m *GetBalanceAtRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBalanceAtRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetBalanceAtRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBalanceAtRequest.Merge(dst, src)
+}
+func (m *GetBalanceAtRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBalanceAtRequest.Size(m)
+}
+func (m *GetBalanceAtRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBalanceAtRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBalanceAtRequest proto.InternalMessageInfo
+
+func (m *GetBalanceAtRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *GetBalanceAtRequest) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *GetBalanceAtRequest) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type GetBalanceAtResult struct {
+	Balance              *Balance `protobuf:"bytes,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBalanceAtResult) Reset()         { *m = GetBalanceAtResult{} }
+func (m *GetBalanceAtResult) String() string { return proto.CompactTextString(m) }
+func (*GetBalanceAtResult) ProtoMessage()    {}
+func (*GetBalanceAtResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{16}
+}
+func (m *GetBalanceAtResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBalanceAtResult.Unmarshal(m, b)
+}
+func (m *GetBalanceAtResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBalanceAtResult.Marshal(b, m, deterministic)
+}
+func (dst *GetBalanceAtResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBalanceAtResult.Merge(dst, src)
+}
+func (m *GetBalanceAtResult) XXX_Size() int {
+	return xxx_messageInfo_GetBalanceAtResult.Size(m)
+}
+func (m *GetBalanceAtResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBalanceAtResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBalanceAtResult proto.InternalMessageInfo
+
+func (m *GetBalanceAtResult) GetBalance() *Balance {
+	if m != nil {
+		return m.Balance
+	}
+	return nil
+}
+
+type GetBalanceHistoryRequest struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	FromTimestamp        int64    `protobuf:"varint,2,opt,name=fromTimestamp,proto3" json:"fromTimestamp,omitempty"`
+	ToTimestamp          int64    `protobuf:"varint,3,opt,name=toTimestamp,proto3" json:"toTimestamp,omitempty"`
+	Bucket               int64    `protobuf:"varint,4,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBalanceHistoryRequest) Reset()         { *m = GetBalanceHistoryRequest{} }
+func (m *GetBalanceHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBalanceHistoryRequest) ProtoMessage()    {}
+func (*GetBalanceHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{17}
+}
+func (m *GetBalanceHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBalanceHistoryRequest.Unmarshal(m, b)
+}
+func (m *GetBalanceHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBalanceHistoryRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetBalanceHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBalanceHistoryRequest.Merge(dst, src)
+}
+func (m *GetBalanceHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBalanceHistoryRequest.Size(m)
+}
+func (m *GetBalanceHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBalanceHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBalanceHistoryRequest proto.InternalMessageInfo
+
+func (m *GetBalanceHistoryRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *GetBalanceHistoryRequest) GetFromTimestamp() int64 {
+	if m != nil {
+		return m.FromTimestamp
+	}
+	return 0
+}
+
+func (m *GetBalanceHistoryRequest) GetToTimestamp() int64 {
+	if m != nil {
+		return m.ToTimestamp
+	}
+	return 0
+}
+
+func (m *GetBalanceHistoryRequest) GetBucket() int64 {
+	if m != nil {
+		return m.Bucket
+	}
+	return 0
+}
+
+type GetBalanceHistoryResult struct {
+	Balances             []*Balance `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *GetBalanceHistoryResult) Reset()         { *m = GetBalanceHistoryResult{} }
+func (m *GetBalanceHistoryResult) String() string { return proto.CompactTextString(m) }
+func (*GetBalanceHistoryResult) ProtoMessage()    {}
+func (*GetBalanceHistoryResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{18}
+}
+func (m *GetBalanceHistoryResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBalanceHistoryResult.Unmarshal(m, b)
+}
+func (m *GetBalanceHistoryResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBalanceHistoryResult.Marshal(b, m, deterministic)
+}
+func (dst *GetBalanceHistoryResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBalanceHistoryResult.Merge(dst, src)
+}
+func (m *GetBalanceHistoryResult) XXX_Size() int {
+	return xxx_messageInfo_GetBalanceHistoryResult.Size(m)
+}
+func (m *GetBalanceHistoryResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBalanceHistoryResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBalanceHistoryResult proto.InternalMessageInfo
+
+func (m *GetBalanceHistoryResult) GetBalances() []*Balance {
+	if m != nil {
+		return m.Balances
+	}
+	return nil
+}
+
+type GetIncomingRequest struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Pending              bool     `protobuf:"varint,2,opt,name=pending,proto3" json:"pending,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetIncomingRequest) Reset()         { *m = GetIncomingRequest{} }
+func (m *GetIncomingRequest) String() string { return proto.CompactTextString(m) }
+func (*GetIncomingRequest) ProtoMessage()    {}
+func (*GetIncomingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{19}
+}
+func (m *GetIncomingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetIncomingRequest.Unmarshal(m, b)
+}
+func (m *GetIncomingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetIncomingRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetIncomingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetIncomingRequest.Merge(dst, src)
+}
+func (m *GetIncomingRequest) XXX_Size() int {
+	return xxx_messageInfo_GetIncomingRequest.Size(m)
+}
+func (m *GetIncomingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetIncomingRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetIncomingRequest proto.InternalMessageInfo
+
+func (m *GetIncomingRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *GetIncomingRequest) GetPending() bool {
+	if m != nil {
+		return m.Pending
+	}
+	return false
+}
+
+type GetIncomingResult struct {
+	Txs                  []*Transaction `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *GetIncomingResult) Reset()         { *m = GetIncomingResult{} }
+func (m *GetIncomingResult) String() string { return proto.CompactTextString(m) }
+func (*GetIncomingResult) ProtoMessage()    {}
+func (*GetIncomingResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{20}
+}
+func (m *GetIncomingResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetIncomingResult.Unmarshal(m, b)
+}
+func (m *GetIncomingResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetIncomingResult.Marshal(b, m, deterministic)
+}
+func (dst *GetIncomingResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetIncomingResult.Merge(dst, src)
+}
+func (m *GetIncomingResult) XXX_Size() int {
+	return xxx_messageInfo_GetIncomingResult.Size(m)
+}
+func (m *GetIncomingResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetIncomingResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetIncomingResult proto.InternalMessageInfo
+
+func (m *GetIncomingResult) GetTxs() []*Transaction {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
+type GetReceiveForSendRequest struct {
+	Hash                 string   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetReceiveForSendRequest) Reset()         { *m = GetReceiveForSendRequest{} }
+func (m *GetReceiveForSendRequest) String() string { return proto.CompactTextString(m) }
+func (*GetReceiveForSendRequest) ProtoMessage()    {}
+func (*GetReceiveForSendRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{21}
+}
+func (m *GetReceiveForSendRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetReceiveForSendRequest.Unmarshal(m, b)
+}
+func (m *GetReceiveForSendRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetReceiveForSendRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetReceiveForSendRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetReceiveForSendRequest.Merge(dst, src)
+}
+func (m *GetReceiveForSendRequest) XXX_Size() int {
+	return xxx_messageInfo_GetReceiveForSendRequest.Size(m)
+}
+func (m *GetReceiveForSendRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetReceiveForSendRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetReceiveForSendRequest proto.InternalMessageInfo
+
+func (m *GetReceiveForSendRequest) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+type GetReceiveForSendResult struct {
+	Tx                   *Transaction `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *GetReceiveForSendResult) Reset()         { *m = GetReceiveForSendResult{} }
+func (m *GetReceiveForSendResult) String() string { return proto.CompactTextString(m) }
+func (*GetReceiveForSendResult) ProtoMessage()    {}
+func (*GetReceiveForSendResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ledgerserver_7330f844a6a49c3b, []int{22}
+}
+func (m *GetReceiveForSendResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetReceiveForSendResult.Unmarshal(m, b)
+}
+func (m *GetReceiveForSendResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetReceiveForSendResult.Marshal(b, m, deterministic)
+}
+func (dst *GetReceiveForSendResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetReceiveForSendResult.Merge(dst, src)
+}
+func (m *GetReceiveForSendResult) XXX_Size() int {
+	return xxx_messageInfo_GetReceiveForSendResult.Size(m)
+}
+func (m *GetReceiveForSendResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetReceiveForSendResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetReceiveForSendResult proto.InternalMessageInfo
+
+func (m *GetReceiveForSendResult) GetTx() *Transaction {
+	if m != nil {
+		return m.Tx
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*RegisterRequest)(nil), "ledger.RegisterRequest")
 	proto.RegisterType((*RegisterResult)(nil), "ledger.RegisterResult")
@@ -484,6 +1146,19 @@ func init() {
 	proto.RegisterType((*VerifyResult)(nil), "ledger.VerifyResult")
 	proto.RegisterType((*GetAddressStatementRequest)(nil), "ledger.GetAddressStatementRequest")
 	proto.RegisterType((*GetAddressStatementResult)(nil), "ledger.GetAddressStatementResult")
+	proto.RegisterType((*SubscribeRequest)(nil), "ledger.SubscribeRequest")
+	proto.RegisterType((*TransactionEvent)(nil), "ledger.TransactionEvent")
+	proto.RegisterType((*Balance)(nil), "ledger.Balance")
+	proto.RegisterType((*GetBalanceAtRequest)(nil), "ledger.GetBalanceAtRequest")
+	proto.RegisterType((*GetBalanceAtResult)(nil), "ledger.GetBalanceAtResult")
+	proto.RegisterType((*GetBalanceHistoryRequest)(nil), "ledger.GetBalanceHistoryRequest")
+	proto.RegisterType((*GetBalanceHistoryResult)(nil), "ledger.GetBalanceHistoryResult")
+	proto.RegisterType((*GetIncomingRequest)(nil), "ledger.GetIncomingRequest")
+	proto.RegisterType((*GetIncomingResult)(nil), "ledger.GetIncomingResult")
+	proto.RegisterType((*GetReceiveForSendRequest)(nil), "ledger.GetReceiveForSendRequest")
+	proto.RegisterType((*GetReceiveForSendResult)(nil), "ledger.GetReceiveForSendResult")
+	proto.RegisterEnum("ledger.Direction", Direction_name, Direction_value)
+	proto.RegisterEnum("ledger.ConfirmationState", ConfirmationState_name, ConfirmationState_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -504,6 +1179,12 @@ type LedgerClient interface {
 	VerifyTransaction(ctx context.Context, in *VerifyTransactionRequest, opts ...grpc.CallOption) (*VerifyTransactionResult, error)
 	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResult, error)
 	GetAddressStatement(ctx context.Context, in *GetAddressStatementRequest, opts ...grpc.CallOption) (*GetAddressStatementResult, error)
+	StreamAddressStatement(ctx context.Context, in *GetAddressStatementRequest, opts ...grpc.CallOption) (Ledger_StreamAddressStatementClient, error)
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Ledger_SubscribeClient, error)
+	GetBalanceAt(ctx context.Context, in *GetBalanceAtRequest, opts ...grpc.CallOption) (*GetBalanceAtResult, error)
+	GetBalanceHistory(ctx context.Context, in *GetBalanceHistoryRequest, opts ...grpc.CallOption) (*GetBalanceHistoryResult, error)
+	GetIncoming(ctx context.Context, in *GetIncomingRequest, opts ...grpc.CallOption) (*GetIncomingResult, error)
+	GetReceiveForSend(ctx context.Context, in *GetReceiveForSendRequest, opts ...grpc.CallOption) (*GetReceiveForSendResult, error)
 }
 
 type ledgerClient struct {
@@ -568,6 +1249,106 @@ func (c *ledgerClient) GetAddressStatement(ctx context.Context, in *GetAddressSt
 	return out, nil
 }
 
+func (c *ledgerClient) StreamAddressStatement(ctx context.Context, in *GetAddressStatementRequest, opts ...grpc.CallOption) (Ledger_StreamAddressStatementClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Ledger_serviceDesc.Streams[0], "/ledger.Ledger/StreamAddressStatement", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ledgerStreamAddressStatementClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Ledger_StreamAddressStatementClient interface {
+	Recv() (*Transaction, error)
+	grpc.ClientStream
+}
+
+type ledgerStreamAddressStatementClient struct {
+	grpc.ClientStream
+}
+
+func (x *ledgerStreamAddressStatementClient) Recv() (*Transaction, error) {
+	m := new(Transaction)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *ledgerClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Ledger_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Ledger_serviceDesc.Streams[1], "/ledger.Ledger/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ledgerSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Ledger_SubscribeClient interface {
+	Recv() (*TransactionEvent, error)
+	grpc.ClientStream
+}
+
+type ledgerSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *ledgerSubscribeClient) Recv() (*TransactionEvent, error) {
+	m := new(TransactionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *ledgerClient) GetBalanceAt(ctx context.Context, in *GetBalanceAtRequest, opts ...grpc.CallOption) (*GetBalanceAtResult, error) {
+	out := new(GetBalanceAtResult)
+	err := c.cc.Invoke(ctx, "/ledger.Ledger/GetBalanceAt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ledgerClient) GetBalanceHistory(ctx context.Context, in *GetBalanceHistoryRequest, opts ...grpc.CallOption) (*GetBalanceHistoryResult, error) {
+	out := new(GetBalanceHistoryResult)
+	err := c.cc.Invoke(ctx, "/ledger.Ledger/GetBalanceHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ledgerClient) GetIncoming(ctx context.Context, in *GetIncomingRequest, opts ...grpc.CallOption) (*GetIncomingResult, error) {
+	out := new(GetIncomingResult)
+	err := c.cc.Invoke(ctx, "/ledger.Ledger/GetIncoming", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ledgerClient) GetReceiveForSend(ctx context.Context, in *GetReceiveForSendRequest, opts ...grpc.CallOption) (*GetReceiveForSendResult, error) {
+	out := new(GetReceiveForSendResult)
+	err := c.cc.Invoke(ctx, "/ledger.Ledger/GetReceiveForSend", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LedgerServer is the server API for Ledger service.
 type LedgerServer interface {
 	Register(context.Context, *RegisterRequest) (*RegisterResult, error)
@@ -576,6 +1357,12 @@ type LedgerServer interface {
 	VerifyTransaction(context.Context, *VerifyTransactionRequest) (*VerifyTransactionResult, error)
 	Verify(context.Context, *VerifyRequest) (*VerifyResult, error)
 	GetAddressStatement(context.Context, *GetAddressStatementRequest) (*GetAddressStatementResult, error)
+	StreamAddressStatement(*GetAddressStatementRequest, Ledger_StreamAddressStatementServer) error
+	Subscribe(*SubscribeRequest, Ledger_SubscribeServer) error
+	GetBalanceAt(context.Context, *GetBalanceAtRequest) (*GetBalanceAtResult, error)
+	GetBalanceHistory(context.Context, *GetBalanceHistoryRequest) (*GetBalanceHistoryResult, error)
+	GetIncoming(context.Context, *GetIncomingRequest) (*GetIncomingResult, error)
+	GetReceiveForSend(context.Context, *GetReceiveForSendRequest) (*GetReceiveForSendResult, error)
 }
 
 func RegisterLedgerServer(s *grpc.Server, srv LedgerServer) {
@@ -690,6 +1477,120 @@ func _Ledger_GetAddressStatement_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Ledger_StreamAddressStatement_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetAddressStatementRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LedgerServer).StreamAddressStatement(m, &ledgerStreamAddressStatementServer{stream})
+}
+
+type Ledger_StreamAddressStatementServer interface {
+	Send(*Transaction) error
+	grpc.ServerStream
+}
+
+type ledgerStreamAddressStatementServer struct {
+	grpc.ServerStream
+}
+
+func (x *ledgerStreamAddressStatementServer) Send(m *Transaction) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Ledger_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LedgerServer).Subscribe(m, &ledgerSubscribeServer{stream})
+}
+
+type Ledger_SubscribeServer interface {
+	Send(*TransactionEvent) error
+	grpc.ServerStream
+}
+
+type ledgerSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *ledgerSubscribeServer) Send(m *TransactionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Ledger_GetBalanceAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceAtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServer).GetBalanceAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ledger.Ledger/GetBalanceAt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServer).GetBalanceAt(ctx, req.(*GetBalanceAtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ledger_GetBalanceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServer).GetBalanceHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ledger.Ledger/GetBalanceHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServer).GetBalanceHistory(ctx, req.(*GetBalanceHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ledger_GetIncoming_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIncomingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServer).GetIncoming(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ledger.Ledger/GetIncoming",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServer).GetIncoming(ctx, req.(*GetIncomingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ledger_GetReceiveForSend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReceiveForSendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServer).GetReceiveForSend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ledger.Ledger/GetReceiveForSend",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServer).GetReceiveForSend(ctx, req.(*GetReceiveForSendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Ledger_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "ledger.Ledger",
 	HandlerType: (*LedgerServer)(nil),
@@ -718,41 +1619,107 @@ var _Ledger_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetAddressStatement",
 			Handler:    _Ledger_GetAddressStatement_Handler,
 		},
+		{
+			MethodName: "GetBalanceAt",
+			Handler:    _Ledger_GetBalanceAt_Handler,
+		},
+		{
+			MethodName: "GetBalanceHistory",
+			Handler:    _Ledger_GetBalanceHistory_Handler,
+		},
+		{
+			MethodName: "GetIncoming",
+			Handler:    _Ledger_GetIncoming_Handler,
+		},
+		{
+			MethodName: "GetReceiveForSend",
+			Handler:    _Ledger_GetReceiveForSend_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamAddressStatement",
+			Handler:       _Ledger_StreamAddressStatement_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Ledger_Subscribe_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "ledger/ledgerserver.proto",
 }
 
 func init() {
-	proto.RegisterFile("ledger/ledgerserver.proto", fileDescriptor_ledgerserver_fe3ec0588a3116af)
-}
-
-var fileDescriptor_ledgerserver_fe3ec0588a3116af = []byte{
-	// 415 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x54, 0x5b, 0x6b, 0xe2, 0x40,
-	0x14, 0x5e, 0x75, 0xc9, 0xea, 0xd9, 0x5d, 0x77, 0x77, 0xd4, 0x35, 0x86, 0x5d, 0xaa, 0x29, 0x85,
-	0x82, 0x60, 0xa9, 0xa5, 0xed, 0x43, 0x29, 0x52, 0x5f, 0x7c, 0x11, 0x0a, 0xa9, 0x94, 0xd2, 0x42,
-	0x21, 0x35, 0xa7, 0x1a, 0xb0, 0x49, 0x9d, 0x19, 0x25, 0xfd, 0xc7, 0xfd, 0x19, 0xc5, 0x99, 0x49,
-	0xbd, 0xe4, 0x52, 0x9f, 0xfa, 0xa4, 0x99, 0xf3, 0x5d, 0xce, 0x99, 0xf3, 0x31, 0x50, 0x9b, 0xa0,
-	0x33, 0x42, 0x7a, 0x20, 0x7f, 0x18, 0xd2, 0x39, 0xd2, 0xd6, 0x33, 0xf5, 0xb9, 0x4f, 0x34, 0x79,
-	0x66, 0xe8, 0x0a, 0xc2, 0xa9, 0xed, 0x31, 0x7b, 0xc8, 0x5d, 0xdf, 0x93, 0x08, 0x73, 0x0a, 0xbf,
-	0x2c, 0x1c, 0xb9, 0x8c, 0x23, 0xb5, 0x70, 0x3a, 0x43, 0xc6, 0x49, 0x13, 0x34, 0x86, 0x9e, 0x33,
-	0x08, 0xf4, 0x4c, 0x3d, 0xb3, 0xff, 0xbd, 0x5d, 0x6a, 0x49, 0x76, 0x6b, 0xb0, 0x64, 0x5b, 0x0a,
-	0x42, 0x0e, 0xa1, 0x40, 0x71, 0x88, 0xee, 0x1c, 0x07, 0x81, 0x9e, 0x4d, 0xc6, 0x2f, 0x51, 0xe6,
-	0x6f, 0x28, 0x2e, 0x2d, 0xd9, 0x6c, 0xc2, 0xcd, 0x63, 0xa8, 0xf5, 0x90, 0xf7, 0x6d, 0xc6, 0x57,
-	0x29, 0xaa, 0x1d, 0x1d, 0xbe, 0xd9, 0x8e, 0x43, 0x91, 0x31, 0xd1, 0x4f, 0xc1, 0x0a, 0x3f, 0xcd,
-	0x0e, 0xe8, 0x71, 0xb4, 0x85, 0x24, 0xd9, 0x85, 0x2c, 0x4f, 0x1d, 0x20, 0xcb, 0x03, 0xb3, 0x09,
-	0x95, 0x1e, 0xc6, 0x79, 0x12, 0xf8, 0x3a, 0xb6, 0xd9, 0x58, 0x19, 0x8a, 0xff, 0xe6, 0x19, 0x94,
-	0x37, 0xc1, 0xdb, 0x3b, 0x75, 0x40, 0xbf, 0x46, 0xea, 0x3e, 0xbe, 0xc4, 0x98, 0x6d, 0x25, 0x50,
-	0x83, 0x6a, 0x8c, 0x80, 0xb8, 0x3d, 0x1f, 0x7e, 0xca, 0xd2, 0x67, 0x2d, 0xb0, 0x08, 0x3f, 0x42,
-	0x43, 0xd1, 0xc0, 0x09, 0x18, 0x3d, 0xe4, 0x17, 0x72, 0x2b, 0x57, 0xdc, 0xe6, 0xf8, 0x84, 0x1e,
-	0xff, 0x78, 0x7f, 0x5d, 0xb1, 0xf6, 0x28, 0x4f, 0x5c, 0xeb, 0x1e, 0xe4, 0x78, 0xb0, 0xa0, 0xe4,
-	0x92, 0x3a, 0x5a, 0xd4, 0xdb, 0xaf, 0x39, 0xd0, 0xfa, 0xa2, 0x46, 0xce, 0x21, 0x1f, 0xe6, 0x8a,
-	0x54, 0x43, 0xc2, 0x46, 0xb8, 0x8d, 0xbf, 0xd1, 0x82, 0x98, 0xe1, 0x0b, 0xb9, 0x03, 0x12, 0x4d,
-	0x13, 0x69, 0x84, 0xf8, 0xc4, 0x80, 0x1a, 0xf5, 0x34, 0x88, 0x12, 0xbf, 0x84, 0xe2, 0x7a, 0x78,
-	0xc8, 0xff, 0x15, 0x56, 0x8c, 0xe8, 0xbf, 0xa4, 0xb2, 0x12, 0xbc, 0x81, 0x3f, 0x91, 0x3c, 0x90,
-	0xf7, 0x4e, 0x92, 0xb2, 0x66, 0xec, 0xa4, 0x20, 0x94, 0xf2, 0x29, 0x68, 0xb2, 0x48, 0x2a, 0xeb,
-	0xe0, 0x50, 0xa3, 0xbc, 0x79, 0xac, 0x88, 0xf7, 0x50, 0x8a, 0x59, 0x27, 0x31, 0x57, 0x26, 0x49,
-	0xc8, 0x88, 0xd1, 0x48, 0xc5, 0x48, 0xfd, 0x6e, 0xfe, 0x56, 0x3d, 0x67, 0x0f, 0x9a, 0x78, 0xbb,
-	0x8e, 0xde, 0x02, 0x00, 0x00, 0xff, 0xff, 0x94, 0xa1, 0x0e, 0x1d, 0xfa, 0x04, 0x00, 0x00,
+	proto.RegisterFile("ledger/ledgerserver.proto", fileDescriptor_ledgerserver_7330f844a6a49c3b)
+}
+
+var fileDescriptor_ledgerserver_7330f844a6a49c3b = []byte{
+	// 1028 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x57, 0xfd, 0x4e, 0xe3, 0x46,
+	0x10, 0xc7, 0x0e, 0xe4, 0x63, 0x80, 0x5c, 0x58, 0x38, 0x70, 0x5c, 0x2a, 0x72, 0xee, 0x87, 0xe8,
+	0x51, 0x85, 0x96, 0xaa, 0xaa, 0xd4, 0xaa, 0x77, 0x0a, 0x1f, 0x09, 0xa8, 0x57, 0xa8, 0x36, 0x69,
+	0xaf, 0x1f, 0xd2, 0x55, 0x4e, 0x32, 0x04, 0xab, 0xd8, 0xce, 0xad, 0x37, 0x28, 0xfc, 0xd7, 0x87,
+	0xe8, 0x9b, 0xf4, 0x01, 0xfa, 0x00, 0x7d, 0xa9, 0xca, 0xbb, 0xeb, 0xd8, 0x49, 0x9c, 0x90, 0xf2,
+	0xc7, 0xfd, 0x05, 0x33, 0xf3, 0xdb, 0x99, 0xdf, 0xce, 0x78, 0x66, 0x36, 0x50, 0xbe, 0xc5, 0x6e,
+	0x0f, 0xd9, 0xa1, 0xfc, 0x13, 0x20, 0xbb, 0x43, 0x56, 0xed, 0x33, 0x9f, 0xfb, 0x24, 0x2b, 0x75,
+	0xa6, 0xa1, 0x20, 0x9c, 0xd9, 0x5e, 0x60, 0x77, 0xb8, 0xe3, 0x7b, 0x12, 0x61, 0xbd, 0x85, 0x27,
+	0x14, 0x7b, 0x4e, 0xc0, 0x91, 0x51, 0x7c, 0x3b, 0xc0, 0x80, 0x93, 0x03, 0xc8, 0x06, 0xe8, 0x75,
+	0x5b, 0x43, 0x43, 0xab, 0x68, 0xfb, 0xab, 0x47, 0x9b, 0x55, 0x79, 0xba, 0xda, 0x8a, 0x4f, 0x53,
+	0x05, 0x21, 0x9f, 0x43, 0x81, 0x61, 0x07, 0x9d, 0x3b, 0x6c, 0x0d, 0x0d, 0x7d, 0x36, 0x3e, 0x46,
+	0x59, 0x25, 0x28, 0xc6, 0x21, 0x83, 0xc1, 0x2d, 0xb7, 0xbe, 0x84, 0x72, 0x03, 0xf9, 0x2b, 0x3b,
+	0xe0, 0xc9, 0x23, 0x8a, 0x8e, 0x01, 0x39, 0xbb, 0xdb, 0x65, 0x18, 0x04, 0x82, 0x4f, 0x81, 0x46,
+	0xa2, 0xf5, 0x12, 0x8c, 0xb4, 0x63, 0xa1, 0x4b, 0xf2, 0x01, 0xe8, 0x7c, 0xee, 0x05, 0x74, 0x3e,
+	0xb4, 0x0e, 0xe0, 0x69, 0x03, 0xd3, 0x62, 0x12, 0x58, 0xbe, 0xb1, 0x83, 0x1b, 0x15, 0x50, 0xfc,
+	0x6f, 0x7d, 0x03, 0x5b, 0x0d, 0x7c, 0x6c, 0xa4, 0xd7, 0x60, 0xfc, 0x84, 0xcc, 0xb9, 0xbe, 0x4f,
+	0x09, 0xb6, 0x88, 0x03, 0xb2, 0x0d, 0xd9, 0x80, 0xfb, 0x0c, 0xbb, 0x22, 0xc9, 0x79, 0xaa, 0x24,
+	0xab, 0x0c, 0x3b, 0x29, 0x8e, 0x45, 0x56, 0x7d, 0x58, 0x97, 0xa6, 0x77, 0x55, 0xd8, 0x22, 0xac,
+	0x45, 0x01, 0x05, 0x81, 0xbf, 0x75, 0x30, 0x1b, 0xc8, 0x6b, 0xb2, 0x5c, 0x4d, 0x6e, 0x73, 0x74,
+	0xd1, 0xe3, 0x0f, 0x16, 0x96, 0xec, 0x42, 0x21, 0xe0, 0x36, 0xe3, 0xe7, 0x61, 0x0d, 0x74, 0x61,
+	0x8b, 0x15, 0xa4, 0x02, 0xab, 0x52, 0x40, 0xa7, 0x77, 0xc3, 0x8d, 0x4c, 0x45, 0xdb, 0xcf, 0xd0,
+	0xa4, 0x8a, 0x6c, 0xc1, 0xca, 0xad, 0xe3, 0x3a, 0xdc, 0x58, 0xae, 0x68, 0xfb, 0xeb, 0x54, 0x0a,
+	0xe4, 0x10, 0x0a, 0x5d, 0x87, 0xa1, 0xa0, 0x6d, 0xac, 0x54, 0xb4, 0xfd, 0xe2, 0xd1, 0x46, 0x74,
+	0xa3, 0xd3, 0xc8, 0x40, 0x63, 0x0c, 0xf9, 0x14, 0x96, 0xf9, 0x7d, 0x1f, 0x8d, 0xac, 0xc0, 0x1a,
+	0x29, 0xb7, 0xaf, 0xb6, 0xee, 0xfb, 0x48, 0x05, 0x8a, 0x7c, 0x08, 0xeb, 0xd7, 0xcc, 0x77, 0x5b,
+	0x8e, 0x8b, 0x01, 0xb7, 0xdd, 0xbe, 0x91, 0x13, 0xc4, 0xc6, 0x95, 0x21, 0x79, 0xee, 0xc7, 0x98,
+	0xbc, 0x24, 0x9f, 0x50, 0x59, 0x6f, 0xa0, 0x9c, 0x9a, 0x34, 0xf1, 0xb1, 0x7d, 0x04, 0x19, 0x3e,
+	0x0c, 0xf3, 0x95, 0x99, 0x55, 0x8f, 0xd0, 0x4e, 0x4c, 0xc8, 0x7b, 0x38, 0x4c, 0xe6, 0x6f, 0x24,
+	0x5b, 0xff, 0x68, 0x50, 0x6a, 0x0e, 0xda, 0x41, 0x87, 0x39, 0x6d, 0x8c, 0x6a, 0xb1, 0x0b, 0x05,
+	0x95, 0x7c, 0x94, 0xde, 0x0b, 0x34, 0x56, 0x8c, 0x12, 0xa1, 0x2f, 0x94, 0x88, 0x43, 0x58, 0x09,
+	0x42, 0xda, 0xa2, 0x32, 0xc5, 0xa3, 0x72, 0x04, 0x3f, 0xf1, 0xbd, 0x6b, 0x87, 0xb9, 0x76, 0x88,
+	0x17, 0xf7, 0xa2, 0x12, 0x17, 0x7e, 0xdb, 0x0c, 0x83, 0x81, 0x8b, 0xa2, 0x5e, 0x79, 0xaa, 0xa4,
+	0x50, 0xdf, 0x19, 0xb0, 0xc0, 0x67, 0xa2, 0x5a, 0xcb, 0x54, 0x49, 0xd6, 0x9f, 0x1a, 0x94, 0x12,
+	0xb1, 0xcf, 0xee, 0xd0, 0xe3, 0x09, 0xb0, 0x96, 0x04, 0xc7, 0x6c, 0xf4, 0x05, 0xd9, 0xc8, 0x76,
+	0xcc, 0xcc, 0xef, 0x67, 0x17, 0x72, 0xc7, 0xf6, 0xad, 0xed, 0x75, 0x30, 0x4c, 0x1d, 0x1f, 0xd5,
+	0x53, 0x13, 0xf5, 0x8c, 0x15, 0x21, 0xad, 0x1b, 0xf9, 0x9d, 0xea, 0xc2, 0xa4, 0xa4, 0xd1, 0x84,
+	0xc9, 0xc4, 0x13, 0x26, 0x6c, 0x88, 0xb6, 0x74, 0x2a, 0x12, 0xa1, 0xd1, 0x48, 0xb4, 0x10, 0x36,
+	0x1b, 0xc8, 0x55, 0xc4, 0xda, 0x62, 0x1d, 0x14, 0x93, 0xd2, 0x67, 0x93, 0xca, 0x24, 0x49, 0x59,
+	0x2f, 0x81, 0x8c, 0x87, 0x11, 0xdf, 0xdc, 0x27, 0x31, 0x2d, 0x39, 0x37, 0x9e, 0x44, 0x59, 0x51,
+	0xc8, 0x98, 0xe7, 0x5f, 0x9a, 0x18, 0xc9, 0x4a, 0x7f, 0xee, 0x84, 0x43, 0xea, 0xfe, 0x61, 0xb6,
+	0x53, 0xad, 0xa3, 0x2f, 0xd0, 0x3a, 0x99, 0xa9, 0xd6, 0x09, 0xef, 0xd5, 0x1e, 0x74, 0xfe, 0x40,
+	0xd9, 0xf8, 0x19, 0xaa, 0x24, 0xab, 0x0e, 0x3b, 0x29, 0xac, 0xc4, 0xe5, 0x0e, 0x20, 0xaf, 0xc8,
+	0x47, 0x5d, 0x35, 0x75, 0xbb, 0x11, 0xc0, 0x3a, 0x17, 0xf9, 0xb9, 0xf0, 0x3a, 0xbe, 0xeb, 0x78,
+	0xbd, 0x87, 0xef, 0x65, 0x40, 0xae, 0x8f, 0x5e, 0xd7, 0xf1, 0x7a, 0x6a, 0x6a, 0x47, 0xa2, 0xf5,
+	0x35, 0x6c, 0x8c, 0x79, 0xfa, 0x1f, 0xcd, 0x6d, 0x55, 0x45, 0x8e, 0xa9, 0x1c, 0xbb, 0x75, 0x9f,
+	0x35, 0xd1, 0xeb, 0xce, 0x5b, 0x5c, 0x2f, 0x60, 0x27, 0x05, 0xbf, 0xf0, 0xee, 0x7a, 0xfe, 0x31,
+	0x14, 0x46, 0xe3, 0x91, 0xac, 0x42, 0xae, 0x7e, 0x45, 0x5f, 0xd7, 0xe8, 0x69, 0x69, 0x89, 0xac,
+	0x41, 0xfe, 0xb8, 0x76, 0xf2, 0x9d, 0x90, 0xb4, 0xe7, 0x2f, 0x60, 0x63, 0xaa, 0xa9, 0xc8, 0x3a,
+	0x14, 0x6a, 0x97, 0xbf, 0xfc, 0xde, 0x6c, 0xd5, 0x5a, 0x67, 0xa5, 0xa5, 0xf0, 0xf8, 0x0f, 0x67,
+	0x97, 0xa7, 0x17, 0x97, 0x8d, 0x92, 0x16, 0xda, 0x4e, 0xae, 0x2e, 0xeb, 0x17, 0xf4, 0xfb, 0xb3,
+	0xd3, 0x92, 0x7e, 0xf4, 0x6f, 0x0e, 0xb2, 0xaf, 0x04, 0x05, 0xf2, 0x2d, 0xe4, 0xa3, 0x27, 0x02,
+	0xd9, 0x89, 0x78, 0x4d, 0xbc, 0x53, 0xcc, 0xed, 0x69, 0x83, 0x58, 0x3b, 0x4b, 0xe4, 0x37, 0x20,
+	0xd3, 0x0f, 0x03, 0xf2, 0x2c, 0xc2, 0xcf, 0x7c, 0x6b, 0x98, 0x95, 0x79, 0x10, 0xe5, 0xfc, 0x0a,
+	0x8a, 0xe3, 0xef, 0x00, 0xf2, 0x7e, 0xe2, 0x54, 0x8a, 0xd3, 0xdd, 0x59, 0x66, 0xe5, 0xf0, 0x67,
+	0xd8, 0x98, 0x5a, 0xe1, 0x64, 0xc4, 0x64, 0xd6, 0xb3, 0xc1, 0xdc, 0x9b, 0x83, 0x50, 0x9e, 0xbf,
+	0x82, 0xac, 0x34, 0x92, 0xa7, 0xe3, 0xe0, 0xc8, 0xc7, 0xd6, 0xa4, 0x5a, 0x1d, 0x7c, 0x03, 0x9b,
+	0x29, 0x3b, 0x88, 0x58, 0x89, 0x9b, 0xcc, 0xd8, 0xea, 0xe6, 0xb3, 0xb9, 0x18, 0xe5, 0xff, 0x47,
+	0xd8, 0x6e, 0x72, 0x86, 0xb6, 0xfb, 0xa8, 0x10, 0x69, 0x5f, 0xaa, 0xb5, 0xf4, 0x99, 0x46, 0x4e,
+	0xa0, 0x30, 0xda, 0x6c, 0x64, 0xb4, 0xa6, 0x26, 0x97, 0x9d, 0x99, 0xb6, 0xc0, 0xc4, 0x12, 0x11,
+	0x4e, 0x2e, 0x60, 0x2d, 0x39, 0x04, 0xc9, 0x7b, 0x09, 0x46, 0x93, 0x13, 0xd8, 0x34, 0xd3, 0x8d,
+	0x71, 0x65, 0xa7, 0xe6, 0x0e, 0xa9, 0x4c, 0x1f, 0x19, 0x1f, 0x94, 0xe6, 0xde, 0x1c, 0x84, 0xf2,
+	0x5c, 0x87, 0xd5, 0xc4, 0xfc, 0x20, 0x49, 0x1a, 0x13, 0xe3, 0xc9, 0x2c, 0xa7, 0xda, 0xc6, 0x18,
+	0x8e, 0xcf, 0x86, 0x31, 0x86, 0xa9, 0x63, 0xc6, 0xdc, 0x9b, 0x83, 0x90, 0x9e, 0x8f, 0xf3, 0xbf,
+	0xaa, 0x1f, 0x1f, 0xed, 0xac, 0xf8, 0xa5, 0xf1, 0xc5, 0x7f, 0x03, 0x00, 0x27, 0x21, 0x87, 0x2a,
+	0xa8, 0x0c, 0x00, 0x00,
 }
@@ -0,0 +1,175 @@
+package ledger
+
+import (
+	"strconv"
+)
+
+// The link index maps every send transaction to the receive (or open) transaction that claims it, and lists the
+// send transactions to every address in the order they were indexed, so the transfers to an address are found
+// without reading the chains of the senders. Removed send transactions leave a gap in the list of their address.
+
+func receiveKey(sendHash string) string {
+	return "receive/" + sendHash
+}
+
+func incomingCountKey(address string) string {
+	return "incoming/" + address
+}
+
+func incomingKey(address string, n int64) string {
+	return "incoming/" + address + "/" + strconv.FormatInt(n, 10)
+}
+
+func incomingPositionKey(sendHash string) string {
+	return "incomingposition/" + sendHash
+}
+
+// IndexChains rebuilds the index when it was built by another version of it, or before it had one, from the chains
+// of the whole ledger, so the keys added by a later version exist for every chain. The next calls return at once.
+func (ts *TransactionStore) IndexChains() error {
+	version, err := ts.getCount(versionKey)
+	if err != nil || version == indexVersion {
+		return err
+	}
+
+	err = ts.clearIndex()
+	if err != nil {
+		return err
+	}
+	frontiers, err := ts.GetFrontiers()
+	if err != nil {
+		return err
+	}
+	for address := range frontiers {
+		_, err = ts.GetHeadHeight(address)
+		if err != nil {
+			return err
+		}
+	}
+	return ts.index.Put(versionKey, []byte(strconv.FormatInt(indexVersion, 10)))
+}
+
+// GetIncoming returns the send transactions to address, only the ones not received yet if pending is set.
+func (ts *TransactionStore) GetIncoming(address string, pending bool) ([]*Transaction, error) {
+	// The receive transactions of the chain must be indexed to tell the pending send transactions.
+	_, err := ts.GetHeadHeight(address)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := ts.getCount(incomingCountKey(address))
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]*Transaction, 0)
+	for n := int64(1); n <= count; n++ {
+		hash, ok, err := ts.index.Get(incomingKey(address, n))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if pending {
+			_, ok, err = ts.index.Get(receiveKey(string(hash)))
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				continue
+			}
+		}
+		tx, ok, err := ts.GetTransaction(string(hash))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrTransactionNotFound
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// GetReceiveForSend returns the receive (or open) transaction that claims the send transaction sendHash.
+func (ts *TransactionStore) GetReceiveForSend(sendHash string) (*Transaction, bool, error) {
+	send, _, err := ts.GetTransaction(sendHash)
+	if err != nil || send == nil || send.Type != Transaction_SEND {
+		return nil, false, err
+	}
+
+	// The receive transaction is indexed with the chain of the address the amount was sent to.
+	_, err = ts.GetHeadHeight(send.Link)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hash, ok, err := ts.index.Get(receiveKey(send.Hash))
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return ts.GetTransaction(string(hash))
+}
+
+// indexLink adds tx to the link index.
+func (ts *TransactionStore) indexLink(tx *Transaction) error {
+	if tx.Link == "" {
+		return nil
+	}
+	switch tx.Type {
+	case Transaction_OPEN, Transaction_RECEIVE:
+		return ts.index.Put(receiveKey(tx.Link), []byte(tx.Hash))
+	case Transaction_SEND:
+		_, ok, err := ts.index.Get(incomingPositionKey(tx.Hash))
+		if err != nil || ok {
+			return err
+		}
+		n, err := ts.getCount(incomingCountKey(tx.Link))
+		if err != nil {
+			return err
+		}
+		n++
+		err = ts.index.Put(incomingKey(tx.Link, n), []byte(tx.Hash))
+		if err != nil {
+			return err
+		}
+		err = ts.index.Put(incomingPositionKey(tx.Hash), []byte(strconv.FormatInt(n, 10)))
+		if err != nil {
+			return err
+		}
+		return ts.index.Put(incomingCountKey(tx.Link), []byte(strconv.FormatInt(n, 10)))
+	}
+	return nil
+}
+
+// unindexLink removes tx from the link index.
+func (ts *TransactionStore) unindexLink(tx *Transaction) error {
+	if tx.Link == "" {
+		return nil
+	}
+	switch tx.Type {
+	case Transaction_OPEN, Transaction_RECEIVE:
+		return ts.index.Delete(receiveKey(tx.Link))
+	case Transaction_SEND:
+		n, err := ts.getCount(incomingPositionKey(tx.Hash))
+		if err != nil || n == 0 {
+			return err
+		}
+		err = ts.index.Delete(incomingKey(tx.Link, n))
+		if err != nil {
+			return err
+		}
+		return ts.index.Delete(incomingPositionKey(tx.Hash))
+	}
+	return nil
+}
+
+// getCount returns the number kept in the index at key, 0 if there is none.
+func (ts *TransactionStore) getCount(key string) (int64, error) {
+	value, ok, err := ts.index.Get(key)
+	if err != nil || !ok {
+		return 0, err
+	}
+	return strconv.ParseInt(string(value), 10, 64)
+}
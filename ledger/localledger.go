@@ -2,18 +2,52 @@ package ledger
 
 import (
 	"github.com/msaldanha/realChain/address"
+	"github.com/msaldanha/realChain/keyvaluestore"
+	"github.com/msaldanha/realChain/logging"
+	"github.com/msaldanha/realChain/metrics"
+	log "github.com/sirupsen/logrus"
 	"math"
+	"sync"
+	"time"
 )
 
+var powVerification = metrics.NewHistogram("realchain_pow_verification_duration_seconds",
+	"Duration of the proof of work verifications of the transactions.", metrics.DefaultBuckets)
+
 type LocalLedger struct {
 	ts        *TransactionStore
+	events    *EventBus
+	logger    *log.Entry
+	// mtx serializes writes, which may come from the server and from the background sync at the same time.
+	mtx       sync.Mutex
 }
 
+// NewLocalLedger creates a ledger whose events are logged in memory.
 func NewLocalLedger(txStore *TransactionStore) *LocalLedger {
-	return &LocalLedger{ts:txStore}
+	return NewLocalLedgerWithEvents(txStore, NewEventBus(keyvaluestore.NewMemoryKeyValueStore()))
+}
+
+// NewLocalLedgerWithEvents creates a ledger that publishes the transactions it stores to events.
+func NewLocalLedgerWithEvents(txStore *TransactionStore, events *EventBus) *LocalLedger {
+	return &LocalLedger{ts: txStore, events: events, logger: logging.Component(log.StandardLogger(), "ledger")}
+}
+
+// SetLogger sets the logger of the ledger and of its event bus.
+func (ld *LocalLedger) SetLogger(logger log.FieldLogger) {
+	ld.logger = logging.Component(logger, "ledger")
+	ld.events.SetLogger(logger)
+}
+
+// Events returns the bus the ledger events are fired on. Handlers registered on it are called for the
+// transactions stored from then on.
+func (ld *LocalLedger) Events() *EventBus {
+	return ld.events
 }
 
 func (ld *LocalLedger) Initialize(genesisTx *Transaction) error {
+	ld.mtx.Lock()
+	defer ld.mtx.Unlock()
+
 	if !ld.ts.IsEmpty() {
 		return ErrLedgerAlreadyInitialized
 	}
@@ -22,7 +56,13 @@ func (ld *LocalLedger) Initialize(genesisTx *Transaction) error {
 }
 
 func (ld *LocalLedger) Register(sendTx *Transaction, receiveTx *Transaction) error {
+	ld.mtx.Lock()
+	defer ld.mtx.Unlock()
+
 	if err := ld.Verify(sendTx, receiveTx); err != nil {
+		if err == ErrPreviousTransactionIsNotHead {
+			ld.reportForks(sendTx, receiveTx)
+		}
 		return err
 	}
 
@@ -65,6 +105,46 @@ func (ld *LocalLedger) GetAddressStatement(address string) ([]*Transaction, erro
 	return txChain, nil
 }
 
+// GetAddressStatementPage returns the transactions of the address chain selected by query and the hash of the
+// transaction the next page starts at, empty if there are no more.
+func (ld *LocalLedger) GetAddressStatementPage(address string, query StatementQuery) ([]*Transaction, string, error) {
+	return ld.ts.GetStatement(address, query)
+}
+
+// GetBalanceAt returns the balance of address after the transaction at height or, if height is 0, after the
+// transactions older than timestamp.
+func (ld *LocalLedger) GetBalanceAt(address string, timestamp, height int64) (*Balance, error) {
+	return ld.ts.GetBalanceAt(address, timestamp, height)
+}
+
+// GetBalanceHistory returns the balances of address at the end of every bucket from the timestamp from up to to.
+func (ld *LocalLedger) GetBalanceHistory(address string, from, to, bucket int64) ([]*Balance, error) {
+	return ld.ts.GetBalanceHistory(address, from, to, bucket)
+}
+
+// GetIncoming returns the send transactions to address, only the ones not received yet if pending is set.
+func (ld *LocalLedger) GetIncoming(address string, pending bool) ([]*Transaction, error) {
+	return ld.ts.GetIncoming(address, pending)
+}
+
+// GetReceiveForSend returns the receive (or open) transaction that claims the send transaction sendHash, or nil
+// if it was not received.
+func (ld *LocalLedger) GetReceiveForSend(sendHash string) (*Transaction, error) {
+	tx, _, err := ld.ts.GetReceiveForSend(sendHash)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (ld *LocalLedger) GetFrontiers() (map[string]string, error) {
+	return ld.ts.GetFrontiers()
+}
+
+func (ld *LocalLedger) GetStats() (*Stats, error) {
+	return ld.ts.GetStats()
+}
+
 func (ld *LocalLedger) VerifyTransaction(tx *Transaction, isNew bool) error {
 	if ok, err := ld.verifyAddress(tx); !ok {
 		return err
@@ -172,33 +252,105 @@ func (ld *LocalLedger) verifyTransactions(sendTx *Transaction, receiveTx *Transa
 }
 
 func (ld *LocalLedger) saveTransaction(tx *Transaction) error {
+	err := ld.storeTransaction(tx)
+	if err != nil {
+		return err
+	}
+	ld.publish(tx, nil)
+	return nil
+}
+
+func (ld *LocalLedger) storeTransaction(tx *Transaction) error {
 	_, err := ld.ts.Store(tx)
 	if err != nil {
 		return err
 	}
+
+	ld.logger.WithFields(log.Fields{logging.TxField: tx.Hash, logging.AddressField: tx.Address}).
+		Debugf("Stored %s transaction", tx.Type)
 	return nil
 }
 
+// publish logs a stored transaction and fires its events. The send transaction claimed by a receive (or open)
+// transaction is passed along as send.
+func (ld *LocalLedger) publish(tx *Transaction, send *Transaction) {
+	// The transaction is already stored, so failing to publish it must not fail the write.
+	err := ld.events.Publish(tx)
+	if err != nil {
+		ld.logger.WithFields(log.Fields{logging.TxField: tx.Hash, logging.AddressField: tx.Address}).
+			Errorf("Failed to publish transaction: %s", err)
+	}
+
+	switch {
+	case tx.Type == Transaction_SEND:
+		ld.fire(SendPending, tx, nil)
+	case send != nil:
+		ld.fire(ReceiveClaimed, tx, send)
+	}
+}
+
+// saveTransactions stores the transactions of a transfer. Their events are published only once both are stored,
+// so a send transaction rolled back because its receive transaction failed to be stored is never published.
 func (ld *LocalLedger) saveTransactions(sendTx *Transaction, receiveTx *Transaction) error {
 	err := ld.verifyTransactions(sendTx, receiveTx)
 	if err != nil {
 		return err
 	}
 
-	err = ld.saveTransaction(sendTx)
+	err = ld.storeTransaction(sendTx)
 	if err != nil {
 		return err
 	}
 
-	err = ld.saveTransaction(receiveTx)
+	err = ld.storeTransaction(receiveTx)
 	if err != nil {
+		ld.rollback(sendTx)
 		return err
 	}
 
+	ld.publish(sendTx, nil)
+	ld.publish(receiveTx, sendTx)
 	return nil
 }
 
+// rollback removes a stored transaction whose transfer failed to be stored. The transaction was not published,
+// so it is neither in the event log nor sent to the subscribers.
+func (ld *LocalLedger) rollback(tx *Transaction) {
+	err := ld.ts.Remove(tx)
+	if err != nil {
+		ld.logger.WithFields(log.Fields{logging.TxField: tx.Hash, logging.AddressField: tx.Address}).
+			Errorf("Failed to roll back transaction: %s", err)
+		return
+	}
+	ld.fire(Rollback, tx, nil)
+}
+
+// reportForks fires ForkDetected for the transactions whose previous transaction is not the head of their chain.
+func (ld *LocalLedger) reportForks(txs ...*Transaction) {
+	for _, tx := range txs {
+		if tx.Type == Transaction_OPEN {
+			continue
+		}
+		head, err := ld.ts.Retrieve(tx.Address)
+		if err != nil || head == nil {
+			continue
+		}
+		if head.Hash != tx.Previous {
+			ld.fire(ForkDetected, tx, head)
+		}
+	}
+}
+
+func (ld *LocalLedger) fire(eventType EventType, tx *Transaction, related *Transaction) {
+	err := ld.events.Fire(eventType, tx, related)
+	if err != nil {
+		ld.logger.WithFields(log.Fields{logging.TxField: tx.Hash, logging.AddressField: tx.Address}).
+			Errorf("Failed to fire %s event: %s", eventType, err)
+	}
+}
+
 func (ld *LocalLedger) verifyPow(tx *Transaction) bool {
+	defer powVerification.Since(time.Now())
 	ok, _ := tx.VerifyPow()
 	return ok
 }
@@ -207,6 +359,8 @@ func (ld *LocalLedger) isPendingTransaction(tx *Transaction) (bool, error) {
 	if tx.Type != Transaction_SEND {
 		return false, nil
 	}
+	// The chain of the address the amount was sent to is walked rather than read from the link index, so the votes
+	// never depend on how the index was built.
 	target, err := ld.GetLastTransaction(string(tx.Link))
 	if err != nil {
 		return false, err
@@ -0,0 +1,47 @@
+package ledger
+
+import (
+	"github.com/msaldanha/realChain/errors"
+)
+
+const (
+	ErrStatementStartNotInChain = errors.Error("statement start not in address chain")
+)
+
+// StatementQuery selects the transactions of an address chain. The scan starts at StartHash or at the
+// transaction at StartHeight, the open transaction being at height 1, and goes in Direction, towards the head
+// (FORWARD) or towards the open transaction (BACKWARD). Without a start, it starts at the open transaction going
+// forward and at the head going backward.
+type StatementQuery struct {
+	StartHash   string
+	StartHeight int64
+	Direction   Direction
+	// Limit is the maximum number of transactions returned, 0 meaning no limit.
+	Limit int
+	// Type, unless ZERO, and the timestamps From (inclusive) and To (exclusive), unless 0, filter the scanned
+	// transactions.
+	Type Transaction_Type
+	From int64
+	To   int64
+}
+
+// NewStatementQuery returns the query of a statement request.
+func NewStatementQuery(request *GetAddressStatementRequest) StatementQuery {
+	return StatementQuery{StartHash: request.StartHash, StartHeight: request.StartHeight,
+		Direction: request.Direction, Limit: int(request.Limit), Type: request.Type, From: request.FromTimestamp,
+		To: request.ToTimestamp}
+}
+
+// Matches tells whether tx passes the filters of the query.
+func (q StatementQuery) Matches(tx *Transaction) bool {
+	if q.Type != Transaction_ZERO && tx.Type != q.Type {
+		return false
+	}
+	if q.From != 0 && tx.Timestamp < q.From {
+		return false
+	}
+	if q.To != 0 && tx.Timestamp >= q.To {
+		return false
+	}
+	return true
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/msaldanha/realChain/address"
 	"github.com/msaldanha/realChain/crypto"
-	"log"
 	"math"
 	"math/big"
 	"strconv"
@@ -241,11 +240,7 @@ func getTarget() *big.Int {
 }
 
 func int64ToBytes(num int64) []byte {
-	buff := new(bytes.Buffer)
-	err := binary.Write(buff, binary.BigEndian, num)
-	if err != nil {
-		log.Panic(err)
-	}
-
-	return buff.Bytes()
+	buff := make([]byte, 8)
+	binary.BigEndian.PutUint64(buff, uint64(num))
+	return buff
 }
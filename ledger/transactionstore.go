@@ -2,16 +2,36 @@ package ledger
 
 import (
 	"github.com/msaldanha/realChain/keyvaluestore"
+	"sync"
 )
 
+// Stats holds the counters of the transactions in a store.
+type Stats struct {
+	Blocks       int
+	Accounts     int
+	PendingSends int
+}
+
 type TransactionStore struct {
 	store            keyvaluestore.Storer
+	index            keyvaluestore.Storer
 	validatorCreator ValidatorCreator
+	// mtx serializes the writes to the index, which the reads also make when they index a chain.
+	mtx sync.Mutex
+	// stats is counted from the whole store on the first read and then kept up to date by the writes.
+	stats    *Stats
+	statsMtx sync.Mutex
 }
 
 func NewTransactionStore(store keyvaluestore.Storer, validatorCreator ValidatorCreator) (*TransactionStore) {
-	a := &TransactionStore{store: store, validatorCreator: validatorCreator}
-	return a
+	return NewTransactionStoreWithIndex(store, keyvaluestore.NewMemoryKeyValueStore(), validatorCreator)
+}
+
+// NewTransactionStoreWithIndex creates a transaction store keeping the height index of the address chains in
+// index. The chains stored before the index existed are indexed the first time they are read.
+func NewTransactionStoreWithIndex(store keyvaluestore.Storer, index keyvaluestore.Storer,
+	validatorCreator ValidatorCreator) *TransactionStore {
+	return &TransactionStore{store: store, index: index, validatorCreator: validatorCreator}
 }
 
 func (ts *TransactionStore) isValid(tx *Transaction) (bool, error) {
@@ -24,7 +44,12 @@ func (ts *TransactionStore) Store(tx *Transaction) (*Transaction, error) {
 		return nil, err
 	}
 
-	err := ts.store.Put(string(tx.Hash), tx.ToBytes())
+	_, stored, err := ts.store.Get(tx.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ts.store.Put(string(tx.Hash), tx.ToBytes())
 	if err != nil {
 		return nil, err
 	}
@@ -34,9 +59,59 @@ func (ts *TransactionStore) Store(tx *Transaction) (*Transaction, error) {
 		return nil, err
 	}
 
+	// The validators do not check the previous transaction is stored, and a chain without it can not be indexed.
+	_, err = ts.indexChain(tx)
+	if err != nil && err != ErrPreviousTransactionNotFound {
+		return nil, err
+	}
+
+	if !stored {
+		err = ts.updateStats(tx, 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return tx, nil
 }
 
+// Remove removes the head transaction of a chain, making its previous transaction the head again.
+func (ts *TransactionStore) Remove(tx *Transaction) error {
+	head, _, err := ts.GetTransaction(tx.Address)
+	if err != nil {
+		return err
+	}
+	if head == nil || head.Hash != tx.Hash {
+		return ErrTransactionIsNotHead
+	}
+
+	if tx.Previous == "" {
+		err = ts.store.Delete(tx.Address)
+	} else {
+		previous, _, err := ts.GetTransaction(tx.Previous)
+		if err != nil {
+			return err
+		}
+		if previous == nil {
+			return ErrPreviousTransactionNotFound
+		}
+		err = ts.store.Put(tx.Address, previous.ToBytes())
+	}
+	if err != nil {
+		return err
+	}
+
+	err = ts.unindex(tx)
+	if err != nil {
+		return err
+	}
+	err = ts.updateStats(tx, -1)
+	if err != nil {
+		return err
+	}
+	return ts.store.Delete(tx.Hash)
+}
+
 func (ts *TransactionStore) Retrieve(hash string) (*Transaction, error) {
 	value, _, err := ts.GetTransaction(hash)
 	if err != nil {
@@ -46,22 +121,93 @@ func (ts *TransactionStore) Retrieve(hash string) (*Transaction, error) {
 }
 
 
+// GetTransactionChain returns the chain of the transaction, or of the head of the address, txHash, from its open
+// transaction up to it. With includeAll the chain goes on through the send transactions received by the open
+// transactions. The chains are read through the height index.
 func (ts *TransactionStore) GetTransactionChain(txHash string, includeAll bool) ([]*Transaction, error) {
-	tx, ok, _ := ts.GetTransaction(txHash)
 	chain := make([]*Transaction, 0)
-	for ok {
-		chain = append(chain[:0], append([]*Transaction{tx}, chain[0:]...)...)
-		if len(tx.Previous) > 0 {
-			tx, ok, _ = ts.GetTransaction(string(tx.Previous))
-		} else if tx.Type == Transaction_OPEN && len(tx.Link) > 0 && includeAll {
-			tx, ok, _ = ts.GetTransaction(string(tx.Link))
-		} else {
+	for txHash != "" {
+		tx, ok, err := ts.GetTransaction(txHash)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
 			break
 		}
+		height, err := ts.indexChain(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		part := make([]*Transaction, height)
+		part[height-1] = tx
+		for i := int64(1); i < height; i++ {
+			part[i-1], ok, err = ts.GetTransactionAt(tx.Address, i)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, ErrPreviousTransactionNotFound
+			}
+		}
+		chain = append(part, chain...)
+
+		txHash = ""
+		if includeAll && part[0].Type == Transaction_OPEN {
+			txHash = part[0].Link
+		}
 	}
 	return chain, nil
 }
 
+// GetStatement returns the transactions of the address chain selected by query and the hash of the transaction
+// the next page starts at, which is empty if there are no more transactions to scan.
+func (ts *TransactionStore) GetStatement(address string, query StatementQuery) ([]*Transaction, string, error) {
+	txs := make([]*Transaction, 0)
+	head, err := ts.GetHeadHeight(address)
+	if err != nil || head == 0 {
+		return txs, "", err
+	}
+
+	start, step := int64(1), int64(1)
+	if query.Direction == Direction_BACKWARD {
+		start, step = head, -1
+	}
+	if query.StartHash != "" {
+		height, ok, err := ts.GetHeight(query.StartHash)
+		if err != nil {
+			return nil, "", err
+		}
+		hash, _, err := ts.index.Get(chainKey(address, height))
+		if err != nil {
+			return nil, "", err
+		}
+		if !ok || string(hash) != query.StartHash {
+			return nil, "", ErrStatementStartNotInChain
+		}
+		start = height
+	} else if query.StartHeight > 0 {
+		start = query.StartHeight
+	}
+
+	for height := start; height >= 1 && height <= head; height += step {
+		tx, ok, err := ts.GetTransactionAt(address, height)
+		if err != nil {
+			return nil, "", err
+		}
+		if !ok {
+			return nil, "", ErrPreviousTransactionNotFound
+		}
+		if query.Limit > 0 && len(txs) == query.Limit {
+			return txs, tx.Hash, nil
+		}
+		if query.Matches(tx) {
+			txs = append(txs, tx)
+		}
+	}
+	return txs, "", nil
+}
+
 func (ts *TransactionStore) GetTransaction(txHash string) (*Transaction, bool, error) {
 	tx, ok, err := ts.store.Get(txHash)
 	if tx == nil {
@@ -70,6 +216,120 @@ func (ts *TransactionStore) GetTransaction(txHash string) (*Transaction, bool, e
 	return NewTransactionFromBytes(tx), ok, err
 }
 
+// GetFrontiers returns the hash of the head transaction of every address in the store.
+func (ts *TransactionStore) GetFrontiers() (map[string]string, error) {
+	frontiers := make(map[string]string)
+	err := ts.store.ForEach(func(key string, value []byte) error {
+		tx := NewTransactionFromBytes(value)
+		if len(tx.Address) > 0 && tx.Address == key {
+			frontiers[key] = tx.Hash
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return frontiers, nil
+}
+
+// GetStats returns the counters of the transactions, the address chains and the send transactions that were not
+// received yet. Only the first call reads the whole store.
+func (ts *TransactionStore) GetStats() (*Stats, error) {
+	ts.statsMtx.Lock()
+	defer ts.statsMtx.Unlock()
+	if ts.stats == nil {
+		stats, err := ts.countStats()
+		if err != nil {
+			return nil, err
+		}
+		ts.stats = stats
+	}
+	stats := *ts.stats
+	return &stats, nil
+}
+
+// updateStats adds the stored transaction tx, or removes it when n is -1, to the counters if they were counted.
+func (ts *TransactionStore) updateStats(tx *Transaction, n int) error {
+	ts.statsMtx.Lock()
+	defer ts.statsMtx.Unlock()
+	if ts.stats == nil {
+		return nil
+	}
+
+	pending := 0
+	switch tx.Type {
+	case Transaction_SEND:
+		_, received, err := ts.index.Get(receiveKey(tx.Hash))
+		if err != nil {
+			return err
+		}
+		if !received {
+			pending = n
+		}
+	case Transaction_OPEN, Transaction_RECEIVE:
+		if tx.Link == "" {
+			break
+		}
+		send, ok, err := ts.GetTransaction(tx.Link)
+		if err != nil {
+			return err
+		}
+		if ok && send.Type == Transaction_SEND {
+			pending = -n
+		}
+	}
+
+	ts.stats.Blocks += n
+	if tx.Type == Transaction_OPEN {
+		ts.stats.Accounts += n
+	}
+	ts.stats.PendingSends += pending
+	return nil
+}
+
+// countStats counts the transactions, the address chains and the send transactions that were not received yet
+// from the whole store.
+func (ts *TransactionStore) countStats() (*Stats, error) {
+	stats := &Stats{}
+	sends := make(map[string]bool)
+	received := make(map[string]bool)
+	err := ts.store.ForEach(func(key string, value []byte) error {
+		tx := NewTransactionFromBytes(value)
+		switch key {
+		case tx.Address:
+			stats.Accounts++
+		case tx.Hash:
+			stats.Blocks++
+			if tx.Type == Transaction_SEND {
+				sends[tx.Hash] = true
+			} else if tx.Type == Transaction_OPEN || tx.Type == Transaction_RECEIVE {
+				received[tx.Link] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for hash := range sends {
+		if !received[hash] {
+			stats.PendingSends++
+		}
+	}
+	return stats, nil
+}
+
 func (ts *TransactionStore) IsEmpty() (bool) {
 	return ts.store.IsEmpty()
 }
+
+// Check reads the store and its index, returning the error if they can not be read.
+func (ts *TransactionStore) Check() error {
+	_, _, err := ts.store.Get("")
+	if err != nil {
+		return err
+	}
+	_, _, err = ts.index.Get("")
+	return err
+}
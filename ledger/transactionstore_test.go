@@ -3,6 +3,7 @@ package ledger_test
 import (
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/proto"
+	"github.com/msaldanha/realChain/keyvaluestore"
 	"github.com/msaldanha/realChain/ledger"
 	"github.com/msaldanha/realChain/tests"
 	. "github.com/onsi/ginkgo"
@@ -76,32 +77,94 @@ var _ = Describe("TransactionStore", func() {
 		val := ledger.NewValidatorCreator()
 		bs := ledger.NewTransactionStore(ms, val)
 
-		open := &ledger.Transaction{Type: ledger.Transaction_OPEN, Link: "dddddddddddddd", Previous: "eeeeeeeeee",
-			Signature: "a246ce6b1d2b57ac33073127d8f9539fca32fb48481d46d734bf3308796ee18b", Balance: 1,
-			PowNonce: 1, Address: "aaaaaaaaaa", Timestamp: 1, PubKey: "bbbbbbbb"}
-		open.SetHash()
+		open, tx := storeChain(bs, ms, "")
 
-		tx, err := bs.Store(open)
+		chain, err := bs.GetTransactionChain(string(tx.Hash), false)
+		Expect(err).To(BeNil())
+		Expect(len(chain)).To(Equal(2))
+		Expect(chain[0].Hash).To(Equal(open.Hash))
+		Expect(chain[1].Hash).To(Equal(tx.Hash))
 
-		tx = &ledger.Transaction{Type: ledger.Transaction_SEND, Link: "dddddddddddddd", Previous: open.Hash,
-			Signature: "df0d25f706c31d2007ed91da185ac727e5e38bc77f4309bb587e1ff7557ace39", Balance: 1,
-			PowNonce: 1, Address: "aaaaaaaaaa", Timestamp: 1, PubKey: "bbbbbbbb",
-		}
-		tx.SetHash()
+		chain, err = bs.GetTransactionChain(string(tx.Hash), true)
+		Expect(err).To(BeNil())
+		Expect(len(chain)).To(Equal(3))
+		Expect(chain[0].Hash).To(Equal(""))
+		Expect(chain[1].Hash).To(Equal(open.Hash))
+		Expect(chain[2].Hash).To(Equal(tx.Hash))
 
-		b :=  &ledger.Transaction{}
-		ms.Put("dddddddddddddd", b.ToBytes())
+		chain, err = bs.GetTransactionChain(string(open.Hash), false)
+		Expect(err).To(BeNil())
+		Expect(len(chain)).To(Equal(1))
+		Expect(chain[0].Hash).To(Equal(open.Hash))
+	})
 
-		tx, err = bs.Store(tx)
+	It("Should NOT extract the chain for the transaction if its previous transaction is missing", func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		defer mockCtrl.Finish()
 
-		Expect(err).To(BeNil())
-		Expect(tx).NotTo(BeNil())
+		ms := tests.CreateNonEmptyMemoryStore()
+		val := ledger.NewValidatorCreator()
+		bs := ledger.NewTransactionStore(ms, val)
+
+		_, tx := storeChain(bs, ms, "eeeeeeeeee")
 
 		chain, err := bs.GetTransactionChain(string(tx.Hash), true)
-		Expect(err).To(BeNil())
-		Expect(len(chain)).To(Equal(2))
+		Expect(err).To(Equal(ledger.ErrPreviousTransactionNotFound))
+		Expect(chain).To(BeNil())
+	})
+
+	It("Should return the errors of the store when extracting the chain for the transaction", func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		defer mockCtrl.Finish()
 
-		Expect(chain[0].Hash).To(Equal("e54ac468ad78c4b29262cd70331e7f1a53a67f28919419e2ee4134ea895a85bc"))
-		Expect(chain[1].Hash).To(Equal("a04714dbf2c4ec17b38e6d1a2e15c70fcb90855e896b4e12c087c3e0af17c136"))
+		ms := &unreadableStore{MemoryKeyValueStore: tests.CreateNonEmptyMemoryStore()}
+		val := ledger.NewValidatorCreator()
+		bs := ledger.NewTransactionStore(ms, val)
+
+		open, tx := storeChain(bs, ms, "")
+		ms.failKey = open.Hash
+
+		chain, err := bs.GetTransactionChain(string(tx.Hash), false)
+		Expect(err).To(Equal(errStoreFailed))
+		Expect(chain).To(BeNil())
 	})
 })
+
+// storeChain stores an open transaction with previous and a send transaction after it.
+func storeChain(bs *ledger.TransactionStore, ms keyvaluestore.Storer, previous string) (*ledger.Transaction,
+	*ledger.Transaction) {
+	open := &ledger.Transaction{Type: ledger.Transaction_OPEN, Link: "dddddddddddddd", Previous: previous,
+		Signature: "a246ce6b1d2b57ac33073127d8f9539fca32fb48481d46d734bf3308796ee18b", Balance: 1,
+		PowNonce: 1, Address: "aaaaaaaaaa", Timestamp: 1, PubKey: "bbbbbbbb"}
+	open.SetHash()
+
+	_, err := bs.Store(open)
+	Expect(err).To(BeNil())
+
+	tx := &ledger.Transaction{Type: ledger.Transaction_SEND, Link: "dddddddddddddd", Previous: open.Hash,
+		Signature: "df0d25f706c31d2007ed91da185ac727e5e38bc77f4309bb587e1ff7557ace39", Balance: 1,
+		PowNonce: 1, Address: "aaaaaaaaaa", Timestamp: 1, PubKey: "bbbbbbbb",
+	}
+	tx.SetHash()
+
+	b := &ledger.Transaction{}
+	ms.Put("dddddddddddddd", b.ToBytes())
+
+	tx, err = bs.Store(tx)
+	Expect(err).To(BeNil())
+	Expect(tx).NotTo(BeNil())
+	return open, tx
+}
+
+// unreadableStore fails to read the value of failKey.
+type unreadableStore struct {
+	*keyvaluestore.MemoryKeyValueStore
+	failKey string
+}
+
+func (s *unreadableStore) Get(key string) ([]byte, bool, error) {
+	if key == s.failKey {
+		return nil, false, errStoreFailed
+	}
+	return s.MemoryKeyValueStore.Get(key)
+}
@@ -0,0 +1,118 @@
+package ledgersync
+
+import (
+	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/logging"
+	"github.com/msaldanha/realChain/peerdiscovery"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// AntiEntropyStats holds the counters of the anti-entropy rounds run so far.
+type AntiEntropyStats struct {
+	Rounds        int
+	FailedRounds  int
+	LastRound     time.Time
+	LastDiverged  int
+	TotalDiverged int
+	TotalBehind   int
+	TotalFailed   int
+	TotalStored   int
+}
+
+// AntiEntropy periodically reconciles the local ledger with a random peer, pulling the transactions that
+// were missed, for instance because a gossip message was lost.
+type AntiEntropy struct {
+	puller   *Puller
+	dis      peerdiscovery.Discoverer
+	interval time.Duration
+	mtx      sync.Mutex
+	stats    AntiEntropyStats
+	logger   *log.Entry
+}
+
+func NewAntiEntropy(ld ledger.Ledger, dis peerdiscovery.Discoverer, interval time.Duration) *AntiEntropy {
+	return &AntiEntropy{puller: NewPuller(ld), dis: dis, interval: interval,
+		logger: logging.Component(log.StandardLogger(), "sync")}
+}
+
+// SetLogger sets the logger of the anti-entropy.
+func (a *AntiEntropy) SetLogger(logger log.FieldLogger) {
+	a.logger = logging.Component(logger, "sync")
+	a.puller.SetLogger(logger)
+}
+
+// Run runs a reconciliation round every interval until ctx is done.
+func (a *AntiEntropy) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := a.Reconcile(ctx)
+			if err != nil {
+				a.logger.Warnf("Anti-entropy round failed: %s", err)
+			}
+		}
+	}
+}
+
+// Reconcile runs one reconciliation round against a random peer.
+func (a *AntiEntropy) Reconcile(ctx context.Context) error {
+	peers, err := a.dis.Peers()
+	if err != nil {
+		a.roundFailed()
+		return err
+	}
+	if len(peers) == 0 {
+		return nil
+	}
+
+	peer := peers[rand.Intn(len(peers))]
+	result, err := a.puller.Pull(ctx, peer)
+	if err != nil {
+		a.roundFailed()
+		return err
+	}
+
+	a.mtx.Lock()
+	a.stats.Rounds++
+	a.stats.LastRound = time.Now()
+	a.stats.LastDiverged = result.Diverged
+	a.stats.TotalDiverged += result.Diverged
+	a.stats.TotalBehind += result.Behind
+	a.stats.TotalFailed += result.Failed
+	a.stats.TotalStored += result.Stored
+	a.mtx.Unlock()
+
+	if result.Diverged > 0 {
+		a.logger.WithFields(log.Fields{
+			"diverged": result.Diverged,
+			"behind":   result.Behind,
+			"failed":   result.Failed,
+			"stored":   result.Stored,
+		}).Info("Anti-entropy round reconciled diverged chains")
+	}
+	return nil
+}
+
+// Stats returns a copy of the anti-entropy counters.
+func (a *AntiEntropy) Stats() AntiEntropyStats {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	return a.stats
+}
+
+func (a *AntiEntropy) roundFailed() {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.stats.Rounds++
+	a.stats.FailedRounds++
+	a.stats.LastRound = time.Now()
+}
@@ -0,0 +1,125 @@
+package ledgersync_test
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/msaldanha/realChain/address"
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/ledgersync"
+	"github.com/msaldanha/realChain/tests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+	"time"
+)
+
+var _ = Describe("AntiEntropy", func() {
+
+	var mockCtrl *gomock.Controller
+	var dis *tests.MockDiscoverer
+	var peer *tests.MockConsensusClient
+	var source ledger.Ledger
+	var target ledger.Ledger
+	var genesisAddr *address.Address
+	var sendTx *ledger.Transaction
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		dis = tests.NewMockDiscoverer(mockCtrl)
+		peer = tests.NewMockConsensusClient(mockCtrl)
+
+		source = newLedger()
+		target = newLedger()
+
+		var genesisTx *ledger.Transaction
+		genesisTx, genesisAddr = tests.CreateGenesisTransaction(1000)
+		Expect(source.Initialize(genesisTx)).To(BeNil())
+		Expect(target.Initialize(genesisTx)).To(BeNil())
+		sendTx = genesisTx
+	})
+
+	It("Should pull the chains missed by the local ledger", func() {
+		defer mockCtrl.Finish()
+
+		addr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+
+		var receiveTx *ledger.Transaction
+		sendTx, receiveTx = tests.SendFunds(source, genesisAddr, sendTx, receiveTx, addr, 100)
+		Expect(target.Register(sendTx, receiveTx)).To(BeNil())
+		sendTx, receiveTx = tests.SendFunds(source, genesisAddr, sendTx, receiveTx, addr, 100)
+
+		servePeer(peer, source)
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{peer}, nil)
+
+		ae := ledgersync.NewAntiEntropy(target, dis, time.Minute)
+		err = ae.Reconcile(context.Background())
+		Expect(err).To(BeNil())
+
+		assertSameLedger(source, target)
+
+		stats := ae.Stats()
+		Expect(stats.Rounds).To(Equal(1))
+		Expect(stats.LastDiverged).To(Equal(2))
+		Expect(stats.TotalBehind).To(Equal(2))
+		Expect(stats.TotalStored).To(Equal(2))
+		Expect(stats.TotalFailed).To(Equal(0))
+	})
+
+	It("Should count diverged chains the peer is behind on", func() {
+		defer mockCtrl.Finish()
+
+		addr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+		tests.SendFunds(target, genesisAddr, sendTx, nil, addr, 100)
+
+		servePeer(peer, source)
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{peer}, nil)
+
+		ae := ledgersync.NewAntiEntropy(target, dis, time.Minute)
+		err = ae.Reconcile(context.Background())
+		Expect(err).To(BeNil())
+
+		stats := ae.Stats()
+		Expect(stats.LastDiverged).To(Equal(1))
+		Expect(stats.TotalBehind).To(Equal(0))
+		Expect(stats.TotalStored).To(Equal(0))
+	})
+
+	It("Should count failed rounds", func() {
+		defer mockCtrl.Finish()
+
+		someErr := errors.Error("some error")
+		peer.EXPECT().GetFrontiers(gomock.Any(), gomock.Any()).Return(nil, someErr)
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{peer}, nil)
+
+		ae := ledgersync.NewAntiEntropy(target, dis, time.Minute)
+		err := ae.Reconcile(context.Background())
+		Expect(err).To(Equal(someErr))
+
+		stats := ae.Stats()
+		Expect(stats.Rounds).To(Equal(1))
+		Expect(stats.FailedRounds).To(Equal(1))
+	})
+
+	It("Should reconcile periodically until stopped", func() {
+		defer mockCtrl.Finish()
+
+		rounds := make(chan struct{}, 10)
+		dis.EXPECT().Peers().Do(func() { rounds <- struct{}{} }).Return([]consensus.ConsensusClient{}, nil).MinTimes(2)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		ae := ledgersync.NewAntiEntropy(target, dis, 10*time.Millisecond)
+		go func() {
+			ae.Run(ctx)
+			close(done)
+		}()
+
+		Eventually(rounds).Should(Receive())
+		Eventually(rounds).Should(Receive())
+		cancel()
+		Eventually(done).Should(BeClosed())
+	})
+})
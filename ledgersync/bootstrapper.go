@@ -0,0 +1,84 @@
+package ledgersync
+
+import (
+	"github.com/msaldanha/realChain/errors"
+	"github.com/msaldanha/realChain/keyvaluestore"
+	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/logging"
+	"github.com/msaldanha/realChain/peerdiscovery"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+const (
+	ErrNoPeersForBootstrap = errors.Error("no peers for bootstrap")
+	ErrBootstrapFailed     = errors.Error("bootstrap failed for all peers")
+)
+
+const (
+	bootstrapStateKey     = "bootstrap"
+	bootstrapStateRunning = "running"
+	bootstrapStateDone    = "done"
+)
+
+// Bootstrapper syncs the local ledger from its peers. The bootstrap state is kept in its own store so a node
+// that was interrupted while bootstrapping resumes it on the next start.
+type Bootstrapper struct {
+	puller *Puller
+	dis    peerdiscovery.Discoverer
+	state  keyvaluestore.Storer
+	logger *log.Entry
+}
+
+func NewBootstrapper(ld ledger.Ledger, dis peerdiscovery.Discoverer, state keyvaluestore.Storer) *Bootstrapper {
+	return &Bootstrapper{puller: NewPuller(ld), dis: dis, state: state,
+		logger: logging.Component(log.StandardLogger(), "sync")}
+}
+
+// SetLogger sets the logger of the bootstrapper.
+func (b *Bootstrapper) SetLogger(logger log.FieldLogger) {
+	b.logger = logging.Component(logger, "sync")
+	b.puller.SetLogger(logger)
+}
+
+// IsPending tells if a bootstrap was started but did not finish.
+func (b *Bootstrapper) IsPending() (bool, error) {
+	state, _, err := b.state.Get(bootstrapStateKey)
+	if err != nil {
+		return false, err
+	}
+	return string(state) == bootstrapStateRunning, nil
+}
+
+// Run pulls the ledger from every peer. It succeeds if at least one peer could be fully pulled.
+func (b *Bootstrapper) Run(ctx context.Context) error {
+	err := b.state.Put(bootstrapStateKey, []byte(bootstrapStateRunning))
+	if err != nil {
+		return err
+	}
+
+	peers, err := b.dis.Peers()
+	if err != nil {
+		return err
+	}
+	if len(peers) == 0 {
+		return ErrNoPeersForBootstrap
+	}
+
+	ok := 0
+	for i, peer := range peers {
+		result, err := b.puller.Pull(ctx, peer)
+		if err != nil {
+			b.logger.WithField(logging.PeerField, i).Warnf("Bootstrap from peer failed: %s", err)
+			continue
+		}
+		b.logger.WithField(logging.PeerField, i).Infof("Bootstrap from peer stored %d transactions", result.Stored)
+		ok++
+	}
+
+	if ok == 0 {
+		return ErrBootstrapFailed
+	}
+
+	return b.state.Put(bootstrapStateKey, []byte(bootstrapStateDone))
+}
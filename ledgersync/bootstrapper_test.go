@@ -0,0 +1,211 @@
+package ledgersync_test
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/msaldanha/realChain/address"
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/keyvaluestore"
+	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/ledgersync"
+	"github.com/msaldanha/realChain/server"
+	"github.com/msaldanha/realChain/tests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Bootstrapper", func() {
+
+	var mockCtrl *gomock.Controller
+	var dis *tests.MockDiscoverer
+	var peer *tests.MockConsensusClient
+	var source ledger.Ledger
+	var target ledger.Ledger
+	var state *keyvaluestore.MemoryKeyValueStore
+	var txs []*ledger.Transaction
+	var genesisAddr *address.Address
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		dis = tests.NewMockDiscoverer(mockCtrl)
+		peer = tests.NewMockConsensusClient(mockCtrl)
+		state = keyvaluestore.NewMemoryKeyValueStore()
+
+		source = newLedger()
+		target = newLedger()
+
+		var genesisTx *ledger.Transaction
+		genesisTx, genesisAddr = tests.CreateGenesisTransaction(1000)
+		Expect(source.Initialize(genesisTx)).To(BeNil())
+		txs = []*ledger.Transaction{genesisTx}
+
+		addr1, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+		addr2, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+
+		var receiveTx *ledger.Transaction
+		sendTx := genesisTx
+		for x := 1; x <= 3; x++ {
+			sendTx, receiveTx = tests.SendFunds(source, genesisAddr, sendTx, receiveTx, addr1, 100)
+			txs = append(txs, sendTx, receiveTx)
+		}
+		sendTx, receiveTx = tests.SendFunds(source, addr1, receiveTx, nil, addr2, 50)
+		txs = append(txs, sendTx, receiveTx)
+	})
+
+	It("Should bootstrap an empty ledger from its peers", func() {
+		defer mockCtrl.Finish()
+
+		servePeer(peer, source)
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{peer}, nil)
+
+		b := ledgersync.NewBootstrapper(target, dis, state)
+		err := b.Run(context.Background())
+		Expect(err).To(BeNil())
+
+		assertSameLedger(source, target)
+
+		pending, err := b.IsPending()
+		Expect(err).To(BeNil())
+		Expect(pending).To(BeFalse())
+	})
+
+	It("Should resume an interrupted bootstrap from the local heads", func() {
+		defer mockCtrl.Finish()
+
+		Expect(target.Initialize(txs[0])).To(BeNil())
+		Expect(target.Register(txs[1], txs[2])).To(BeNil())
+		state.Put("bootstrap", []byte("running"))
+
+		srv := server.New(source, nil, nil, nil, nil)
+		afters := make(map[string]string)
+		peer.EXPECT().GetFrontiers(gomock.Any(), gomock.Any()).DoAndReturn(srv.GetFrontiers)
+		peer.EXPECT().GetChain(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, request *consensus.GetChainRequest) (*consensus.GetChainResult, error) {
+				afters[request.Address] = request.After
+				return srv.GetChain(ctx, request)
+			}).AnyTimes()
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{peer}, nil)
+
+		b := ledgersync.NewBootstrapper(target, dis, state)
+		pending, err := b.IsPending()
+		Expect(err).To(BeNil())
+		Expect(pending).To(BeTrue())
+
+		err = b.Run(context.Background())
+		Expect(err).To(BeNil())
+
+		Expect(afters[genesisAddr.Address]).To(Equal(txs[1].Hash))
+		assertSameLedger(source, target)
+	})
+
+	It("Should not store transactions rejected by the ledger", func() {
+		defer mockCtrl.Finish()
+
+		tampered := newLedger()
+		Expect(tampered.Initialize(txs[0])).To(BeNil())
+		Expect(tampered.Register(txs[1], txs[2])).To(BeNil())
+
+		srv := server.New(source, nil, nil, nil, nil)
+		peer.EXPECT().GetFrontiers(gomock.Any(), gomock.Any()).DoAndReturn(srv.GetFrontiers)
+		peer.EXPECT().GetChain(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, request *consensus.GetChainRequest) (*consensus.GetChainResult, error) {
+				result, err := srv.GetChain(ctx, request)
+				for _, tx := range result.Txs {
+					if tx.Hash == txs[3].Hash {
+						tx.Balance = 0
+					}
+				}
+				return result, err
+			}).AnyTimes()
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{peer}, nil)
+
+		b := ledgersync.NewBootstrapper(target, dis, state)
+		err := b.Run(context.Background())
+		Expect(err).To(BeNil())
+
+		assertSameLedger(tampered, target)
+	})
+
+	It("Should pull the chains a page at a time", func() {
+		defer mockCtrl.Finish()
+
+		srv := server.New(source, nil, nil, nil, nil)
+		pages := 0
+		peer.EXPECT().GetFrontiers(gomock.Any(), gomock.Any()).DoAndReturn(srv.GetFrontiers)
+		peer.EXPECT().GetChain(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, request *consensus.GetChainRequest) (*consensus.GetChainResult, error) {
+				pages++
+				request.Limit = 2
+				return srv.GetChain(ctx, request)
+			}).AnyTimes()
+
+		puller := ledgersync.NewPuller(target)
+		result, err := puller.Pull(context.Background(), peer)
+		Expect(err).To(BeNil())
+		Expect(result.Failed).To(BeZero())
+		Expect(result.Stored).To(Equal(len(txs)))
+		Expect(pages).To(BeNumerically(">", 3))
+
+		assertSameLedger(source, target)
+	})
+
+	It("Should NOT bootstrap from a peer with a different genesis transaction", func() {
+		defer mockCtrl.Finish()
+
+		otherGenesisTx, _ := tests.CreateGenesisTransaction(1000)
+		Expect(target.Initialize(otherGenesisTx)).To(BeNil())
+
+		servePeer(peer, source)
+
+		puller := ledgersync.NewPuller(target)
+		_, err := puller.Pull(context.Background(), peer)
+		Expect(err).To(Equal(ledgersync.ErrGenesisMismatch))
+	})
+
+	It("Should fail if there are no peers", func() {
+		defer mockCtrl.Finish()
+
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{}, nil)
+
+		b := ledgersync.NewBootstrapper(target, dis, state)
+		err := b.Run(context.Background())
+		Expect(err).To(Equal(ledgersync.ErrNoPeersForBootstrap))
+
+		pending, err := b.IsPending()
+		Expect(err).To(BeNil())
+		Expect(pending).To(BeTrue())
+	})
+})
+
+func newLedger() ledger.Ledger {
+	ts := ledger.NewTransactionStore(keyvaluestore.NewMemoryKeyValueStore(), ledger.NewValidatorCreator())
+	return ledger.NewLocalLedger(ts)
+}
+
+// servePeer makes peer answer frontier and chain requests from the source ledger.
+func servePeer(peer *tests.MockConsensusClient, source ledger.Ledger) {
+	srv := server.New(source, nil, nil, nil, nil)
+	peer.EXPECT().GetFrontiers(gomock.Any(), gomock.Any()).DoAndReturn(srv.GetFrontiers).AnyTimes()
+	peer.EXPECT().GetChain(gomock.Any(), gomock.Any()).DoAndReturn(srv.GetChain).AnyTimes()
+}
+
+func assertSameLedger(expected, actual ledger.Ledger) {
+	expectedFrontiers, err := expected.GetFrontiers()
+	Expect(err).To(BeNil())
+	actualFrontiers, err := actual.GetFrontiers()
+	Expect(err).To(BeNil())
+	Expect(actualFrontiers).To(Equal(expectedFrontiers))
+
+	for addr := range expectedFrontiers {
+		expectedChain, err := expected.GetAddressStatement(addr)
+		Expect(err).To(BeNil())
+		actualChain, err := actual.GetAddressStatement(addr)
+		Expect(err).To(BeNil())
+		Expect(len(actualChain)).To(Equal(len(expectedChain)))
+		for i := range expectedChain {
+			Expect(actualChain[i].Hash).To(Equal(expectedChain[i].Hash))
+		}
+	}
+}
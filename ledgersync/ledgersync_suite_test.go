@@ -0,0 +1,13 @@
+package ledgersync_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestLedgersync(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Ledgersync Suite")
+}
@@ -0,0 +1,209 @@
+package ledgersync
+
+import (
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/logging"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+const (
+	ErrGenesisMismatch = errors.Error("peer genesis transaction differs from local genesis")
+)
+
+// Puller compares the frontiers of a peer with the local ones and pulls the transactions that are missing
+// from the local ledger. Every pulled transaction is verified by the ledger before being stored.
+type Puller struct {
+	ld     ledger.Ledger
+	logger *log.Entry
+}
+
+// PullResult summarizes a pull from a peer.
+type PullResult struct {
+	// Diverged is the number of address chains whose head differs from the peer one.
+	Diverged int
+	// Behind is the number of diverged chains that miss transactions known by the peer.
+	Behind int
+	// Failed is the number of chains that could not be pulled or were rejected by the ledger.
+	Failed int
+	// Stored is the number of transactions stored.
+	Stored int
+}
+
+type pulledChain struct {
+	txs  []*ledger.Transaction
+	next int
+}
+
+func NewPuller(ld ledger.Ledger) *Puller {
+	return &Puller{ld: ld, logger: logging.Component(log.StandardLogger(), "sync")}
+}
+
+// SetLogger sets the logger of the puller.
+func (p *Puller) SetLogger(logger log.FieldLogger) {
+	p.logger = logging.Component(logger, "sync")
+}
+
+// Pull fetches from peer every address chain that is behind locally and registers the missing transactions.
+// Chains are pulled from the current local head, so an interrupted pull is resumed by calling Pull again.
+func (p *Puller) Pull(ctx context.Context, peer consensus.ConsensusClient) (*PullResult, error) {
+	local, err := p.ld.GetFrontiers()
+	if err != nil {
+		return nil, err
+	}
+
+	frontiers, err := peer.GetFrontiers(ctx, &consensus.GetFrontiersRequest{Frontiers: local})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PullResult{}
+	chains := make(map[string]*pulledChain)
+	for addr, hash := range frontiers.Frontiers {
+		if local[addr] == hash {
+			continue
+		}
+		result.Diverged++
+
+		tx, err := p.ld.GetTransaction(hash)
+		if err != nil {
+			return result, err
+		}
+		if tx != nil {
+			continue
+		}
+		result.Behind++
+
+		txs, err := p.pullChain(ctx, peer, addr, local[addr])
+		if err != nil {
+			p.logger.WithField(logging.AddressField, addr).Warnf("Failed to pull chain: %s", err)
+			result.Failed++
+			continue
+		}
+		if len(txs) > 0 {
+			chains[addr] = &pulledChain{txs: txs}
+		}
+	}
+
+	err = p.apply(chains, result)
+	return result, err
+}
+
+// pullChain fetches the chain of addr after the transaction after, or whole if it is empty, a page at a time.
+func (p *Puller) pullChain(ctx context.Context, peer consensus.ConsensusClient, addr,
+	after string) ([]*ledger.Transaction, error) {
+	txs := make([]*ledger.Transaction, 0)
+	for {
+		page, err := peer.GetChain(ctx, &consensus.GetChainRequest{Address: addr, After: after})
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, page.Txs...)
+		if page.NextHash == "" || page.NextHash == after {
+			return txs, nil
+		}
+		after = page.NextHash
+	}
+}
+
+// apply registers the pulled transactions. Send and receive transactions are registered in pairs, so a pair
+// is only registered when both transactions are the next ones in their chains. It stops when no more pairs
+// can be registered.
+func (p *Puller) apply(chains map[string]*pulledChain, result *PullResult) error {
+	stored, err := p.applyGenesis(chains)
+	result.Stored += stored
+	if err != nil {
+		return err
+	}
+
+	byHash := make(map[string]*ledger.Transaction)
+	for _, chain := range chains {
+		for _, tx := range chain.txs {
+			byHash[tx.Hash] = tx
+		}
+	}
+
+	for progress := true; progress; {
+		progress = false
+		for addr, chain := range chains {
+			if chain.next >= len(chain.txs) {
+				continue
+			}
+
+			sendTx, receiveTx := p.findPair(chain.txs[chain.next], chains, byHash)
+			if sendTx == nil || !isNext(chains, sendTx) || !isNext(chains, receiveTx) {
+				continue
+			}
+
+			err := p.ld.Register(sendTx, receiveTx)
+			if err != nil {
+				p.logger.WithFields(log.Fields{logging.SendTxField: sendTx.Hash, logging.TxField: receiveTx.Hash,
+					logging.AddressField: receiveTx.Address}).Warnf("Failed to register pulled transactions: %s", err)
+				delete(chains, addr)
+				result.Failed++
+				continue
+			}
+
+			chains[sendTx.Address].next++
+			chains[receiveTx.Address].next++
+			result.Stored += 2
+			progress = true
+		}
+	}
+
+	return nil
+}
+
+func (p *Puller) applyGenesis(chains map[string]*pulledChain) (int, error) {
+	for _, chain := range chains {
+		genesisTx := chain.txs[0]
+		if genesisTx.Type != ledger.Transaction_OPEN || genesisTx.Link != "" {
+			continue
+		}
+
+		err := p.ld.VerifyTransaction(genesisTx, true)
+		if err != nil {
+			return 0, err
+		}
+
+		err = p.ld.Initialize(genesisTx)
+		if err == ledger.ErrLedgerAlreadyInitialized {
+			return 0, ErrGenesisMismatch
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		chain.next++
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (p *Puller) findPair(tx *ledger.Transaction, chains map[string]*pulledChain,
+	byHash map[string]*ledger.Transaction) (*ledger.Transaction, *ledger.Transaction) {
+	switch tx.Type {
+	case ledger.Transaction_SEND:
+		chain, ok := chains[tx.Link]
+		if !ok {
+			return nil, nil
+		}
+		for _, receiveTx := range chain.txs[chain.next:] {
+			if receiveTx.Link == tx.Hash {
+				return tx, receiveTx
+			}
+		}
+	case ledger.Transaction_OPEN, ledger.Transaction_RECEIVE:
+		if sendTx, ok := byHash[tx.Link]; ok {
+			return sendTx, tx
+		}
+	}
+	return nil, nil
+}
+
+func isNext(chains map[string]*pulledChain, tx *ledger.Transaction) bool {
+	chain, ok := chains[tx.Address]
+	return ok && chain.next < len(chain.txs) && chain.txs[chain.next] == tx
+}
@@ -0,0 +1,200 @@
+// Package logging creates the node logger from the configuration and carries request IDs through the gRPC calls,
+// so the log lines of a call, and of the peer calls it makes, can be told apart from the others.
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	ErrInvalidLogFormat = errors.Error("invalid log format")
+)
+
+const (
+	// RequestIdKey is the gRPC metadata key of the request ID.
+	RequestIdKey = "x-request-id"
+
+	// Fields of the log lines.
+	ComponentField = "component"
+	RequestIdField = "request_id"
+	TxField        = "tx"
+	SendTxField    = "send_tx"
+	AddressField   = "address"
+	PeerField      = "peer"
+	MethodField    = "method"
+
+	maxRequestIdLen = 64
+)
+
+type requestIdKey struct{}
+
+// New creates a logger writing to stderr with the configured level (info by default) and format (text by
+// default).
+func New(cfg *viper.Viper) (*log.Logger, error) {
+	level := log.InfoLevel
+	if name := cfg.GetString(config.CfgLogLevel); name != "" {
+		var err error
+		level, err = log.ParseLevel(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var formatter log.Formatter
+	switch cfg.GetString(config.CfgLogFormat) {
+	case "", config.LogFormatText:
+		formatter = &log.TextFormatter{FullTimestamp: true}
+	case config.LogFormatJson:
+		formatter = &log.JSONFormatter{}
+	default:
+		return nil, ErrInvalidLogFormat
+	}
+
+	logger := log.New()
+	logger.SetOutput(os.Stderr)
+	logger.SetLevel(level)
+	logger.SetFormatter(formatter)
+	return logger, nil
+}
+
+// Component returns the logger of a node component, whose lines are tagged with the component name.
+func Component(logger log.FieldLogger, name string) *log.Entry {
+	return logger.WithField(ComponentField, name)
+}
+
+// NewRequestId creates a random request ID.
+func NewRequestId() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// WithRequestId returns a copy of ctx carrying the request ID.
+func WithRequestId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, id)
+}
+
+// RequestId returns the request ID carried by ctx, or an empty string if there is none.
+func RequestId(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIdKey{}).(string)
+	return id
+}
+
+// FromContext returns the logger with the request ID carried by ctx, if any.
+func FromContext(ctx context.Context, logger log.FieldLogger) *log.Entry {
+	id := RequestId(ctx)
+	if id == "" {
+		return logger.WithFields(log.Fields{})
+	}
+	return logger.WithField(RequestIdField, id)
+}
+
+// AppendToOutgoingContext adds the request ID carried by ctx to the metadata of the calls made with the returned
+// context, so the called node logs them with the same ID.
+func AppendToOutgoingContext(ctx context.Context) context.Context {
+	id := RequestId(ctx)
+	if id == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, RequestIdKey, id)
+}
+
+// RequestIdHandler gives every HTTP request a request ID, the one in the RequestIdKey header or a new one, sent
+// back in the response header.
+func RequestIdHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIdKey)
+		if !validRequestId(id) {
+			id = NewRequestId()
+		}
+		w.Header().Set(RequestIdKey, id)
+		next.ServeHTTP(w, r.WithContext(WithRequestId(r.Context(), id)))
+	})
+}
+
+// UnaryServerInterceptor gives every call a request ID, the one sent by the client or a new one, and logs the
+// calls at debug level.
+func UnaryServerInterceptor(logger log.FieldLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = incomingRequestId(ctx)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, logger, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor gives every stream a request ID, the one sent by the client or a new one, and logs the
+// streams at debug level when they end.
+func StreamServerInterceptor(logger log.FieldLogger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := incomingRequestId(ss.Context())
+		start := time.Now()
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, logger, info.FullMethod, start, err)
+		return err
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// incomingRequestId returns a copy of ctx carrying the request ID sent by the client, or a new one if the client
+// sent none or an invalid one. The request ID is sent back in the response header.
+func incomingRequestId(ctx context.Context) context.Context {
+	id := ""
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(RequestIdKey); len(values) > 0 && validRequestId(values[0]) {
+		id = values[0]
+	}
+	if id == "" {
+		id = NewRequestId()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(RequestIdKey, id))
+	return WithRequestId(ctx, id)
+}
+
+// validRequestId tells if a request ID sent by a client is short and printable, so it can be logged as is.
+func validRequestId(id string) bool {
+	if id == "" || len(id) > maxRequestIdLen {
+		return false
+	}
+	for _, c := range id {
+		valid := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' ||
+			c == '.'
+		if !valid {
+			return false
+		}
+	}
+	return true
+}
+
+func logCall(ctx context.Context, logger log.FieldLogger, method string, start time.Time, err error) {
+	entry := FromContext(ctx, logger).WithFields(log.Fields{MethodField: method, "duration": time.Since(start)})
+	if err != nil {
+		entry.Debugf("Call failed: %s", err)
+		return
+	}
+	entry.Debug("Call served")
+}
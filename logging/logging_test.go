@@ -0,0 +1,121 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/logging"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"net/http"
+	"net/http/httptest"
+)
+
+var _ = Describe("Logging", func() {
+
+	var cfg *viper.Viper
+
+	BeforeEach(func() {
+		cfg = viper.New()
+	})
+
+	It("Should create a text logger at info level by default", func() {
+		logger, err := logging.New(cfg)
+		Expect(err).To(BeNil())
+		Expect(logger.Level).To(Equal(log.InfoLevel))
+		Expect(logger.Formatter).To(BeAssignableToTypeOf(&log.TextFormatter{}))
+	})
+
+	It("Should create a logger with the configured level and format", func() {
+		cfg.Set(config.CfgLogLevel, "debug")
+		cfg.Set(config.CfgLogFormat, config.LogFormatJson)
+		logger, err := logging.New(cfg)
+		Expect(err).To(BeNil())
+		Expect(logger.Level).To(Equal(log.DebugLevel))
+
+		buf := &bytes.Buffer{}
+		logger.SetOutput(buf)
+		ctx := logging.WithRequestId(context.Background(), "request-1")
+		logging.FromContext(ctx, logging.Component(logger, "server")).WithField(logging.TxField, "hash").
+			Debug("Voting")
+
+		line := map[string]interface{}{}
+		Expect(json.Unmarshal(buf.Bytes(), &line)).To(BeNil())
+		Expect(line["msg"]).To(Equal("Voting"))
+		Expect(line["level"]).To(Equal("debug"))
+		Expect(line[logging.ComponentField]).To(Equal("server"))
+		Expect(line[logging.RequestIdField]).To(Equal("request-1"))
+		Expect(line[logging.TxField]).To(Equal("hash"))
+	})
+
+	It("Should return error for an invalid level or format", func() {
+		cfg.Set(config.CfgLogLevel, "loud")
+		_, err := logging.New(cfg)
+		Expect(err).NotTo(BeNil())
+
+		cfg.Set(config.CfgLogLevel, "info")
+		cfg.Set(config.CfgLogFormat, "xml")
+		_, err = logging.New(cfg)
+		Expect(err).To(Equal(logging.ErrInvalidLogFormat))
+	})
+
+	It("Should carry the request ID to the outgoing calls", func() {
+		ctx := logging.AppendToOutgoingContext(context.Background())
+		_, ok := metadata.FromOutgoingContext(ctx)
+		Expect(ok).To(BeFalse())
+
+		ctx = logging.AppendToOutgoingContext(logging.WithRequestId(context.Background(), "request-1"))
+		md, _ := metadata.FromOutgoingContext(ctx)
+		Expect(md.Get(logging.RequestIdKey)).To(Equal([]string{"request-1"}))
+	})
+
+	It("Should give the calls the request ID sent by the client or a new one", func() {
+		logger := log.New()
+		logger.SetOutput(&bytes.Buffer{})
+		interceptor := logging.UnaryServerInterceptor(logger)
+		info := &grpc.UnaryServerInfo{FullMethod: "/ledger.Ledger/Register"}
+		requestId := func(ctx context.Context) string {
+			var id string
+			_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				id = logging.RequestId(ctx)
+				return nil, nil
+			})
+			Expect(err).To(BeNil())
+			return id
+		}
+
+		sent := metadata.NewIncomingContext(context.Background(), metadata.Pairs(logging.RequestIdKey, "request-1"))
+		Expect(requestId(sent)).To(Equal("request-1"))
+
+		invalid := metadata.NewIncomingContext(context.Background(), metadata.Pairs(logging.RequestIdKey, "bad\nid"))
+		Expect(requestId(invalid)).NotTo(Equal("bad\nid"))
+		Expect(requestId(invalid)).NotTo(BeEmpty())
+
+		Expect(requestId(context.Background())).NotTo(BeEmpty())
+		Expect(requestId(context.Background())).NotTo(Equal(requestId(context.Background())))
+	})
+
+	It("Should give the HTTP requests the request ID sent by the client or a new one", func() {
+		var id string
+		handler := logging.RequestIdHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id = logging.RequestId(r.Context())
+		}))
+
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set(logging.RequestIdKey, "request-1")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		Expect(id).To(Equal("request-1"))
+		Expect(recorder.Header().Get(logging.RequestIdKey)).To(Equal("request-1"))
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+		Expect(id).NotTo(BeEmpty())
+		Expect(recorder.Header().Get(logging.RequestIdKey)).To(Equal(id))
+	})
+})
@@ -0,0 +1,310 @@
+// Package metrics keeps the node metrics and serves them in the Prometheus text exposition format.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the upper bounds, in seconds, of the histogram buckets used for latencies.
+var DefaultBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Default is the registry the package level constructors register the metrics in.
+var Default = NewRegistry()
+
+// Registry holds a set of metrics and writes them in the text exposition format.
+type Registry struct {
+	mtx     sync.Mutex
+	metrics []metric
+	names   map[string]bool
+}
+
+type metric interface {
+	name() string
+	write(w io.Writer)
+}
+
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// NewCounter creates a counter in the default registry.
+func NewCounter(name, help string, labels ...string) *Counter {
+	return Default.NewCounter(name, help, labels...)
+}
+
+// NewGauge creates a gauge in the default registry.
+func NewGauge(name, help string, labels ...string) *Gauge {
+	return Default.NewGauge(name, help, labels...)
+}
+
+// NewHistogram creates a histogram in the default registry.
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return Default.NewHistogram(name, help, buckets, labels...)
+}
+
+// Handler serves the metrics of the default registry.
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// NewCounter creates a counter, a value that only goes up, with the given label names. It panics if the
+// registry already has a metric with the same name.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{family: newFamily(name, help, "counter", labels)}
+	r.register(c)
+	return c
+}
+
+// NewGauge creates a gauge, a value that goes up and down, with the given label names. It panics if the
+// registry already has a metric with the same name.
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{family: newFamily(name, help, "gauge", labels)}
+	r.register(g)
+	return g
+}
+
+// NewHistogram creates a histogram that counts the observed values in buckets with the given upper bounds. It
+// panics if the registry already has a metric with the same name.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	h := &Histogram{family: newFamily(name, help, "histogram", labels), buckets: sorted}
+	r.register(h)
+	return h
+}
+
+// Write writes the metrics, sorted by name, in the text exposition format.
+func (r *Registry) Write(w io.Writer) error {
+	r.mtx.Lock()
+	metrics := append([]metric{}, r.metrics...)
+	r.mtx.Unlock()
+
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].name() < metrics[j].name() })
+	buf := &bytes.Buffer{}
+	for _, m := range metrics {
+		m.write(buf)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Handler serves the metrics of the registry.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_ = r.Write(w)
+	})
+}
+
+func (r *Registry) register(m metric) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.names[m.name()] {
+		panic(fmt.Sprintf("metric %s already registered", m.name()))
+	}
+	r.names[m.name()] = true
+	r.metrics = append(r.metrics, m)
+}
+
+// Counter is a counter metric. The label values passed to its methods must match its label names.
+type Counter struct {
+	*family
+}
+
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds v, which must not be negative, to the counter.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	c.update(labelValues, func(s *series) { s.value += v })
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.writeHeader(w)
+	for _, s := range c.snapshot() {
+		writeSample(w, c.metricName, c.labels, s.labelValues, "", "", s.value)
+	}
+}
+
+// Gauge is a gauge metric. The label values passed to its methods must match its label names.
+type Gauge struct {
+	*family
+}
+
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.update(labelValues, func(s *series) { s.value = v })
+}
+
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.update(labelValues, func(s *series) { s.value += v })
+}
+
+func (g *Gauge) write(w io.Writer) {
+	g.writeHeader(w)
+	for _, s := range g.snapshot() {
+		writeSample(w, g.metricName, g.labels, s.labelValues, "", "", s.value)
+	}
+}
+
+// Histogram is a histogram metric. The label values passed to its methods must match its label names.
+type Histogram struct {
+	*family
+	buckets []float64
+}
+
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.update(labelValues, func(s *series) {
+		if s.counts == nil {
+			s.counts = make([]uint64, len(h.buckets))
+		}
+		for i, bound := range h.buckets {
+			if v <= bound {
+				s.counts[i]++
+			}
+		}
+		s.count++
+		s.value += v
+	})
+}
+
+// Since observes the seconds elapsed since start.
+func (h *Histogram) Since(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.writeHeader(w)
+	for _, s := range h.snapshot() {
+		for i, bound := range h.buckets {
+			var count uint64
+			if s.counts != nil {
+				count = s.counts[i]
+			}
+			writeSample(w, h.metricName+"_bucket", h.labels, s.labelValues, "le", formatFloat(bound), float64(count))
+		}
+		writeSample(w, h.metricName+"_bucket", h.labels, s.labelValues, "le", "+Inf", float64(s.count))
+		writeSample(w, h.metricName+"_sum", h.labels, s.labelValues, "", "", s.value)
+		writeSample(w, h.metricName+"_count", h.labels, s.labelValues, "", "", float64(s.count))
+	}
+}
+
+// family is a metric and its series, one for every set of label values.
+type family struct {
+	metricName string
+	help       string
+	typ        string
+	labels     []string
+	mtx        sync.Mutex
+	series     map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	counts      []uint64
+	count       uint64
+}
+
+func newFamily(name, help, typ string, labels []string) *family {
+	return &family{metricName: name, help: help, typ: typ, labels: labels, series: make(map[string]*series)}
+}
+
+func (f *family) name() string {
+	return f.metricName
+}
+
+func (f *family) update(labelValues []string, fn func(s *series)) {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", f.metricName, len(f.labels),
+			len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		f.series[key] = s
+	}
+	fn(s)
+}
+
+// snapshot returns a copy of the series sorted by label values.
+func (f *family) snapshot() []series {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	all := make([]series, 0, len(keys))
+	for _, key := range keys {
+		s := *f.series[key]
+		s.counts = append([]uint64(nil), s.counts...)
+		all = append(all, s)
+	}
+	return all
+}
+
+func (f *family) writeHeader(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", f.metricName, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.metricName, f.typ)
+}
+
+func writeSample(w io.Writer, name string, labels, labelValues []string, extraLabel, extraValue string, value float64) {
+	pairs := make([]string, 0, len(labels)+1)
+	for i, label := range labels {
+		pairs = append(pairs, label+`="`+escapeLabel(labelValues[i])+`"`)
+	}
+	if extraLabel != "" {
+		pairs = append(pairs, extraLabel+`="`+extraValue+`"`)
+	}
+
+	if len(pairs) == 0 {
+		fmt.Fprintf(w, "%s %s\n", name, formatFloat(value))
+		return
+	}
+	fmt.Fprintf(w, "%s{%s} %s\n", name, strings.Join(pairs, ","), formatFloat(value))
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
@@ -0,0 +1,92 @@
+package metrics_test
+
+import (
+	"bytes"
+	"github.com/msaldanha/realChain/metrics"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+)
+
+var _ = Describe("Metrics", func() {
+
+	var registry *metrics.Registry
+
+	BeforeEach(func() {
+		registry = metrics.NewRegistry()
+	})
+
+	write := func() string {
+		buf := &bytes.Buffer{}
+		Expect(registry.Write(buf)).To(BeNil())
+		return buf.String()
+	}
+
+	It("Should write counters and gauges by label values", func() {
+		requests := registry.NewCounter("requests_total", "Requests handled.", "method", "code")
+		peers := registry.NewGauge("peers", "Healthy peers.")
+
+		requests.Inc("/ledger.Ledger/Register", "OK")
+		requests.Add(2, "/ledger.Ledger/Register", "OK")
+		requests.Inc("/Consensus/Vote", "Unavailable")
+		peers.Set(3)
+		peers.Add(-1)
+
+		Expect(write()).To(Equal(`# HELP peers Healthy peers.
+# TYPE peers gauge
+peers 2
+# HELP requests_total Requests handled.
+# TYPE requests_total counter
+requests_total{method="/Consensus/Vote",code="Unavailable"} 1
+requests_total{method="/ledger.Ledger/Register",code="OK"} 3
+`))
+	})
+
+	It("Should write histogram buckets, sum and count", func() {
+		latency := registry.NewHistogram("latency_seconds", "Call latency.", []float64{1, 0.1}, "method")
+
+		latency.Observe(0.05, "get")
+		latency.Observe(0.5, "get")
+		latency.Observe(2, "get")
+
+		Expect(write()).To(Equal(`# HELP latency_seconds Call latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{method="get",le="0.1"} 1
+latency_seconds_bucket{method="get",le="1"} 2
+latency_seconds_bucket{method="get",le="+Inf"} 3
+latency_seconds_sum{method="get"} 2.55
+latency_seconds_count{method="get"} 3
+`))
+	})
+
+	It("Should escape label values and help", func() {
+		errors := registry.NewCounter("errors_total", "Errors\nby message.", "message")
+		errors.Inc(`say "hi"\now`)
+
+		Expect(write()).To(ContainSubstring(`# HELP errors_total Errors\nby message.`))
+		Expect(write()).To(ContainSubstring(`errors_total{message="say \"hi\"\\now"} 1`))
+	})
+
+	It("Should refuse duplicate metric names and wrong label values", func() {
+		counter := registry.NewCounter("requests_total", "Requests handled.", "method")
+		Expect(func() { registry.NewGauge("requests_total", "Duplicate.") }).To(Panic())
+		Expect(func() { counter.Inc() }).To(Panic())
+	})
+
+	It("Should serve the metrics in the text format", func() {
+		registry.NewCounter("requests_total", "Requests handled.").Inc()
+		server := httptest.NewServer(registry.Handler())
+		defer server.Close()
+
+		response, err := http.Get(server.URL)
+		Expect(err).To(BeNil())
+		defer response.Body.Close()
+		body, err := ioutil.ReadAll(response.Body)
+		Expect(err).To(BeNil())
+
+		Expect(response.Header.Get("Content-Type")).To(HavePrefix("text/plain; version=0.0.4"))
+		Expect(string(body)).To(ContainSubstring("requests_total 1\n"))
+	})
+})
@@ -1,133 +1,693 @@
 package node
 
 import (
-	"fmt"
+	"crypto/tls"
 	"github.com/msaldanha/realChain/address"
+	"github.com/msaldanha/realChain/auth"
 	"github.com/msaldanha/realChain/errors"
 	"github.com/msaldanha/realChain/config"
 	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/gateway"
+	"github.com/msaldanha/realChain/health"
 	"github.com/msaldanha/realChain/keyvaluestore"
 	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/ledgersync"
+	"github.com/msaldanha/realChain/logging"
+	"github.com/msaldanha/realChain/ratelimit"
+	"github.com/msaldanha/realChain/metrics"
 	"github.com/msaldanha/realChain/peerdiscovery"
+	"github.com/msaldanha/realChain/security"
 	"github.com/msaldanha/realChain/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 )
 
-const ErrLedgerNotInitialized = errors.Error("ledger not initialized")
+var (
+	ledgerBlocks       = metrics.NewGauge("realchain_ledger_blocks", "Transactions stored in the ledger.")
+	ledgerAccounts     = metrics.NewGauge("realchain_ledger_accounts", "Address chains in the ledger.")
+	ledgerPendingSends = metrics.NewGauge("realchain_ledger_pending_sends",
+		"Send transactions stored in the ledger that were not received yet.")
+)
+
+const (
+	ErrLedgerNotInitialized = errors.Error("ledger not initialized")
+	ErrInvalidDiscoveryMode = errors.Error("invalid discovery mode")
+	ErrNodeNotInitialized   = errors.Error("node not initialized")
+	ErrBootstrapPending     = errors.Error("bootstrap not finished")
+	ErrNotEnoughPeers       = errors.Error("not enough healthy peers for the quorum")
+)
+
+const (
+	probeTimeout = 2 * time.Second
+)
 
 type Node struct {
-	addrDb keyvaluestore.Storer
-	ld     ledger.Ledger
-	cfg    *viper.Viper
+	addrDb    keyvaluestore.Storer
+	syncState keyvaluestore.Storer
+	ae        *ledgersync.AntiEntropy
+	ts        *ledger.TransactionStore
+	ld        ledger.Ledger
+	events    *ledger.EventBus
+	dis       peerdiscovery.Discoverer
+	pm        *peerdiscovery.PeerManager
+	cfg       *viper.Viper
+	health    *health.Health
+	limiter   *ratelimit.Limiter
+	authz     *auth.Authorizer
+	stores    []keyvaluestore.Storer
+	logger    *log.Logger
+	running   sync.WaitGroup
+	failed    chan error
+	stoppers  []func(ctx context.Context) error
+	// bootstrapped is set once the ledger is in sync with the peers, so the node can vote.
+	bootstrapped int32
 }
 
 func New(cfg *viper.Viper) *Node {
 	return &Node{cfg: cfg}
 }
 
-func (n *Node) Run() {
-	log.Info("Loading address db.")
-	err := n.loadAddrDb()
-	checkError(err)
+// Run starts the node components in order and serves until ctx is done, SIGINT or SIGTERM is received or a server
+// fails. The node then becomes not ready, stops the servers gracefully, waiting up to the shutdown timeout for the
+// in-flight calls and consensus rounds before cancelling them, stops the background components, waits for the
+// asynchronous event handlers and closes the stores. Run returns the error that stopped the node, if any.
+func (n *Node) Run(ctx context.Context) (err error) {
+	err = n.createLogger()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	// The background components keep running until the servers are stopped, as the in-flight votings need them.
+	bgCtx, cancelBg := context.WithCancel(context.Background())
+	n.failed = make(chan error, 1)
+	defer func() {
+		stopErr := n.stopServers()
+		cancel()
+		cancelBg()
+		n.running.Wait()
+		if n.events != nil {
+			n.events.Wait()
+		}
+		closeErr := n.closeStores()
+		if err == nil {
+			err = stopErr
+		}
+		if err == nil {
+			err = closeErr
+		}
+	}()
+	n.handleSignals(ctx, cancel)
+
+	n.logger.Info("Loading address db.")
+	err = n.loadAddrDb()
+	if err != nil {
+		return err
+	}
 
-	log.Info("Creating ledger.")
+	n.logger.Info("Creating ledger.")
 	err = n.createLedger()
-	checkError(err)
+	if err != nil {
+		return err
+	}
 
-	log.Info("Creating server.")
-	srv, err := n.createServer()
-	checkError(err)
+	n.logger.Info("Creating peer discoverer.")
+	err = n.createDiscoverer(bgCtx)
+	if err != nil {
+		return err
+	}
+
+	n.createHealth()
+	err = n.serveOps()
+	if err != nil {
+		return err
+	}
+
+	err = n.bootstrap(ctx)
+	if err != nil {
+		return err
+	}
+	atomic.StoreInt32(&n.bootstrapped, 1)
 
-	serverCh := make(chan error)
+	n.startAntiEntropy(bgCtx)
 
-	go func() { serverCh <- srv.Run() }()
+	opts, err := security.ServerOptions(n.cfg)
+	if err != nil {
+		return err
+	}
 
-	log.Info("Ready.")
-	er := <-serverCh
-	checkError(er)
+	n.logger.Info("Creating server.")
+	srv, err := n.createServer()
+	if err != nil {
+		return err
+	}
 
-	fmt.Println("Done.")
+	// The gateway is started first so it is stopped after the server, which ends the subscriptions it streams.
+	if n.cfg.GetString(config.CfgGatewayServer) != "" {
+		n.logger.Info("Creating gateway.")
+		gw, err := n.createGateway(srv)
+		if err != nil {
+			srv.Stop(context.Background())
+			return err
+		}
+		n.serve(gw.Run, gw.Shutdown)
+	}
+	n.serve(func() error { return srv.Run(opts...) }, srv.Stop)
+
+	n.logger.Info("Started.")
+	select {
+	case <-ctx.Done():
+	case err = <-n.failed:
+		n.logger.Errorf("Server failed: %s", err)
+	}
+	return err
 }
 
-func (n *Node) Init() {
-	log.Info("Loading address db.")
-	err := n.loadAddrDb()
-	checkError(err)
+func (n *Node) Init() error {
+	err := n.createLogger()
+	if err != nil {
+		return err
+	}
+
+	n.logger.Info("Loading address db.")
+	err = n.loadAddrDb()
+	if err != nil {
+		return err
+	}
+	defer n.closeStores()
 
-	log.Info("Creating address.")
+	n.logger.Info("Creating address.")
 	addr, err := address.NewAddressWithKeys()
-	checkError(err)
+	if err != nil {
+		return err
+	}
 
-	log.Info("Saving address.")
-	err = n.addrDb.Put(addr.Address, addr.ToBytes())
-	checkError(err)
+	n.logger.Info("Saving address.")
+	addrBytes, err := addr.ToBytes()
+	if err != nil {
+		return err
+	}
+	err = n.addrDb.Put(addr.Address, addrBytes)
+	if err != nil {
+		return err
+	}
+
+	n.logger.WithField(logging.AddressField, addr.Address).Info("Node address created.")
+	return nil
+}
+
+// createLogger creates the logger of the node, which is injected into its components.
+func (n *Node) createLogger() error {
+	logger, err := logging.New(n.cfg)
+	if err != nil {
+		return err
+	}
+	n.logger = logger
+	return nil
+}
+
+// handleSignals cancels the node context when SIGINT or SIGTERM is received.
+func (n *Node) handleSignals(ctx context.Context, cancel context.CancelFunc) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case sig := <-signals:
+			n.logger.Infof("Received %s.", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// serve runs a server in the background. A server that fails stops the node. When the node stops, the servers
+// are stopped with their stop functions, in the reverse order they were started.
+func (n *Node) serve(run func() error, stop func(ctx context.Context) error) {
+	n.stoppers = append(n.stoppers, stop)
+	go func() {
+		err := run()
+		if err != nil {
+			select {
+			case n.failed <- err:
+			default:
+			}
+		}
+	}()
+}
+
+// stopServers makes the node not ready and stops the servers, giving them up to the shutdown timeout to finish the
+// in-flight calls and consensus rounds. A zero timeout waits for them without limit.
+func (n *Node) stopServers() error {
+	if n.health != nil {
+		n.health.Shutdown()
+	}
+	if len(n.stoppers) == 0 {
+		return nil
+	}
+
+	n.logger.Info("Stopping.")
+	ctx := context.Background()
+	if timeout := n.cfg.GetDuration(config.CfgNodeShutdownTimeout); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var firstErr error
+	for i := len(n.stoppers) - 1; i >= 0; i-- {
+		err := n.stoppers[i](ctx)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	n.stoppers = nil
+	return firstErr
+}
 
-	fmt.Printf("Done. Node address: %s\n", addr.Address)
+// quorum returns the number of healthy peers the node needs to be ready and to vote on the transfers it registers.
+func (n *Node) quorum() int {
+	return n.cfg.GetInt(config.CfgNodeQuorum)
 }
 
-func (n *Node) getNodeAddr() *address.Address {
+// createHealth creates the health of the node: it is alive if its stores answer, and ready once the ledger is
+// initialized and bootstrapped and enough peers are healthy to reach the quorum.
+func (n *Node) createHealth() {
+	n.health = health.New(probeTimeout)
+	n.health.AddLiveness("store", func() error {
+		err := n.ts.Check()
+		if err != nil {
+			return err
+		}
+		_, err = n.events.LastSeq()
+		return err
+	})
+	n.health.AddReadiness("ledger", func() error {
+		if n.ts.IsEmpty() {
+			return ErrLedgerNotInitialized
+		}
+		return nil
+	})
+	n.health.AddReadiness("bootstrap", func() error {
+		if atomic.LoadInt32(&n.bootstrapped) == 0 {
+			return ErrBootstrapPending
+		}
+		return nil
+	})
+	n.health.AddReadiness("peers", func() error {
+		peers, err := n.dis.Peers()
+		if err != nil {
+			return err
+		}
+		if len(peers) < n.quorum() {
+			return ErrNotEnoughPeers
+		}
+		return nil
+	})
+}
+
+// serveOps serves the metrics and the health endpoints, on the same server if they have the same address.
+func (n *Node) serveOps() error {
+	muxes := make(map[string]*http.ServeMux)
+	handle := func(addr, pattern string, handler http.Handler) {
+		if addr == "" {
+			return
+		}
+		if muxes[addr] == nil {
+			muxes[addr] = http.NewServeMux()
+		}
+		muxes[addr].Handle(pattern, handler)
+	}
+	handle(n.cfg.GetString(config.CfgMetricsServer), "/metrics", metrics.Handler())
+	handle(n.cfg.GetString(config.CfgHealthServer), "/healthz", n.health.LiveHandler())
+	handle(n.cfg.GetString(config.CfgHealthServer), "/readyz", n.health.ReadyHandler())
+
+	for addr, mux := range muxes {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+		n.logger.Infof("Serving operations endpoints on %s.", addr)
+		srv := &http.Server{Handler: mux}
+		n.serve(func() error { return serveHTTP(srv, listener) }, srv.Shutdown)
+	}
+	return nil
+}
+
+func serveHTTP(srv *http.Server, listener net.Listener) error {
+	err := srv.Serve(listener)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// start runs fn in the background. Run waits for it to return before closing the stores, so fn must return
+// when the node context is done.
+func (n *Node) start(fn func()) {
+	n.running.Add(1)
+	go func() {
+		defer n.running.Done()
+		fn()
+	}()
+}
+
+func (n *Node) getNodeAddr() (*address.Address, error) {
 	addrs, err := n.addrDb.GetAll()
-	checkError(err)
-	addr := address.NewAddressFromBytes(addrs[0])
-	return addr
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, ErrNodeNotInitialized
+	}
+	return address.NewAddressFromBytes(addrs[0]), nil
 }
 
 func (n *Node) loadAddrDb() error {
-	addrOptions := prepareOptions(config.AddressBucket,
-		filepath.Join(n.cfg.GetString(config.CfgDataFolder), n.cfg.GetString(config.CfgNodeAddressesFile)))
+	addrDb, err := n.openStore(config.AddressBucket, config.CfgNodeAddressesFile)
+	if err != nil {
+		return err
+	}
 
-	addrDb := keyvaluestore.NewBoltKeyValueStore()
+	n.addrDb = addrDb
+	return nil
+}
 
-	err := addrDb.Init(addrOptions)
+func (n *Node) createLedger() error {
+	txDb, err := n.openStore(config.TxBucket, config.CfgLedgerChainFile)
 	if err != nil {
 		return err
 	}
 
-	n.addrDb = addrDb
+	eventsDb, err := n.openStore(config.EventsBucket, config.CfgLedgerEventsFile)
+	if err != nil {
+		return err
+	}
+
+	indexDb, err := n.openStore(config.IndexBucket, config.CfgLedgerIndexFile)
+	if err != nil {
+		return err
+	}
+
+	val := ledger.NewValidatorCreator()
+	n.ts = ledger.NewTransactionStoreWithIndex(txDb, indexDb, val)
+	err = n.ts.IndexChains()
+	if err != nil {
+		return err
+	}
+	n.events = ledger.NewEventBus(eventsDb)
+	n.events.HandleAsync(ledger.ForkDetected, func(event *ledger.Event) {
+		n.logger.WithFields(log.Fields{logging.TxField: event.Tx.Hash, logging.AddressField: event.Account()}).
+			Warnf("Fork detected: transaction does not follow the chain head %s", event.Related.Hash)
+	})
+	n.events.HandleAsync(ledger.Rollback, func(event *ledger.Event) {
+		n.logger.WithFields(log.Fields{logging.TxField: event.Tx.Hash, logging.AddressField: event.Account()}).
+			Warn("Transaction rolled back")
+	})
+	err = n.trackLedgerStats()
+	if err != nil {
+		return err
+	}
+	ld := ledger.NewLocalLedgerWithEvents(n.ts, n.events)
+	ld.SetLogger(n.logger)
+	n.ld = ld
+
 	return nil
 }
 
-func checkError(err error) {
+// trackLedgerStats sets the ledger gauges from the stored transactions, then keeps them up to date from the ledger
+// events, so the metrics scrapes do not read the whole ledger.
+func (n *Node) trackLedgerStats() error {
+	stats, err := n.ts.GetStats()
 	if err != nil {
-		fmt.Printf("Error: %s\n", err)
-		os.Exit(1)
+		return err
 	}
+	ledgerBlocks.Set(float64(stats.Blocks))
+	ledgerAccounts.Set(float64(stats.Accounts))
+	ledgerPendingSends.Set(float64(stats.PendingSends))
+
+	n.events.Handle(ledger.BlockStored, func(event *ledger.Event) {
+		ledgerBlocks.Add(1)
+		if event.Tx.Type == ledger.Transaction_OPEN {
+			ledgerAccounts.Add(1)
+		}
+	})
+	n.events.Handle(ledger.SendPending, func(event *ledger.Event) { ledgerPendingSends.Add(1) })
+	n.events.Handle(ledger.ReceiveClaimed, func(event *ledger.Event) { ledgerPendingSends.Add(-1) })
+	return nil
 }
 
-func (n *Node) createLedger() error {
-	txOptions := prepareOptions(config.TxBucket,
-		filepath.Join(n.cfg.GetString(config.CfgDataFolder), n.cfg.GetString(config.CfgLedgerChainFile)))
-	txDb := keyvaluestore.NewBoltKeyValueStore()
-	err := txDb.Init(txOptions)
+func (n *Node) createDiscoverer(ctx context.Context) error {
+	dialOpt, err := security.DialOption(n.cfg)
 	if err != nil {
-		log.Fatal("Failed to init ledger chain: " + err.Error())
+		return err
 	}
 
-	val := ledger.NewValidatorCreator()
-	bs := ledger.NewTransactionStore(txDb, val)
-	if bs.IsEmpty() {
-		return ErrLedgerNotInitialized
+	source, err := n.createPeerSource(ctx, dialOpt)
+	if err != nil {
+		return err
+	}
+
+	addr, err := n.getNodeAddr()
+	if err != nil {
+		return err
 	}
 
-	n.ld = ledger.NewLocalLedger(bs)
+	id := consensus.NewIdentity(addr.Keys, n.cfg.GetString(config.CfgChainId))
+	pm := peerdiscovery.NewPeerManager(source, id, dialOpt, n.cfg)
+	pm.SetLogger(n.logger)
+	if dis, ok := source.(*peerdiscovery.DynamicDiscoverer); ok {
+		// The peers are exchanged through the connections of the manager, whose handshakes tell who asks.
+		dis.SetPeerClients(pm)
+	}
+	err = pm.Init()
+	if err != nil {
+		return err
+	}
+	n.start(func() { pm.Run(ctx) })
 
+	n.dis = pm
+	n.pm = pm
 	return nil
 }
 
+// createPeerSource creates and initializes the discoverer, set by the discovery mode, that finds the peers
+// managed by the peer manager.
+func (n *Node) createPeerSource(ctx context.Context, dialOpt grpc.DialOption) (peerdiscovery.Discoverer, error) {
+	switch n.cfg.GetString(config.CfgDiscovery) {
+	case config.DiscoveryStatic:
+		dis := peerdiscovery.NewStaticDiscoverer(n.cfg, dialOpt)
+		return dis, dis.Init()
+	case config.DiscoveryDynamic:
+		table, err := n.openStore(config.PeersBucket, config.CfgDiscoveryTableFile)
+		if err != nil {
+			return nil, err
+		}
+		dis := peerdiscovery.NewDynamicDiscoverer(n.cfg, table, dialOpt)
+		dis.SetLogger(n.logger)
+		err = dis.Init()
+		if err != nil {
+			return nil, err
+		}
+		n.start(func() { dis.Run(ctx, n.cfg.GetDuration(config.CfgDiscoveryInterval)) })
+		return dis, nil
+	case config.DiscoveryUdp:
+		addr, err := n.getNodeAddr()
+		if err != nil {
+			return nil, err
+		}
+		dis := peerdiscovery.NewUdpDiscoverer(n.cfg, addr.Keys, dialOpt)
+		dis.SetLogger(n.logger)
+		err = dis.Init()
+		if err != nil {
+			return nil, err
+		}
+		n.start(func() { dis.Run(ctx) })
+		return dis, nil
+	default:
+		return nil, ErrInvalidDiscoveryMode
+	}
+}
+
+func (n *Node) bootstrap(ctx context.Context) error {
+	var err error
+	n.syncState, err = n.openStore(config.SyncBucket, config.CfgNodeSyncStateFile)
+	if err != nil {
+		return err
+	}
+
+	bootstrapper := ledgersync.NewBootstrapper(n.ld, n.dis, n.syncState)
+	bootstrapper.SetLogger(n.logger)
+	pending, err := bootstrapper.IsPending()
+	if err != nil {
+		return err
+	}
+
+	if !n.ts.IsEmpty() && !pending {
+		return nil
+	}
+
+	if !pending && !n.cfg.GetBool(config.CfgNodeBootstrap) {
+		return ErrLedgerNotInitialized
+	}
+
+	n.logger.Info("Bootstrapping ledger from peers.")
+	return bootstrapper.Run(ctx)
+}
+
+func (n *Node) startAntiEntropy(ctx context.Context) {
+	interval := n.cfg.GetDuration(config.CfgNodeAntiEntropy)
+	if interval <= 0 {
+		n.logger.Info("Anti-entropy disabled.")
+		return
+	}
+
+	n.logger.Infof("Starting anti-entropy every %s.", interval)
+	n.ae = ledgersync.NewAntiEntropy(n.ld, n.dis, interval)
+	n.ae.SetLogger(n.logger)
+	n.start(func() { n.ae.Run(ctx) })
+}
+
 func (n *Node) createServer() (*server.Server, error) {
-	addr := n.getNodeAddr()
-	con := consensus.NewConsensus(n.ld, addr)
-	dis := peerdiscovery.NewStaticDiscoverer(n.cfg)
+	addr, err := n.getNodeAddr()
+	if err != nil {
+		return nil, err
+	}
+	votes, err := n.openStore(config.VotesBucket, config.CfgNodeVotesFile)
+	if err != nil {
+		return nil, err
+	}
+	con := consensus.NewConsensus(n.ld, addr, n.cfg.GetString(config.CfgChainId))
+	con.SetVoteStore(votes)
+	con.SetLogger(n.logger)
+
+	n.authz, err = auth.New(n.cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	listener, err := net.Listen("tcp", n.cfg.GetString(config.CfgNodeServer))
+	// The admin listener serves the metrics too, with the TLS of the gRPC servers.
+	tlsConfig, err := security.ServerConfig(n.cfg)
+	if err != nil {
+		return nil, err
+	}
+	listeners, err := n.listen()
 	if err != nil {
 		return nil, err
 	}
-	return server.New(n.ld, con, dis, listener), nil
+	srv := server.New(n.ld, n.events, con, n.dis, listeners[0])
+	con.SetVoters(consensus.VoterSets{srv, n.pm})
+	con.SetQuorum(n.quorum())
+	srv.SetAdminListener(listeners[1])
+	if listeners[1] != nil {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		srv.SetAdminHandler(mux, tlsConfig)
+	}
+	srv.SetLocalListener(listeners[2])
+	srv.SetLogger(n.logger)
+	srv.SetQuorum(n.quorum())
+	srv.SetChainId(n.cfg.GetString(config.CfgChainId))
+	srv.SetAdmin(server.NewAdmin(srv, addr.Address, n.pm, n.ae, n.cfg))
+	srv.SetHealth(n.health)
+	n.limiter = ratelimit.New(n.cfg)
+	srv.SetLimiter(n.limiter)
+	srv.SetAuthorizer(n.authz)
+	return srv, nil
+}
+
+// listen opens the listeners of the server: the public one, set in node.server, and the admin one, set in
+// admin.server, and the Unix domain socket set in node.socket, which are nil if not set.
+func (n *Node) listen() ([]net.Listener, error) {
+	listeners := make([]net.Listener, 3)
+	closeAll := func() {
+		for _, listener := range listeners {
+			if listener != nil {
+				listener.Close()
+			}
+		}
+	}
+
+	var err error
+	listeners[0], err = net.Listen("tcp", n.cfg.GetString(config.CfgNodeServer))
+	if err != nil {
+		return nil, err
+	}
+	if addr := n.cfg.GetString(config.CfgAdminServer); addr != "" {
+		listeners[1], err = net.Listen("tcp", addr)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+	}
+	if SocketPath(n.cfg) != "" {
+		listeners[2], err = listenSocket(n.cfg)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+	}
+	return listeners, nil
+}
+
+// createGateway creates the HTTP gateway of the ledger service, using TLS if it is enabled.
+func (n *Node) createGateway(srv *server.Server) (*gateway.Gateway, error) {
+	listener, err := net.Listen("tcp", n.cfg.GetString(config.CfgGatewayServer))
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := security.ServerConfig(n.cfg)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	gw := gateway.New(srv, listener)
+	gw.SetLogger(n.logger)
+	gw.SetLimiter(n.limiter)
+	gw.SetAuthorizer(n.authz)
+	return gw, nil
+}
+
+// openStore opens the bolt store whose file name is set in the fileCfg configuration property. The store is
+// closed by closeStores.
+func (n *Node) openStore(bucketName, fileCfg string) (keyvaluestore.Storer, error) {
+	options := prepareOptions(bucketName,
+		filepath.Join(n.cfg.GetString(config.CfgDataFolder), n.cfg.GetString(fileCfg)))
+	store := keyvaluestore.NewBoltKeyValueStore()
+	err := store.Init(options)
+	if err != nil {
+		return nil, err
+	}
+	n.stores = append(n.stores, store)
+	return store, nil
+}
+
+// closeStores closes the opened stores in the reverse order they were opened.
+func (n *Node) closeStores() error {
+	var firstErr error
+	for i := len(n.stores) - 1; i >= 0; i-- {
+		err := n.stores[i].Close()
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	n.stores = nil
+	return firstErr
 }
 
 func prepareOptions(bucketName, filepath string) *keyvaluestore.BoltKeyValueStoreOptions {
@@ -0,0 +1,256 @@
+package node_test
+
+import (
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/keyvaluestore"
+	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/node"
+	"github.com/msaldanha/realChain/tests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ = Describe("Node", func() {
+
+	var dataFolder string
+	var cfg *viper.Viper
+
+	BeforeEach(func() {
+		var err error
+		dataFolder, err = ioutil.TempDir("", "node")
+		Expect(err).To(BeNil())
+
+		cfg = viper.New()
+		cfg.Set(config.CfgDataFolder, dataFolder)
+		cfg.Set(config.CfgLedgerChainFile, "chain.db")
+		cfg.Set(config.CfgLedgerEventsFile, "events.db")
+		cfg.Set(config.CfgLedgerIndexFile, "index.db")
+		cfg.Set(config.CfgNodeAddressesFile, "addresses.db")
+		cfg.Set(config.CfgNodeSyncStateFile, "syncstate.db")
+		cfg.Set(config.CfgNodeVotesFile, "votes.db")
+		cfg.Set(config.CfgNodeServer, "127.0.0.1:0")
+		cfg.Set(config.CfgNodeShutdownTimeout, "5s")
+		cfg.Set(config.CfgDiscovery, config.DiscoveryStatic)
+		cfg.Set(config.CfgDiscoveryPing, "1s")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dataFolder)
+	})
+
+	openStore := func(bucketName, file string) keyvaluestore.Storer {
+		store := keyvaluestore.NewBoltKeyValueStore()
+		err := store.Init(&keyvaluestore.BoltKeyValueStoreOptions{BucketName: bucketName,
+			DbFile: filepath.Join(dataFolder, file)})
+		Expect(err).To(BeNil())
+		return store
+	}
+
+	initLedger := func() {
+		store := openStore(config.TxBucket, "chain.db")
+		defer store.Close()
+		genesisTx, _ := tests.CreateGenesisTransaction(1000)
+		ld := ledger.NewLocalLedger(ledger.NewTransactionStore(store, ledger.NewValidatorCreator()))
+		Expect(ld.Initialize(genesisTx)).To(BeNil())
+	}
+
+	It("Should stop and close the stores when the context is done", func() {
+		Expect(node.New(cfg).Init()).To(BeNil())
+		initLedger()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- node.New(cfg).Run(ctx) }()
+
+		Consistently(done, 200*time.Millisecond).ShouldNot(Receive())
+		cancel()
+		Eventually(done, 5*time.Second).Should(Receive(BeNil()))
+
+		for file, bucketName := range map[string]string{"chain.db": config.TxBucket, "events.db": config.EventsBucket,
+			"addresses.db": config.AddressBucket, "syncstate.db": config.SyncBucket} {
+			Expect(openStore(bucketName, file).Close()).To(BeNil())
+		}
+	})
+
+	It("Should serve the metrics when enabled", func() {
+		Expect(node.New(cfg).Init()).To(BeNil())
+		initLedger()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		metricsAddr := listener.Addr().String()
+		listener.Close()
+		cfg.Set(config.CfgMetricsServer, metricsAddr)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- node.New(cfg).Run(ctx) }()
+		defer func() {
+			cancel()
+			Eventually(done, 5*time.Second).Should(Receive(BeNil()))
+		}()
+
+		scrape := func() string {
+			response, err := http.Get("http://" + metricsAddr + "/metrics")
+			if err != nil {
+				return ""
+			}
+			defer response.Body.Close()
+			body, _ := ioutil.ReadAll(response.Body)
+			return string(body)
+		}
+		Eventually(scrape, 5*time.Second).Should(ContainSubstring("realchain_ledger_blocks 1\n"))
+		Expect(scrape()).To(ContainSubstring("realchain_ledger_accounts 1\n"))
+		Expect(scrape()).To(ContainSubstring("# TYPE realchain_store_operation_duration_seconds histogram"))
+	})
+
+	It("Should serve the metrics on the admin listener", func() {
+		Expect(node.New(cfg).Init()).To(BeNil())
+		initLedger()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		adminAddr := listener.Addr().String()
+		listener.Close()
+		cfg.Set(config.CfgAdminServer, adminAddr)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- node.New(cfg).Run(ctx) }()
+		defer func() {
+			cancel()
+			Eventually(done, 5*time.Second).Should(Receive(BeNil()))
+		}()
+
+		scrape := func() string {
+			response, err := http.Get("http://" + adminAddr + "/metrics")
+			if err != nil {
+				return ""
+			}
+			defer response.Body.Close()
+			body, _ := ioutil.ReadAll(response.Body)
+			return string(body)
+		}
+		Eventually(scrape, 5*time.Second).Should(ContainSubstring("realchain_ledger_blocks 1\n"))
+	})
+
+	It("Should not be ready without enough peers for the quorum", func() {
+		Expect(node.New(cfg).Init()).To(BeNil())
+		initLedger()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		opsAddr := listener.Addr().String()
+		listener.Close()
+		cfg.Set(config.CfgHealthServer, opsAddr)
+		cfg.Set(config.CfgMetricsServer, opsAddr)
+		cfg.Set(config.CfgNodeQuorum, 1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- node.New(cfg).Run(ctx) }()
+		defer func() {
+			cancel()
+			Eventually(done, 5*time.Second).Should(Receive(BeNil()))
+		}()
+
+		get := func(path string) (int, string) {
+			response, err := http.Get("http://" + opsAddr + path)
+			if err != nil {
+				return 0, ""
+			}
+			defer response.Body.Close()
+			body, _ := ioutil.ReadAll(response.Body)
+			return response.StatusCode, string(body)
+		}
+		statusCode := func(path string) func() int {
+			return func() int {
+				code, _ := get(path)
+				return code
+			}
+		}
+		Eventually(statusCode("/healthz"), 5*time.Second).Should(Equal(http.StatusOK))
+		code, body := get("/readyz")
+		Expect(code).To(Equal(http.StatusServiceUnavailable))
+		Expect(body).To(ContainSubstring(node.ErrNotEnoughPeers.Error()))
+		Expect(statusCode("/metrics")()).To(Equal(http.StatusOK))
+	})
+
+	It("Should be ready once bootstrapped when no quorum is required", func() {
+		Expect(node.New(cfg).Init()).To(BeNil())
+		initLedger()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		healthAddr := listener.Addr().String()
+		listener.Close()
+		cfg.Set(config.CfgHealthServer, healthAddr)
+		cfg.Set(config.CfgNodeQuorum, 0)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- node.New(cfg).Run(ctx) }()
+		defer func() {
+			cancel()
+			Eventually(done, 5*time.Second).Should(Receive(BeNil()))
+		}()
+
+		ready := func() int {
+			response, err := http.Get("http://" + healthAddr + "/readyz")
+			if err != nil {
+				return 0
+			}
+			response.Body.Close()
+			return response.StatusCode
+		}
+		Eventually(ready, 5*time.Second).Should(Equal(http.StatusOK))
+	})
+
+	It("Should serve the local clients on the Unix domain socket", func() {
+		Expect(node.New(cfg).Init()).To(BeNil())
+		initLedger()
+
+		cfg.Set(config.CfgNodeSocket, "node.sock")
+		cfg.Set(config.CfgNodeSocketMode, "0600")
+		socket := node.SocketPath(cfg)
+		Expect(socket).To(Equal(filepath.Join(dataFolder, "node.sock")))
+		Expect(ioutil.WriteFile(socket, nil, 0644)).To(BeNil())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- node.New(cfg).Run(ctx) }()
+
+		dial := func() error {
+			conn, err := net.Dial("unix", socket)
+			if err == nil {
+				conn.Close()
+			}
+			return err
+		}
+		Eventually(dial, 5*time.Second).Should(BeNil())
+		info, err := os.Stat(socket)
+		Expect(err).To(BeNil())
+		Expect(info.Mode() & os.ModeSocket).NotTo(BeZero())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		cancel()
+		Eventually(done, 5*time.Second).Should(Receive(BeNil()))
+		_, err = os.Stat(socket)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("Should return the error that prevents it from starting", func() {
+		err := node.New(cfg).Run(context.Background())
+		Expect(err).To(Equal(node.ErrNodeNotInitialized))
+
+		Expect(openStore(config.AddressBucket, "addresses.db").Close()).To(BeNil())
+	})
+})
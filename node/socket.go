@@ -0,0 +1,63 @@
+package node
+
+import (
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/spf13/viper"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	ErrSocketInUse       = errors.Error("socket in use by another process")
+	ErrInvalidSocketMode = errors.Error("invalid socket mode")
+)
+
+const socketDialTimeout = time.Second
+
+// SocketPath returns the path of the Unix domain socket set in node.socket, relative to the data folder, or an
+// empty string if the node has no socket.
+func SocketPath(cfg *viper.Viper) string {
+	file := cfg.GetString(config.CfgNodeSocket)
+	if file == "" || filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(cfg.GetString(config.CfgDataFolder), file)
+}
+
+// listenSocket listens on the Unix domain socket of the node, whose file gets the permissions set in
+// node.socketmode. The socket file left by a node that did not stop cleanly is replaced, but not the one of a
+// running node.
+func listenSocket(cfg *viper.Viper) (net.Listener, error) {
+	mode, err := strconv.ParseUint(cfg.GetString(config.CfgNodeSocketMode), 8, 32)
+	if err != nil {
+		return nil, ErrInvalidSocketMode
+	}
+
+	file := SocketPath(cfg)
+	if _, err := os.Stat(file); err == nil {
+		conn, err := net.DialTimeout("unix", file, socketDialTimeout)
+		if err == nil {
+			conn.Close()
+			return nil, ErrSocketInUse
+		}
+		err = os.Remove(file)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	listener, err := net.Listen("unix", file)
+	if err != nil {
+		return nil, err
+	}
+	err = os.Chmod(file, os.FileMode(mode))
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
@@ -0,0 +1,49 @@
+package peerdiscovery
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/golang/protobuf/proto"
+	"github.com/msaldanha/realChain/crypto"
+	"strconv"
+)
+
+func (m *Announcement) Hash() []byte {
+	timestamp := []byte(strconv.FormatInt(m.Timestamp, 10))
+	hashableBytes := [][]byte{[]byte(m.Endpoint), m.PubKey, []byte(m.ChainId), timestamp}
+	headers := bytes.Join(hashableBytes, []byte{})
+	hash := sha256.Sum256(headers)
+	return []byte(hex.EncodeToString(hash[:]))
+}
+
+func (m *Announcement) Sign(privateKey *ecdsa.PrivateKey) error {
+	s, err := crypto.Sign(m.Hash(), privateKey)
+	if err != nil {
+		return err
+	}
+	m.Signature = s
+	return nil
+}
+
+func (m *Announcement) VerifySignature() bool {
+	if len(m.Signature) == 0 || len(m.PubKey) == 0 {
+		return false
+	}
+	return crypto.VerifySignature(m.Signature, m.PubKey, m.Hash())
+}
+
+func (m *Announcement) ToBytes() []byte {
+	data, _ := proto.Marshal(m)
+	return data
+}
+
+func NewAnnouncementFromBytes(d []byte) (*Announcement, error) {
+	announcement := &Announcement{}
+	err := proto.Unmarshal(d, announcement)
+	if err != nil {
+		return nil, err
+	}
+	return announcement, nil
+}
@@ -0,0 +1,112 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: peerdiscovery/announcement.proto
+
+package peerdiscovery
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type Announcement struct {
+	Endpoint             string   `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	PubKey               []byte   `protobuf:"bytes,2,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
+	ChainId              string   `protobuf:"bytes,3,opt,name=chainId,proto3" json:"chainId,omitempty"`
+	Timestamp            int64    `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Signature            []byte   `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Announcement) Reset()         { *m = Announcement{} }
+func (m *Announcement) String() string { return proto.CompactTextString(m) }
+func (*Announcement) ProtoMessage()    {}
+func (*Announcement) Descriptor() ([]byte, []int) {
+	return fileDescriptor_announcement_5ba8c951030dd81b, []int{0}
+}
+func (m *Announcement) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Announcement.Unmarshal(m, b)
+}
+func (m *Announcement) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Announcement.Marshal(b, m, deterministic)
+}
+func (dst *Announcement) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Announcement.Merge(dst, src)
+}
+func (m *Announcement) XXX_Size() int {
+	return xxx_messageInfo_Announcement.Size(m)
+}
+func (m *Announcement) XXX_DiscardUnknown() {
+	xxx_messageInfo_Announcement.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Announcement proto.InternalMessageInfo
+
+func (m *Announcement) GetEndpoint() string {
+	if m != nil {
+		return m.Endpoint
+	}
+	return ""
+}
+
+func (m *Announcement) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *Announcement) GetChainId() string {
+	if m != nil {
+		return m.ChainId
+	}
+	return ""
+}
+
+func (m *Announcement) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Announcement) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Announcement)(nil), "peerdiscovery.Announcement")
+}
+
+func init() {
+	proto.RegisterFile("peerdiscovery/announcement.proto", fileDescriptor_announcement_5ba8c951030dd81b)
+}
+
+var fileDescriptor_announcement_5ba8c951030dd81b = []byte{
+	// 173 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x28, 0x48, 0x4d, 0x2d,
+	0x4a, 0xc9, 0x2c, 0x4e, 0xce, 0x2f, 0x4b, 0x2d, 0xaa, 0xd4, 0x4f, 0xcc, 0xcb, 0xcb, 0x2f, 0xcd,
+	0x4b, 0x4e, 0xcd, 0x4d, 0xcd, 0x2b, 0xd1, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x45, 0x51,
+	0xa1, 0x34, 0x83, 0x91, 0x8b, 0xc7, 0x11, 0x49, 0x95, 0x90, 0x14, 0x17, 0x47, 0x6a, 0x5e, 0x4a,
+	0x41, 0x7e, 0x66, 0x5e, 0x89, 0x04, 0xa3, 0x02, 0xa3, 0x06, 0x67, 0x10, 0x9c, 0x2f, 0x24, 0xc6,
+	0xc5, 0x56, 0x50, 0x9a, 0xe4, 0x9d, 0x5a, 0x29, 0xc1, 0xa4, 0xc0, 0xa8, 0xc1, 0x13, 0x04, 0xe5,
+	0x09, 0x49, 0x70, 0xb1, 0x27, 0x67, 0x24, 0x66, 0xe6, 0x79, 0xa6, 0x48, 0x30, 0x83, 0xb5, 0xc0,
+	0xb8, 0x42, 0x32, 0x5c, 0x9c, 0x25, 0x99, 0xb9, 0xa9, 0xc5, 0x25, 0x89, 0xb9, 0x05, 0x12, 0x2c,
+	0x0a, 0x8c, 0x1a, 0xcc, 0x41, 0x08, 0x01, 0x90, 0x6c, 0x71, 0x66, 0x7a, 0x5e, 0x62, 0x49, 0x69,
+	0x51, 0xaa, 0x04, 0x2b, 0xd8, 0x48, 0x84, 0x80, 0x13, 0x7f, 0x14, 0xaa, 0x5b, 0x93, 0xd8, 0xc0,
+	0x3e, 0x30, 0x06, 0x0c, 0x00, 0xee, 0x33, 0xe5, 0xc2, 0xe5, 0x00, 0x00, 0x00,
+}
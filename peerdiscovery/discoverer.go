@@ -1,10 +1,50 @@
 package peerdiscovery
 
-import "github.com/msaldanha/realChain/consensus"
+import (
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/spf13/viper"
+	"sort"
+)
 
 //go:generate mockgen -destination=../tests/mock_discoverer.go -package=tests github.com/msaldanha/realChain/peerdiscovery Discoverer
 
 type Discoverer interface {
 	Init() error
 	Peers() ([]consensus.ConsensusClient, error)
+	KnownPeers() ([]*consensus.Peer, error)
+	AddPeer(address string) error
+	Report(peer consensus.ConsensusClient, reason string)
+	// PeerIdentity returns the identity the peer proved in its handshake, nil if it did not run one.
+	PeerIdentity(peer consensus.ConsensusClient) *consensus.NodeInfo
+}
+
+// ActiveSource is implemented by the discoverers that select the peers to connect to among the known ones. The
+// PeerManager then manages the active peers only.
+type ActiveSource interface {
+	ActivePeers() ([]*consensus.Peer, error)
+}
+
+// PeerClients returns the clients of the healthy peers by their address.
+type PeerClients interface {
+	PeerClients() map[string]consensus.ConsensusClient
+}
+
+// advertisedAddress returns the address other nodes use to reach this node.
+func advertisedAddress(cfg *viper.Viper) string {
+	addr := cfg.GetString(config.CfgNodeAdvertise)
+	if addr == "" {
+		addr = cfg.GetString(config.CfgNodeServer)
+	}
+	return addr
+}
+
+// sortPeers sorts peers from the most to the least recently seen.
+func sortPeers(peers []*consensus.Peer) {
+	sort.Slice(peers, func(i, j int) bool {
+		if peers[i].LastSeen != peers[j].LastSeen {
+			return peers[i].LastSeen > peers[j].LastSeen
+		}
+		return peers[i].Address < peers[j].Address
+	})
 }
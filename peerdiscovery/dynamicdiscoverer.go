@@ -0,0 +1,352 @@
+package peerdiscovery
+
+import (
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/keyvaluestore"
+	"github.com/msaldanha/realChain/logging"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"sync"
+	"time"
+)
+
+const (
+	peerExchangeTimeout = 5 * time.Second
+	// maxLearnedPeers is the most new peers taken from a single peer exchange.
+	maxLearnedPeers = 16
+	// maxQueuedPeers is the most peers that contacted this node waiting to be pinged.
+	maxQueuedPeers = 16
+)
+
+// DynamicDiscoverer starts from the configured seed peers and learns new peers by asking the active ones for
+// their peers. Known peers are kept in a persisted peer table, along with the last time this node saw them, and
+// the most recently seen ones are selected as the active peers, which the PeerManager connects to. The peers are
+// asked through the connections of the PeerManager, set with SetPeerClients, so they know who asks. The peers learned from other nodes, or that contact
+// this node, are only added once they answer a ping, and the times other nodes saw their peers are not trusted.
+// The peers that contact this node are queued and pinged in the background, never while they wait.
+type DynamicDiscoverer struct {
+	table    keyvaluestore.Storer
+	self     string
+	seeds    map[string]bool
+	maxPeers int
+	expiry   time.Duration
+	dialOpt  grpc.DialOption
+	mtx      sync.Mutex
+	known    map[string]*consensus.Peer
+	active   map[string]bool
+	clients  PeerClients
+	queued   map[string]bool
+	wake     chan struct{}
+	logger   *log.Entry
+}
+
+func NewDynamicDiscoverer(cfg *viper.Viper, table keyvaluestore.Storer, dialOpt grpc.DialOption) *DynamicDiscoverer {
+	seeds := make(map[string]bool)
+	for _, v := range cfg.GetStringSlice(config.CfgPeers) {
+		seeds[v] = true
+	}
+
+	return &DynamicDiscoverer{
+		table:    table,
+		self:     advertisedAddress(cfg),
+		seeds:    seeds,
+		maxPeers: cfg.GetInt(config.CfgDiscoveryMaxPeers),
+		expiry:   cfg.GetDuration(config.CfgDiscoveryExpiry),
+		dialOpt:  dialOpt,
+		known:    make(map[string]*consensus.Peer),
+		active:   make(map[string]bool),
+		queued:   make(map[string]bool),
+		wake:     make(chan struct{}, 1),
+		logger:   logging.Component(log.StandardLogger(), "discovery"),
+	}
+}
+
+// Init loads the peer table, adds the seed peers to it and selects the active peers.
+func (d *DynamicDiscoverer) Init() error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	err := d.table.ForEach(func(key string, value []byte) error {
+		peer := consensus.NewPeerFromBytes(value)
+		if peer.Address != "" {
+			d.known[peer.Address] = peer
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for seed := range d.seeds {
+		if _, ok := d.known[seed]; !ok {
+			d.known[seed] = &consensus.Peer{Address: seed}
+		}
+	}
+
+	d.selectActive()
+	return nil
+}
+
+// Run refreshes the peer table every interval, and pings the peers that contacted this node as they are
+// queued, until ctx is done.
+func (d *DynamicDiscoverer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+			d.addQueued(ctx)
+		case <-ticker.C:
+			d.Refresh(ctx)
+		}
+	}
+}
+
+// Refresh pings the queued peers, asks every active peer for its known peers, merges them into the peer table,
+// drops the expired peers and selects the active peers again.
+func (d *DynamicDiscoverer) Refresh(ctx context.Context) {
+	d.addQueued(ctx)
+
+	d.mtx.Lock()
+	clients := d.clients
+	d.mtx.Unlock()
+	active := make(map[string]consensus.ConsensusClient)
+	if clients != nil {
+		active = clients.PeerClients()
+	}
+
+	for addr, client := range active {
+		reqCtx, cancel := context.WithTimeout(ctx, peerExchangeTimeout)
+		result, err := client.GetPeers(reqCtx, &consensus.GetPeersRequest{From: d.self})
+		cancel()
+		if err != nil {
+			d.logger.WithField(logging.PeerField, addr).Warnf("Peer exchange failed: %s", err)
+			continue
+		}
+		d.mtx.Lock()
+		d.merge(&consensus.Peer{Address: addr, LastSeen: time.Now().UnixNano()})
+		learned := d.unknown(result.Peers)
+		d.mtx.Unlock()
+
+		for _, peer := range learned {
+			d.addIfReachable(ctx, peer)
+		}
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.prune()
+	d.selectActive()
+	d.persist()
+}
+
+// Peers returns the clients of the active peers that the peer clients set with SetPeerClients are connected to.
+func (d *DynamicDiscoverer) Peers() ([]consensus.ConsensusClient, error) {
+	d.mtx.Lock()
+	clients := d.clients
+	d.mtx.Unlock()
+	connected := make(map[string]consensus.ConsensusClient)
+	if clients != nil {
+		connected = clients.PeerClients()
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	peers := make([]consensus.ConsensusClient, 0, len(d.active))
+	for addr, client := range connected {
+		if d.active[addr] {
+			peers = append(peers, client)
+		}
+	}
+	return peers, nil
+}
+
+// ActivePeers returns the peers selected to connect to, up to the maximum number of peers, from the most to the
+// least recently seen.
+func (d *DynamicDiscoverer) ActivePeers() ([]*consensus.Peer, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	peers := make([]*consensus.Peer, 0, len(d.active))
+	for _, peer := range d.sortedKnown() {
+		if d.active[peer.Address] {
+			peers = append(peers, peer)
+		}
+	}
+	return peers, nil
+}
+
+func (d *DynamicDiscoverer) KnownPeers() ([]*consensus.Peer, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	return d.sortedKnown(), nil
+}
+
+// AddPeer queues a peer that contacted this node to be added to the peer table, if it answers a ping at
+// address. The peers already known are not pinged again, and the ones beyond maxQueuedPeers are dropped.
+func (d *DynamicDiscoverer) AddPeer(address string) error {
+	if address == "" || address == d.self {
+		return nil
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if _, ok := d.known[address]; ok || len(d.queued) >= maxQueuedPeers {
+		return nil
+	}
+	d.queued[address] = true
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// SetPeerClients sets the connections the active peers are asked for their peers through.
+func (d *DynamicDiscoverer) SetPeerClients(clients PeerClients) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.clients = clients
+}
+
+// SetLogger sets the logger of the discoverer.
+func (d *DynamicDiscoverer) SetLogger(logger log.FieldLogger) {
+	d.logger = logging.Component(logger, "discovery")
+}
+
+// Report does nothing, as the health of the peers is tracked by the PeerManager.
+func (d *DynamicDiscoverer) Report(peer consensus.ConsensusClient, reason string) {
+}
+
+// PeerIdentity returns nil, as the peers are authenticated by the PeerManager.
+func (d *DynamicDiscoverer) PeerIdentity(peer consensus.ConsensusClient) *consensus.NodeInfo {
+	return nil
+}
+
+// unknown returns the addresses of up to maxLearnedPeers of peers that are not in the peer table.
+func (d *DynamicDiscoverer) unknown(peers []*consensus.Peer) []string {
+	addresses := make([]string, 0)
+	for _, peer := range peers {
+		if len(addresses) >= maxLearnedPeers {
+			break
+		}
+		if _, ok := d.known[peer.Address]; ok || peer.Address == "" || peer.Address == d.self {
+			continue
+		}
+		addresses = append(addresses, peer.Address)
+	}
+	return addresses
+}
+
+// addQueued pings the queued peers and adds the ones that answer.
+func (d *DynamicDiscoverer) addQueued(ctx context.Context) {
+	d.mtx.Lock()
+	queued := d.queued
+	d.queued = make(map[string]bool)
+	d.mtx.Unlock()
+
+	for address := range queued {
+		err := d.addIfReachable(ctx, address)
+		if err != nil {
+			d.logger.WithField(logging.PeerField, address).Warnf("Failed to save peer: %s", err)
+		}
+	}
+}
+
+// addIfReachable pings the peer at address and, if it answers, adds it to the peer table as seen now.
+func (d *DynamicDiscoverer) addIfReachable(ctx context.Context, address string) error {
+	logger := d.logger.WithField(logging.PeerField, address)
+	conn, err := grpc.Dial(address, d.dialOpt)
+	if err != nil {
+		logger.Debugf("Failed to dial peer: %s", err)
+		return nil
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, peerExchangeTimeout)
+	_, err = consensus.NewConsensusClient(conn).Ping(reqCtx, &consensus.PingRequest{Timestamp: time.Now().UnixNano()})
+	cancel()
+	conn.Close()
+	if err != nil {
+		logger.Debugf("Peer not added, ping failed: %s", err)
+		return nil
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if !d.merge(&consensus.Peer{Address: address, LastSeen: time.Now().UnixNano()}) {
+		return nil
+	}
+	return d.table.Put(address, d.known[address].ToBytes())
+}
+
+// merge adds peer to the peer table or updates its last seen time. It returns true if the table changed.
+func (d *DynamicDiscoverer) merge(peer *consensus.Peer) bool {
+	if peer.Address == "" || peer.Address == d.self {
+		return false
+	}
+
+	lastSeen := peer.LastSeen
+	if now := time.Now().UnixNano(); lastSeen > now {
+		lastSeen = now
+	}
+
+	known, ok := d.known[peer.Address]
+	if !ok {
+		d.known[peer.Address] = &consensus.Peer{Address: peer.Address, LastSeen: lastSeen}
+		return true
+	}
+	if lastSeen > known.LastSeen {
+		known.LastSeen = lastSeen
+		return true
+	}
+	return false
+}
+
+func (d *DynamicDiscoverer) prune() {
+	if d.expiry <= 0 {
+		return
+	}
+	limit := time.Now().Add(-d.expiry).UnixNano()
+	for addr, peer := range d.known {
+		if !d.seeds[addr] && peer.LastSeen < limit {
+			delete(d.known, addr)
+			d.table.Delete(addr)
+		}
+	}
+}
+
+// selectActive selects the most recently seen peers as the active ones, up to the maximum number of peers.
+func (d *DynamicDiscoverer) selectActive() {
+	d.active = make(map[string]bool)
+	for _, peer := range d.sortedKnown() {
+		if d.maxPeers > 0 && len(d.active) >= d.maxPeers {
+			break
+		}
+		d.active[peer.Address] = true
+	}
+}
+
+func (d *DynamicDiscoverer) persist() {
+	for _, peer := range d.known {
+		err := d.table.Put(peer.Address, peer.ToBytes())
+		if err != nil {
+			d.logger.WithField(logging.PeerField, peer.Address).Warnf("Failed to save peer: %s", err)
+		}
+	}
+}
+
+func (d *DynamicDiscoverer) sortedKnown() []*consensus.Peer {
+	peers := make([]*consensus.Peer, 0, len(d.known))
+	for _, peer := range d.known {
+		peers = append(peers, &consensus.Peer{Address: peer.Address, LastSeen: peer.LastSeen})
+	}
+	sortPeers(peers)
+	return peers
+}
@@ -0,0 +1,250 @@
+package peerdiscovery_test
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/msaldanha/realChain/address"
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/keyvaluestore"
+	"github.com/msaldanha/realChain/peerdiscovery"
+	"github.com/msaldanha/realChain/server"
+	"github.com/msaldanha/realChain/tests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"net"
+	"time"
+)
+
+var _ = Describe("DynamicDiscoverer", func() {
+
+	const self = "127.0.0.1:1"
+
+	var mockCtrl *gomock.Controller
+	var table *keyvaluestore.MemoryKeyValueStore
+	var cfg *viper.Viper
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		table = keyvaluestore.NewMemoryKeyValueStore()
+		cfg = viper.New()
+		cfg.Set(config.CfgNodeAdvertise, self)
+		cfg.Set(config.CfgDiscoveryMaxPeers, 8)
+		cfg.Set(config.CfgDiscoveryExpiry, "1h")
+	})
+
+	It("Should learn new peers from its seed peers", func() {
+		defer mockCtrl.Finish()
+
+		peerAddr := startPeer(mockCtrl, nil)
+		seedAddr := startPeer(mockCtrl, []*consensus.Peer{{Address: peerAddr, LastSeen: time.Now().UnixNano()}})
+		cfg.Set(config.CfgPeers, []string{seedAddr})
+
+		dis := peerdiscovery.NewDynamicDiscoverer(cfg, table, grpc.WithInsecure())
+		Expect(dis.Init()).To(BeNil())
+		pm := managePeers(dis, cfg)
+
+		peers, err := dis.Peers()
+		Expect(err).To(BeNil())
+		Expect(len(peers)).To(Equal(1))
+
+		dis.Refresh(context.Background())
+		Expect(pm.Check(context.Background())).To(BeNil())
+
+		peers, err = dis.Peers()
+		Expect(err).To(BeNil())
+		Expect(len(peers)).To(Equal(2))
+
+		known, err := dis.KnownPeers()
+		Expect(err).To(BeNil())
+		Expect(addresses(known)).To(ConsistOf(seedAddr, peerAddr))
+	})
+
+	It("Should learn only the peers that answer, as seen by itself", func() {
+		defer mockCtrl.Finish()
+
+		peerAddr := startPeer(mockCtrl, nil)
+		seedAddr := startPeer(mockCtrl, []*consensus.Peer{{Address: peerAddr, LastSeen: 1},
+			{Address: "127.0.0.1:2", LastSeen: time.Now().UnixNano()}})
+		cfg.Set(config.CfgPeers, []string{seedAddr})
+
+		dis := peerdiscovery.NewDynamicDiscoverer(cfg, table, grpc.WithInsecure())
+		Expect(dis.Init()).To(BeNil())
+		managePeers(dis, cfg)
+
+		before := time.Now().UnixNano()
+		dis.Refresh(context.Background())
+
+		known, err := dis.KnownPeers()
+		Expect(err).To(BeNil())
+		Expect(addresses(known)).To(ConsistOf(seedAddr, peerAddr))
+		for _, peer := range known {
+			Expect(peer.LastSeen).To(BeNumerically(">=", before))
+		}
+	})
+
+	It("Should persist the peer table", func() {
+		defer mockCtrl.Finish()
+
+		peerAddr := startPeer(mockCtrl, nil)
+		seedAddr := startPeer(mockCtrl, []*consensus.Peer{{Address: peerAddr, LastSeen: time.Now().UnixNano()}})
+		cfg.Set(config.CfgPeers, []string{seedAddr})
+
+		dis := peerdiscovery.NewDynamicDiscoverer(cfg, table, grpc.WithInsecure())
+		Expect(dis.Init()).To(BeNil())
+		managePeers(dis, cfg)
+		dis.Refresh(context.Background())
+
+		cfg.Set(config.CfgPeers, []string{})
+		dis = peerdiscovery.NewDynamicDiscoverer(cfg, table, grpc.WithInsecure())
+		Expect(dis.Init()).To(BeNil())
+
+		known, err := dis.KnownPeers()
+		Expect(err).To(BeNil())
+		Expect(addresses(known)).To(ConsistOf(seedAddr, peerAddr))
+	})
+
+	It("Should keep only the most recently seen peers active", func() {
+		defer mockCtrl.Finish()
+
+		now := time.Now().UnixNano()
+		table.Put("127.0.0.1:2", (&consensus.Peer{Address: "127.0.0.1:2", LastSeen: now - 10}).ToBytes())
+		table.Put("127.0.0.1:3", (&consensus.Peer{Address: "127.0.0.1:3", LastSeen: now}).ToBytes())
+		cfg.Set(config.CfgDiscoveryMaxPeers, 1)
+
+		dis := peerdiscovery.NewDynamicDiscoverer(cfg, table, grpc.WithInsecure())
+		Expect(dis.Init()).To(BeNil())
+
+		active, err := dis.ActivePeers()
+		Expect(err).To(BeNil())
+		Expect(addresses(active)).To(Equal([]string{"127.0.0.1:3"}))
+
+		known, err := dis.KnownPeers()
+		Expect(err).To(BeNil())
+		Expect(addresses(known)).To(Equal([]string{"127.0.0.1:3", "127.0.0.1:2"}))
+	})
+
+	It("Should have the peer manager connect only to the active peers", func() {
+		defer mockCtrl.Finish()
+
+		oldAddr := startPeer(mockCtrl, nil)
+		newAddr := startPeer(mockCtrl, nil)
+		now := time.Now().UnixNano()
+		table.Put(oldAddr, (&consensus.Peer{Address: oldAddr, LastSeen: now - 10}).ToBytes())
+		table.Put(newAddr, (&consensus.Peer{Address: newAddr, LastSeen: now}).ToBytes())
+		cfg.Set(config.CfgDiscoveryMaxPeers, 1)
+
+		dis := peerdiscovery.NewDynamicDiscoverer(cfg, table, grpc.WithInsecure())
+		Expect(dis.Init()).To(BeNil())
+		pm := managePeers(dis, cfg)
+
+		stats := pm.Stats()
+		Expect(len(stats)).To(Equal(1))
+		Expect(stats[0].Address).To(Equal(newAddr))
+		Expect(stats[0].Healthy).To(BeTrue())
+		peers, err := pm.Peers()
+		Expect(err).To(BeNil())
+		Expect(len(peers)).To(Equal(1))
+	})
+
+	It("Should drop expired peers but keep the seed peers", func() {
+		defer mockCtrl.Finish()
+
+		old := time.Now().Add(-2 * time.Hour).UnixNano()
+		table.Put("127.0.0.1:2", (&consensus.Peer{Address: "127.0.0.1:2", LastSeen: old}).ToBytes())
+		table.Put("127.0.0.1:3", (&consensus.Peer{Address: "127.0.0.1:3", LastSeen: old}).ToBytes())
+		cfg.Set(config.CfgPeers, []string{"127.0.0.1:3"})
+
+		dis := peerdiscovery.NewDynamicDiscoverer(cfg, table, grpc.WithInsecure())
+		Expect(dis.Init()).To(BeNil())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		dis.Refresh(ctx)
+
+		known, err := dis.KnownPeers()
+		Expect(err).To(BeNil())
+		Expect(addresses(known)).To(Equal([]string{"127.0.0.1:3"}))
+		_, found, _ := table.Get("127.0.0.1:2")
+		Expect(found).To(BeFalse())
+	})
+
+	It("Should add peers that contacted the node, except itself", func() {
+		defer mockCtrl.Finish()
+
+		peerAddr := startPeer(mockCtrl, nil)
+
+		dis := peerdiscovery.NewDynamicDiscoverer(cfg, table, grpc.WithInsecure())
+		Expect(dis.Init()).To(BeNil())
+
+		Expect(dis.AddPeer(peerAddr)).To(BeNil())
+		Expect(dis.AddPeer(self)).To(BeNil())
+		known, err := dis.KnownPeers()
+		Expect(err).To(BeNil())
+		Expect(known).To(BeEmpty())
+
+		dis.Refresh(context.Background())
+
+		known, err = dis.KnownPeers()
+		Expect(err).To(BeNil())
+		Expect(addresses(known)).To(Equal([]string{peerAddr}))
+		Expect(known[0].LastSeen).To(BeNumerically(">", 0))
+		_, found, _ := table.Get(peerAddr)
+		Expect(found).To(BeTrue())
+	})
+
+	It("Should NOT add peers that contacted the node but do not answer", func() {
+		defer mockCtrl.Finish()
+
+		dis := peerdiscovery.NewDynamicDiscoverer(cfg, table, grpc.WithInsecure())
+		Expect(dis.Init()).To(BeNil())
+
+		Expect(dis.AddPeer("127.0.0.1:2")).To(BeNil())
+		dis.Refresh(context.Background())
+
+		known, err := dis.KnownPeers()
+		Expect(err).To(BeNil())
+		Expect(known).To(BeEmpty())
+		_, found, _ := table.Get("127.0.0.1:2")
+		Expect(found).To(BeFalse())
+	})
+})
+
+// managePeers connects a peer manager to the active peers of dis and exchanges the peers through it.
+func managePeers(dis *peerdiscovery.DynamicDiscoverer, cfg *viper.Viper) *peerdiscovery.PeerManager {
+	pm := peerdiscovery.NewPeerManager(dis, newIdentity("test"), grpc.WithInsecure(), cfg)
+	Expect(pm.Init()).To(BeNil())
+	dis.SetPeerClients(pm)
+	return pm
+}
+
+// startPeer starts a node server whose discoverer knows the given peers and returns its address.
+func startPeer(mockCtrl *gomock.Controller, known []*consensus.Peer) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(BeNil())
+
+	dis := tests.NewMockDiscoverer(mockCtrl)
+	dis.EXPECT().AddPeer(gomock.Any()).AnyTimes()
+	dis.EXPECT().KnownPeers().Return(known, nil).AnyTimes()
+
+	srv := server.New(nil, nil, newConsensus("test"), dis, lis)
+	go srv.Run()
+
+	return lis.Addr().String()
+}
+
+func newConsensus(chainId string) consensus.Consensus {
+	addr, err := address.NewAddressWithKeys()
+	Expect(err).To(BeNil())
+	return consensus.NewConsensus(nil, addr, chainId)
+}
+
+func addresses(peers []*consensus.Peer) []string {
+	addrs := make([]string, 0)
+	for _, peer := range peers {
+		addrs = append(addrs, peer.Address)
+	}
+	return addrs
+}
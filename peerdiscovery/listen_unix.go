@@ -0,0 +1,30 @@
+//go:build !windows
+// +build !windows
+
+package peerdiscovery
+
+import (
+	"golang.org/x/net/context"
+	"net"
+	"syscall"
+)
+
+// listenUDP listens for announcements at addr with SO_REUSEADDR set, so the nodes running on the same host can
+// all listen on the announcement port and all get the broadcast announcements.
+func listenUDP(addr string) (*net.UDPConn, error) {
+	lc := net.ListenConfig{Control: func(network, address string, c syscall.RawConn) error {
+		var sockErr error
+		err := c.Control(func(fd uintptr) {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1)
+		})
+		if err != nil {
+			return err
+		}
+		return sockErr
+	}}
+	conn, err := lc.ListenPacket(context.Background(), "udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return conn.(*net.UDPConn), nil
+}
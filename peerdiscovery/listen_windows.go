@@ -0,0 +1,15 @@
+package peerdiscovery
+
+import (
+	"net"
+)
+
+// listenUDP listens for announcements at addr. Windows lets a socket with SO_REUSEADDR take over a port in use,
+// so the option is not set and a single node per host can listen on the announcement port.
+func listenUDP(addr string) (*net.UDPConn, error) {
+	listenAddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return net.ListenUDP("udp", listenAddr)
+}
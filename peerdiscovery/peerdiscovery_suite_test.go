@@ -0,0 +1,13 @@
+package peerdiscovery_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestPeerdiscovery(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Peerdiscovery Suite")
+}
@@ -0,0 +1,474 @@
+package peerdiscovery
+
+import (
+	"bytes"
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/errcodes"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/msaldanha/realChain/logging"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	ErrVoteNotFromPeer = errors.Error("vote not signed by the peer node key")
+)
+
+const (
+	maxScore          = 100
+	bannedScore       = 0
+	unbannedScore     = maxScore / 2
+	successScore      = 1
+	slowScore         = -1
+	errorScore        = -5
+	invalidVoteScore  = -25
+	slowLatency       = time.Second
+	pingTimeout       = 5 * time.Second
+	maxBackoff        = 5 * time.Minute
+	reconnectFailures = 3
+)
+
+// PeerManager keeps the peers found by another discoverer healthy. It runs a handshake with every peer to
+// authenticate it, then pings it periodically, backs off and reconnects to the ones that stop answering, and
+// scores each peer from the latency and errors of the calls made to it and from the invalid votes it sends.
+// Peers whose score drops to zero are banned for a while. Only the healthy peers are returned by Peers.
+type PeerManager struct {
+	source       Discoverer
+	id           *consensus.Identity
+	pingInterval time.Duration
+	banDuration  time.Duration
+	dialOpt      grpc.DialOption
+	mtx          sync.Mutex
+	peers        map[string]*managedPeer
+	logger       *log.Entry
+}
+
+// PeerStats holds the health of a managed peer.
+type PeerStats struct {
+	Address     string
+	Info        *consensus.NodeInfo
+	Healthy     bool
+	Score       int
+	Latency     time.Duration
+	Failures    int
+	BannedUntil time.Time
+}
+
+type managedPeer struct {
+	address     string
+	conn        *grpc.ClientConn
+	client      consensus.ConsensusClient
+	info        *consensus.NodeInfo
+	session     string
+	healthy     bool
+	score       int
+	latency     time.Duration
+	failures    int
+	retryAt     time.Time
+	bannedUntil time.Time
+}
+
+// NewPeerManager creates a peer manager for the peers known by source, which must already be initialized.
+// The node proves its identity to the peers with id and connects to them with dialOpt.
+func NewPeerManager(source Discoverer, id *consensus.Identity, dialOpt grpc.DialOption, cfg *viper.Viper) *PeerManager {
+	return &PeerManager{
+		source:       source,
+		id:           id,
+		pingInterval: cfg.GetDuration(config.CfgDiscoveryPing),
+		banDuration:  cfg.GetDuration(config.CfgDiscoveryBanTime),
+		dialOpt:      dialOpt,
+		peers:        make(map[string]*managedPeer),
+		logger:       logging.Component(log.StandardLogger(), "peers"),
+	}
+}
+
+// SetLogger sets the logger of the peer manager.
+func (m *PeerManager) SetLogger(logger log.FieldLogger) {
+	m.logger = logging.Component(logger, "peers")
+}
+
+// Init connects to the known peers and pings them, so the healthy peers are available right away.
+func (m *PeerManager) Init() error {
+	return m.Check(context.Background())
+}
+
+// Run checks the peers every ping interval until ctx is done, then disconnects from them.
+func (m *PeerManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.disconnect()
+			return
+		case <-ticker.C:
+			err := m.Check(ctx)
+			if err != nil {
+				m.logger.Warnf("Peer check failed: %s", err)
+			}
+		}
+	}
+}
+
+// Check updates the managed peers from the source, its active peers if it selects them or else all it knows,
+// and pings the ones that are due, running a handshake first with the ones that have no session.
+func (m *PeerManager) Check(ctx context.Context) error {
+	var known []*consensus.Peer
+	var err error
+	if source, ok := m.source.(ActiveSource); ok {
+		known, err = source.ActivePeers()
+	} else {
+		known, err = m.source.KnownPeers()
+	}
+	if err != nil {
+		return err
+	}
+
+	m.mtx.Lock()
+	m.update(known)
+	due := m.due(time.Now())
+	m.mtx.Unlock()
+
+	wg := sync.WaitGroup{}
+	for _, peer := range due {
+		wg.Add(1)
+		go func(peer *managedPeer, client consensus.ConsensusClient) {
+			defer wg.Done()
+			pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+			defer cancel()
+			err := m.handshake(pingCtx, peer, client)
+			if err == nil {
+				_, err = client.Ping(pingCtx, &consensus.PingRequest{Timestamp: time.Now().UnixNano()})
+			}
+			m.pinged(peer, err)
+		}(peer, peer.client)
+	}
+	wg.Wait()
+	return nil
+}
+
+// Peers returns the healthy peers, from the best to the worst scored.
+func (m *PeerManager) Peers() ([]consensus.ConsensusClient, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	healthy := make([]*managedPeer, 0, len(m.peers))
+	for _, peer := range m.peers {
+		if peer.healthy {
+			healthy = append(healthy, peer)
+		}
+	}
+	sort.Slice(healthy, func(i, j int) bool {
+		if healthy[i].score != healthy[j].score {
+			return healthy[i].score > healthy[j].score
+		}
+		return healthy[i].address < healthy[j].address
+	})
+
+	peers := make([]consensus.ConsensusClient, 0, len(healthy))
+	for _, peer := range healthy {
+		peers = append(peers, peer.client)
+	}
+	return peers, nil
+}
+
+// PeerClients returns the clients of the healthy peers by their address.
+func (m *PeerManager) PeerClients() map[string]consensus.ConsensusClient {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	clients := make(map[string]consensus.ConsensusClient)
+	for addr, peer := range m.peers {
+		if peer.healthy {
+			clients[addr] = peer.client
+		}
+	}
+	return clients
+}
+
+func (m *PeerManager) KnownPeers() ([]*consensus.Peer, error) {
+	return m.source.KnownPeers()
+}
+
+func (m *PeerManager) AddPeer(address string) error {
+	return m.source.AddPeer(address)
+}
+
+// IsVoter tells if pubKey is the node key a managed peer proved in its handshake. Banned peers are not voters.
+func (m *PeerManager) IsVoter(pubKey []byte) bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for _, peer := range m.peers {
+		if peer.info != nil && peer.bannedUntil.IsZero() && bytes.Equal(peer.info.PubKey, pubKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// PeerIdentity returns the identity the managed peer proved in its handshake, nil if it has none.
+func (m *PeerManager) PeerIdentity(peer consensus.ConsensusClient) *consensus.NodeInfo {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for _, p := range m.peers {
+		if p.client == peer {
+			return p.info
+		}
+	}
+	return nil
+}
+
+// Report lowers the score of a peer that sent an invalid vote.
+func (m *PeerManager) Report(peer consensus.ConsensusClient, reason string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for _, p := range m.peers {
+		if p.client == peer {
+			m.misbehaved(p, reason)
+			return
+		}
+	}
+}
+
+func (m *PeerManager) misbehaved(peer *managedPeer, reason string) {
+	m.peerLogger(peer).Warnf("Peer misbehaved: %s", reason)
+	m.adjustScore(peer, invalidVoteScore)
+}
+
+// Stats returns the health of every managed peer.
+func (m *PeerManager) Stats() []PeerStats {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	stats := make([]PeerStats, 0, len(m.peers))
+	for _, peer := range m.peers {
+		stats = append(stats, PeerStats{
+			Address:     peer.address,
+			Info:        peer.info,
+			Healthy:     peer.healthy,
+			Score:       peer.score,
+			Latency:     peer.latency,
+			Failures:    peer.failures,
+			BannedUntil: peer.bannedUntil,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Address < stats[j].Address })
+	return stats
+}
+
+// update connects to the new peers and disconnects from the ones the source does not know anymore.
+func (m *PeerManager) update(known []*consensus.Peer) {
+	addrs := make(map[string]bool)
+	for _, peer := range known {
+		addrs[peer.Address] = true
+		if _, ok := m.peers[peer.Address]; ok {
+			continue
+		}
+		managed := &managedPeer{address: peer.Address, score: maxScore}
+		if m.connect(managed) {
+			m.peers[peer.Address] = managed
+		}
+	}
+
+	for addr, peer := range m.peers {
+		if !addrs[addr] {
+			peer.conn.Close()
+			delete(m.peers, addr)
+		}
+	}
+}
+
+// disconnect closes the connections to all the peers.
+func (m *PeerManager) disconnect() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for addr, peer := range m.peers {
+		peer.conn.Close()
+		delete(m.peers, addr)
+	}
+}
+
+func (m *PeerManager) handshake(ctx context.Context, peer *managedPeer, client consensus.ConsensusClient) error {
+	m.mtx.Lock()
+	session := peer.session
+	m.mtx.Unlock()
+	if session != "" {
+		return nil
+	}
+
+	info, session, err := m.id.Handshake(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if peer.info != nil && !bytes.Equal(peer.info.PubKey, info.PubKey) {
+		m.peerLogger(peer).Warn("Peer changed its node key")
+	}
+	peer.info = info
+	peer.session = session
+	return nil
+}
+
+func (m *PeerManager) due(now time.Time) []*managedPeer {
+	due := make([]*managedPeer, 0)
+	for _, peer := range m.peers {
+		if !peer.bannedUntil.IsZero() {
+			if now.Before(peer.bannedUntil) {
+				continue
+			}
+			m.peerLogger(peer).Info("Peer is no longer banned")
+			peer.bannedUntil = time.Time{}
+			peer.score = unbannedScore
+		}
+		if now.Before(peer.retryAt) {
+			continue
+		}
+		due = append(due, peer)
+	}
+	return due
+}
+
+// pinged updates the peer health with the ping result. A peer that fails to answer is retried with an
+// exponential backoff, and after a few failures its connection is dropped and dialed again.
+func (m *PeerManager) pinged(peer *managedPeer, err error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if err == nil {
+		if !peer.healthy && peer.bannedUntil.IsZero() {
+			m.peerLogger(peer).Info("Peer is healthy")
+		}
+		peer.failures = 0
+		peer.retryAt = time.Time{}
+		peer.healthy = peer.bannedUntil.IsZero()
+		return
+	}
+
+	if peer.healthy {
+		m.peerLogger(peer).Warnf("Peer is unhealthy: %s", err)
+	}
+	peer.healthy = false
+	peer.failures++
+
+	backoff := m.pingInterval << uint(peer.failures-1)
+	if backoff > maxBackoff || backoff <= 0 {
+		backoff = maxBackoff
+	}
+	peer.retryAt = time.Now().Add(backoff)
+
+	if peer.failures%reconnectFailures == 0 {
+		peer.conn.Close()
+		peer.session = ""
+		m.connect(peer)
+	}
+}
+
+// connect dials the peer. Every call made through the connection updates the peer score.
+func (m *PeerManager) connect(peer *managedPeer) bool {
+	conn, err := grpc.Dial(peer.address, m.dialOpt, grpc.WithUnaryInterceptor(m.interceptor(peer)))
+	if err != nil {
+		m.peerLogger(peer).Warnf("Failed to dial peer: %s", err)
+		return false
+	}
+	peer.conn = conn
+	peer.client = consensus.NewConsensusClient(conn)
+	return true
+}
+
+// interceptor binds the calls made through the peer connection to the peer identity: it adds the handshake
+// session and the request ID to every call and rejects the votes not signed by the peer node key. Catalogued
+// errors returned by the peer are converted back to the errors.
+func (m *PeerManager) interceptor(peer *managedPeer) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		m.mtx.Lock()
+		session, info := peer.session, peer.info
+		m.mtx.Unlock()
+
+		if session != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, consensus.SessionKey, session)
+		}
+		ctx = logging.AppendToOutgoingContext(ctx)
+
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		m.called(peer, time.Since(start), err)
+		if err != nil {
+			logging.FromContext(ctx, m.peerLogger(peer)).WithField(logging.MethodField, method).
+				Debugf("Peer call failed: %s", err)
+			return errcodes.FromStatus(err)
+		}
+
+		if result, ok := reply.(*consensus.VoteResult); ok && info != nil {
+			if result.Vote == nil || !bytes.Equal(result.Vote.PubKey, info.PubKey) {
+				m.mtx.Lock()
+				m.misbehaved(peer, ErrVoteNotFromPeer.Error())
+				m.mtx.Unlock()
+				return ErrVoteNotFromPeer
+			}
+		}
+		return nil
+	}
+}
+
+func (m *PeerManager) called(peer *managedPeer, latency time.Duration, err error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if err != nil {
+		if status.Code(err) == codes.Unauthenticated {
+			peer.session = ""
+		}
+		m.adjustScore(peer, errorScore)
+		return
+	}
+
+	if peer.latency == 0 {
+		peer.latency = latency
+	} else {
+		peer.latency = (peer.latency*3 + latency) / 4
+	}
+
+	if peer.latency > slowLatency {
+		m.adjustScore(peer, slowScore)
+	} else {
+		m.adjustScore(peer, successScore)
+	}
+}
+
+func (m *PeerManager) peerLogger(peer *managedPeer) *log.Entry {
+	return m.logger.WithField(logging.PeerField, peer.address)
+}
+
+func (m *PeerManager) adjustScore(peer *managedPeer, delta int) {
+	if !peer.bannedUntil.IsZero() {
+		return
+	}
+
+	peer.score += delta
+	if peer.score > maxScore {
+		peer.score = maxScore
+	}
+	if peer.score <= bannedScore {
+		m.peerLogger(peer).Warnf("Banning peer for %s", m.banDuration)
+		peer.score = bannedScore
+		peer.healthy = false
+		peer.bannedUntil = time.Now().Add(m.banDuration)
+	}
+}
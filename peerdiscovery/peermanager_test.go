@@ -0,0 +1,265 @@
+package peerdiscovery_test
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/keypair"
+	"github.com/msaldanha/realChain/logging"
+	"github.com/msaldanha/realChain/peerdiscovery"
+	"github.com/msaldanha/realChain/server"
+	"github.com/msaldanha/realChain/tests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net"
+	"time"
+)
+
+var _ = Describe("PeerManager", func() {
+
+	var mockCtrl *gomock.Controller
+	var source *tests.MockDiscoverer
+	var cfg *viper.Viper
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		source = tests.NewMockDiscoverer(mockCtrl)
+		cfg = viper.New()
+		cfg.Set(config.CfgDiscoveryPing, "20ms")
+		cfg.Set(config.CfgDiscoveryBanTime, "1h")
+	})
+
+	It("Should only return the healthy peers", func() {
+		defer mockCtrl.Finish()
+
+		liveAddr := startPeer(mockCtrl, nil)
+		deadAddr := freeTcpAddress()
+		source.EXPECT().KnownPeers().Return([]*consensus.Peer{{Address: liveAddr}, {Address: deadAddr}}, nil)
+
+		pm := peerdiscovery.NewPeerManager(source, newIdentity("test"), grpc.WithInsecure(), cfg)
+		Expect(pm.Init()).To(BeNil())
+
+		peers, err := pm.Peers()
+		Expect(err).To(BeNil())
+		Expect(len(peers)).To(Equal(1))
+
+		stats := pm.Stats()
+		Expect(len(stats)).To(Equal(2))
+		for _, stat := range stats {
+			Expect(stat.Healthy).To(Equal(stat.Address == liveAddr))
+		}
+	})
+
+	It("Should authenticate the peers", func() {
+		defer mockCtrl.Finish()
+
+		keys, err := keypair.New()
+		Expect(err).To(BeNil())
+		addr, con := startAuthPeer(mockCtrl, keys)
+		con.EXPECT().Vote(gomock.Any()).Return(&consensus.VoteResult{Vote: signVote(keys)}, nil)
+		source.EXPECT().KnownPeers().Return([]*consensus.Peer{{Address: addr}}, nil)
+
+		pm := peerdiscovery.NewPeerManager(source, newIdentity("test"), grpc.WithInsecure(), cfg)
+		Expect(pm.Init()).To(BeNil())
+
+		stats := pm.Stats()
+		Expect(stats[0].Healthy).To(BeTrue())
+		Expect(stats[0].Info.PubKey).To(Equal(keys.PublicKey))
+
+		peers, err := pm.Peers()
+		Expect(err).To(BeNil())
+		result, err := peers[0].Vote(context.Background(), &consensus.VoteRequest{})
+		Expect(err).To(BeNil())
+		Expect(result.Vote.PubKey).To(Equal(keys.PublicKey))
+		Expect(pm.IsVoter(keys.PublicKey)).To(BeTrue())
+		Expect(pm.IsVoter([]byte("other key"))).To(BeFalse())
+	})
+
+	It("Should send the request ID along the peer calls", func() {
+		defer mockCtrl.Finish()
+
+		keys, err := keypair.New()
+		Expect(err).To(BeNil())
+		addr, con := startAuthPeer(mockCtrl, keys)
+		con.EXPECT().Vote(gomock.Any()).Return(&consensus.VoteResult{Vote: signVote(keys)}, nil)
+		source.EXPECT().KnownPeers().Return([]*consensus.Peer{{Address: addr}}, nil)
+
+		pm := peerdiscovery.NewPeerManager(source, newIdentity("test"), grpc.WithInsecure(), cfg)
+		Expect(pm.Init()).To(BeNil())
+
+		peers, err := pm.Peers()
+		Expect(err).To(BeNil())
+		header := metadata.MD{}
+		ctx := logging.WithRequestId(context.Background(), "request-1")
+		_, err = peers[0].Vote(ctx, &consensus.VoteRequest{}, grpc.Header(&header))
+		Expect(err).To(BeNil())
+		Expect(header.Get(logging.RequestIdKey)).To(Equal([]string{"request-1"}))
+	})
+
+	It("Should reject votes not signed by the peer node key", func() {
+		defer mockCtrl.Finish()
+
+		keys, err := keypair.New()
+		Expect(err).To(BeNil())
+		otherKeys, err := keypair.New()
+		Expect(err).To(BeNil())
+		addr, con := startAuthPeer(mockCtrl, keys)
+		con.EXPECT().Vote(gomock.Any()).Return(&consensus.VoteResult{Vote: signVote(otherKeys)}, nil)
+		source.EXPECT().KnownPeers().Return([]*consensus.Peer{{Address: addr}}, nil)
+
+		pm := peerdiscovery.NewPeerManager(source, newIdentity("test"), grpc.WithInsecure(), cfg)
+		Expect(pm.Init()).To(BeNil())
+
+		peers, err := pm.Peers()
+		Expect(err).To(BeNil())
+		_, err = peers[0].Vote(context.Background(), &consensus.VoteRequest{})
+		Expect(err).To(Equal(peerdiscovery.ErrVoteNotFromPeer))
+		Expect(pm.Stats()[0].Score).To(BeNumerically("<", 100))
+	})
+
+	It("Should not use peers of other chains", func() {
+		defer mockCtrl.Finish()
+
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		srv := server.New(nil, nil, newConsensus("other"), tests.NewMockDiscoverer(mockCtrl), lis)
+		go srv.Run()
+		source.EXPECT().KnownPeers().Return([]*consensus.Peer{{Address: lis.Addr().String()}}, nil)
+
+		pm := peerdiscovery.NewPeerManager(source, newIdentity("test"), grpc.WithInsecure(), cfg)
+		Expect(pm.Init()).To(BeNil())
+
+		Expect(peerCount(pm)).To(Equal(0))
+		Expect(pm.Stats()[0].Info).To(BeNil())
+	})
+
+	It("Should not let unauthenticated peers vote", func() {
+		defer mockCtrl.Finish()
+
+		keys, err := keypair.New()
+		Expect(err).To(BeNil())
+		addr, _ := startAuthPeer(mockCtrl, keys)
+
+		conn, err := grpc.Dial(addr, grpc.WithInsecure())
+		Expect(err).To(BeNil())
+		defer conn.Close()
+
+		_, err = consensus.NewConsensusClient(conn).Vote(context.Background(), &consensus.VoteRequest{})
+		Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+	})
+
+	It("Should reconnect to peers that come back", func() {
+		defer mockCtrl.Finish()
+
+		addr := freeTcpAddress()
+		source.EXPECT().KnownPeers().Return([]*consensus.Peer{{Address: addr}}, nil).AnyTimes()
+
+		pm := peerdiscovery.NewPeerManager(source, newIdentity("test"), grpc.WithInsecure(), cfg)
+		Expect(pm.Init()).To(BeNil())
+		Expect(peerCount(pm)).To(Equal(0))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go pm.Run(ctx)
+
+		startPeerAt(mockCtrl, addr)
+
+		Eventually(func() int { return peerCount(pm) }, "5s").Should(Equal(1))
+	})
+
+	It("Should ban peers that send invalid votes", func() {
+		defer mockCtrl.Finish()
+
+		addr := startPeer(mockCtrl, nil)
+		source.EXPECT().KnownPeers().Return([]*consensus.Peer{{Address: addr}}, nil).Times(2)
+
+		pm := peerdiscovery.NewPeerManager(source, newIdentity("test"), grpc.WithInsecure(), cfg)
+		Expect(pm.Init()).To(BeNil())
+
+		peers, err := pm.Peers()
+		Expect(err).To(BeNil())
+		Expect(len(peers)).To(Equal(1))
+
+		for i := 0; i < 4; i++ {
+			pm.Report(peers[0], "invalid vote")
+		}
+		Expect(peerCount(pm)).To(Equal(0))
+
+		Expect(pm.Check(context.Background())).To(BeNil())
+		Expect(peerCount(pm)).To(Equal(0))
+		Expect(pm.Stats()[0].BannedUntil).To(BeTemporally(">", time.Now()))
+	})
+
+	It("Should drop the peers the source does not know anymore", func() {
+		defer mockCtrl.Finish()
+
+		addr := startPeer(mockCtrl, nil)
+		gomock.InOrder(
+			source.EXPECT().KnownPeers().Return([]*consensus.Peer{{Address: addr}}, nil),
+			source.EXPECT().KnownPeers().Return([]*consensus.Peer{}, nil),
+		)
+
+		pm := peerdiscovery.NewPeerManager(source, newIdentity("test"), grpc.WithInsecure(), cfg)
+		Expect(pm.Init()).To(BeNil())
+		Expect(peerCount(pm)).To(Equal(1))
+
+		Expect(pm.Check(context.Background())).To(BeNil())
+		Expect(peerCount(pm)).To(Equal(0))
+		Expect(pm.Stats()).To(BeEmpty())
+	})
+})
+
+func peerCount(pm *peerdiscovery.PeerManager) int {
+	peers, err := pm.Peers()
+	Expect(err).To(BeNil())
+	return len(peers)
+}
+
+func freeTcpAddress() string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(BeNil())
+	defer lis.Close()
+	return lis.Addr().String()
+}
+
+func startPeerAt(mockCtrl *gomock.Controller, addr string) {
+	lis, err := net.Listen("tcp", addr)
+	Expect(err).To(BeNil())
+
+	dis := tests.NewMockDiscoverer(mockCtrl)
+	srv := server.New(nil, nil, newConsensus("test"), dis, lis)
+	go srv.Run()
+}
+
+func newIdentity(chainId string) *consensus.Identity {
+	keys, err := keypair.New()
+	Expect(err).To(BeNil())
+	return consensus.NewIdentity(keys, chainId)
+}
+
+// startAuthPeer starts a node server with the given node keys whose consensus is mocked except for the handshake.
+func startAuthPeer(mockCtrl *gomock.Controller, keys *keypair.KeyPair) (string, *tests.MockConsensus) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(BeNil())
+
+	id := consensus.NewIdentity(keys, "test")
+	con := tests.NewMockConsensus(mockCtrl)
+	con.EXPECT().Handshake(gomock.Any()).DoAndReturn(id.Answer).AnyTimes()
+
+	srv := server.New(nil, nil, con, tests.NewMockDiscoverer(mockCtrl), lis)
+	go srv.Run()
+
+	return lis.Addr().String(), con
+}
+
+func signVote(keys *keypair.KeyPair) *consensus.Vote {
+	vote := &consensus.Vote{Ok: true, PubKey: keys.PublicKey}
+	Expect(vote.Sign(keys.ToEcdsaPrivateKey())).To(BeNil())
+	return vote
+}
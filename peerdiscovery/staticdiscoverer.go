@@ -9,11 +9,12 @@ import (
 
 type StaticDiscoverer struct {
 	cfg *viper.Viper
+	dialOpt grpc.DialOption
 	peers []consensus.ConsensusClient
 }
 
-func NewStaticDiscoverer(cfg *viper.Viper) *StaticDiscoverer{
-	return &StaticDiscoverer{cfg: cfg}
+func NewStaticDiscoverer(cfg *viper.Viper, dialOpt grpc.DialOption) *StaticDiscoverer{
+	return &StaticDiscoverer{cfg: cfg, dialOpt: dialOpt}
 }
 
 func (d *StaticDiscoverer) Init() error {
@@ -28,7 +29,7 @@ func (d *StaticDiscoverer) Peers() ([]consensus.ConsensusClient, error) {
 	peers := make([]consensus.ConsensusClient, 0)
 	ips := d.cfg.GetStringSlice(config.CfgPeers)
 	for _, v := range ips {
-		conn, err := grpc.Dial(v, grpc.WithInsecure())
+		conn, err := grpc.Dial(v, d.dialOpt)
 		if err == nil {
 			peer := consensus.NewConsensusClient(conn)
 			peers = append(peers, peer)
@@ -38,3 +39,25 @@ func (d *StaticDiscoverer) Peers() ([]consensus.ConsensusClient, error) {
 	d.peers = peers
 	return d.peers, nil
 }
+
+func (d *StaticDiscoverer) KnownPeers() ([]*consensus.Peer, error) {
+	peers := make([]*consensus.Peer, 0)
+	for _, v := range d.cfg.GetStringSlice(config.CfgPeers) {
+		peers = append(peers, &consensus.Peer{Address: v})
+	}
+	return peers, nil
+}
+
+// AddPeer does nothing, as the static discoverer only uses the configured peers.
+func (d *StaticDiscoverer) AddPeer(address string) error {
+	return nil
+}
+
+// Report does nothing, as the health of the peers is tracked by the PeerManager.
+func (d *StaticDiscoverer) Report(peer consensus.ConsensusClient, reason string) {
+}
+
+// PeerIdentity returns nil, as the peers are authenticated by the PeerManager.
+func (d *StaticDiscoverer) PeerIdentity(peer consensus.ConsensusClient) *consensus.NodeInfo {
+	return nil
+}
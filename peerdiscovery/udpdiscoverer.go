@@ -0,0 +1,313 @@
+package peerdiscovery
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/msaldanha/realChain/keypair"
+	"github.com/msaldanha/realChain/logging"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ErrInvalidAnnounceTarget = errors.Error("invalid announce target")
+)
+
+const (
+	maxAnnouncementSize = 1024
+	announcementMaxAge  = time.Minute
+	broadcastHost       = "255.255.255.255"
+)
+
+// UdpDiscoverer finds the peers on the local network. Each node periodically announces its gRPC endpoint, node
+// public key and chain ID over UDP to the announce targets (the broadcast address by default) and listens for the
+// announcements of the other nodes. Announcements are signed with the node key; the ones for other chains, too
+// old or with an invalid signature are ignored. Peers that stop announcing are dropped.
+type UdpDiscoverer struct {
+	keys     *keypair.KeyPair
+	listen   string
+	endpoint string
+	chainId  string
+	targets  []string
+	interval time.Duration
+	dialOpt  grpc.DialOption
+	addrs    []*net.UDPAddr
+	conn     *net.UDPConn
+	mtx      sync.Mutex
+	peers    map[string]*udpPeer
+	logger   *log.Entry
+}
+
+type udpPeer struct {
+	endpoint string
+	lastSeen int64
+	conn     *grpc.ClientConn
+	client   consensus.ConsensusClient
+}
+
+func NewUdpDiscoverer(cfg *viper.Viper, keys *keypair.KeyPair, dialOpt grpc.DialOption) *UdpDiscoverer {
+	return &UdpDiscoverer{
+		keys:     keys,
+		listen:   cfg.GetString(config.CfgUdpServer),
+		endpoint: advertisedAddress(cfg),
+		chainId:  cfg.GetString(config.CfgChainId),
+		targets:  cfg.GetStringSlice(config.CfgDiscoveryAnnounce),
+		interval: cfg.GetDuration(config.CfgDiscoveryInterval),
+		dialOpt:  dialOpt,
+		peers:    make(map[string]*udpPeer),
+		logger:   logging.Component(log.StandardLogger(), "discovery"),
+	}
+}
+
+// SetLogger sets the logger of the discoverer.
+func (d *UdpDiscoverer) SetLogger(logger log.FieldLogger) {
+	d.logger = logging.Component(logger, "discovery")
+}
+
+// Init starts listening for announcements and announces the node.
+func (d *UdpDiscoverer) Init() error {
+	addrs, err := parseTargets(d.targets, d.listen)
+	if err != nil {
+		return err
+	}
+	d.addrs = addrs
+
+	d.conn, err = listenUDP(d.listen)
+	if err != nil {
+		return err
+	}
+
+	go d.receive()
+	d.announce(d.addrs...)
+	return nil
+}
+
+// Run announces the node and drops the peers that stopped announcing every interval until ctx is done.
+func (d *UdpDiscoverer) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.conn.Close()
+			return
+		case <-ticker.C:
+			d.announce(d.addrs...)
+			d.expire()
+		}
+	}
+}
+
+func (d *UdpDiscoverer) Peers() ([]consensus.ConsensusClient, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	peers := make([]consensus.ConsensusClient, 0, len(d.peers))
+	for _, peer := range d.peers {
+		peers = append(peers, peer.client)
+	}
+	return peers, nil
+}
+
+func (d *UdpDiscoverer) KnownPeers() ([]*consensus.Peer, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	peers := make([]*consensus.Peer, 0, len(d.peers))
+	for _, peer := range d.peers {
+		peers = append(peers, &consensus.Peer{Address: peer.endpoint, LastSeen: peer.lastSeen})
+	}
+	sortPeers(peers)
+	return peers, nil
+}
+
+// AddPeer does nothing, as the UDP discoverer only uses the peers announced on the local network.
+func (d *UdpDiscoverer) AddPeer(address string) error {
+	return nil
+}
+
+// Report does nothing, as the health of the peers is tracked by the PeerManager.
+func (d *UdpDiscoverer) Report(peer consensus.ConsensusClient, reason string) {
+}
+
+// PeerIdentity returns nil, as the peers are authenticated by the PeerManager.
+func (d *UdpDiscoverer) PeerIdentity(peer consensus.ConsensusClient) *consensus.NodeInfo {
+	return nil
+}
+
+func (d *UdpDiscoverer) receive() {
+	buf := make([]byte, maxAnnouncementSize)
+	for {
+		n, from, err := d.conn.ReadFromUDP(buf)
+		if err != nil {
+			d.logger.Debugf("Stopped listening for announcements: %s", err)
+			return
+		}
+		d.handle(buf[:n], from)
+	}
+}
+
+// handle adds the peer of a valid announcement. When the peer is new the node announces itself straight back
+// to it, so the new peer does not have to wait for the next announcement round.
+func (d *UdpDiscoverer) handle(data []byte, from *net.UDPAddr) {
+	announcement, err := NewAnnouncementFromBytes(data)
+	if err != nil {
+		d.logger.WithField(logging.PeerField, from.String()).Debugf("Ignoring invalid announcement: %s", err)
+		return
+	}
+
+	if announcement.ChainId != d.chainId || bytes.Equal(announcement.PubKey, d.keys.PublicKey) {
+		return
+	}
+
+	age := time.Since(time.Unix(0, announcement.Timestamp))
+	if age > announcementMaxAge || age < -announcementMaxAge {
+		d.logger.WithField(logging.PeerField, from.String()).Debug("Ignoring stale announcement")
+		return
+	}
+
+	if !announcement.VerifySignature() {
+		d.logger.WithField(logging.PeerField, from.String()).Debug("Ignoring announcement with invalid signature")
+		return
+	}
+
+	if d.addPeer(announcement, from) {
+		d.announce(from)
+	}
+}
+
+// addPeer adds or updates the announced peer. It returns true if the peer is new.
+func (d *UdpDiscoverer) addPeer(announcement *Announcement, from *net.UDPAddr) bool {
+	endpoint := announcedEndpoint(announcement.Endpoint, from)
+	key := hex.EncodeToString(announcement.PubKey)
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	peer, ok := d.peers[key]
+	if ok && announcement.Timestamp <= peer.lastSeen {
+		return false
+	}
+	if ok && peer.endpoint == endpoint {
+		peer.lastSeen = announcement.Timestamp
+		return false
+	}
+	if ok {
+		peer.conn.Close()
+	}
+
+	conn, err := grpc.Dial(endpoint, d.dialOpt)
+	if err != nil {
+		d.logger.WithField(logging.PeerField, endpoint).Warnf("Failed to dial peer: %s", err)
+		delete(d.peers, key)
+		return false
+	}
+
+	d.logger.WithField(logging.PeerField, endpoint).Info("Discovered peer")
+	d.peers[key] = &udpPeer{endpoint: endpoint, lastSeen: announcement.Timestamp, conn: conn,
+		client: consensus.NewConsensusClient(conn)}
+	return !ok
+}
+
+func (d *UdpDiscoverer) expire() {
+	limit := time.Now().Add(-3 * d.interval).UnixNano()
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	for key, peer := range d.peers {
+		if peer.lastSeen < limit {
+			d.logger.WithField(logging.PeerField, peer.endpoint).Info("Dropping peer")
+			peer.conn.Close()
+			delete(d.peers, key)
+		}
+	}
+}
+
+func (d *UdpDiscoverer) announce(addrs ...*net.UDPAddr) {
+	announcement := &Announcement{
+		Endpoint:  d.endpoint,
+		PubKey:    d.keys.PublicKey,
+		ChainId:   d.chainId,
+		Timestamp: time.Now().UnixNano(),
+	}
+	err := announcement.Sign(d.keys.ToEcdsaPrivateKey())
+	if err != nil {
+		d.logger.Warnf("Failed to sign announcement: %s", err)
+		return
+	}
+
+	data := announcement.ToBytes()
+	for _, addr := range addrs {
+		_, err := d.conn.WriteToUDP(data, addr)
+		if err != nil {
+			d.logger.WithField(logging.PeerField, addr.String()).Debugf("Failed to announce: %s", err)
+		}
+	}
+}
+
+// announcedEndpoint replaces a missing or unspecified host in the announced endpoint by the sender address.
+func announcedEndpoint(endpoint string, from *net.UDPAddr) string {
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		return net.JoinHostPort(from.IP.String(), port)
+	}
+	return endpoint
+}
+
+// parseTargets resolves the announce targets. A target port can be a range, as in 127.0.0.1:1200-1205, so
+// nodes listening on different ports of the same host find each other. With no targets, announcements are
+// broadcast to the port the node listens on.
+func parseTargets(targets []string, listen string) ([]*net.UDPAddr, error) {
+	if len(targets) == 0 {
+		_, port, err := net.SplitHostPort(listen)
+		if err != nil {
+			return nil, err
+		}
+		targets = []string{net.JoinHostPort(broadcastHost, port)}
+	}
+
+	addrs := make([]*net.UDPAddr, 0)
+	for _, target := range targets {
+		host, ports, err := net.SplitHostPort(target)
+		if err != nil {
+			return nil, ErrInvalidAnnounceTarget
+		}
+
+		first, last := ports, ports
+		if i := strings.Index(ports, "-"); i >= 0 {
+			first, last = ports[:i], ports[i+1:]
+		}
+		from, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, ErrInvalidAnnounceTarget
+		}
+		to, err := strconv.Atoi(last)
+		if err != nil || from > to || to > 65535 {
+			return nil, ErrInvalidAnnounceTarget
+		}
+
+		for port := from; port <= to; port++ {
+			addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(host, strconv.Itoa(port)))
+			if err != nil {
+				return nil, err
+			}
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs, nil
+}
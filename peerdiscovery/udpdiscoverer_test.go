@@ -0,0 +1,128 @@
+package peerdiscovery_test
+
+import (
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/keypair"
+	"github.com/msaldanha/realChain/peerdiscovery"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"net"
+	"strconv"
+	"time"
+)
+
+var _ = Describe("UdpDiscoverer", func() {
+
+	It("Should find the peers that announce on the same chain", func() {
+		port1, port2 := freeUdpPort(), freeUdpPort()
+		dis1 := newUdpDiscoverer(port1, "127.0.0.1:2001", "test", targetRange(port1, port1))
+		dis2 := newUdpDiscoverer(port2, "127.0.0.1:2002", "test", targetRange(port1, port1))
+
+		Expect(dis1.Init()).To(BeNil())
+		Expect(dis2.Init()).To(BeNil())
+
+		Eventually(func() []string { return knownAddresses(dis1) }).Should(Equal([]string{"127.0.0.1:2002"}))
+		Eventually(func() []string { return knownAddresses(dis2) }).Should(Equal([]string{"127.0.0.1:2001"}))
+
+		peers, err := dis1.Peers()
+		Expect(err).To(BeNil())
+		Expect(len(peers)).To(Equal(1))
+	})
+
+	It("Should let the nodes of the same host listen on the same port", func() {
+		port := freeUdpPort()
+		dis1 := newUdpDiscoverer(port, "127.0.0.1:2001", "test", targetRange(port, port))
+		dis2 := newUdpDiscoverer(port, "127.0.0.1:2002", "test", targetRange(port, port))
+
+		Expect(dis1.Init()).To(BeNil())
+		Expect(dis2.Init()).To(BeNil())
+	})
+
+	It("Should ignore the peers of other chains", func() {
+		port1, port2 := freeUdpPort(), freeUdpPort()
+		dis1 := newUdpDiscoverer(port1, "127.0.0.1:2001", "test", targetRange(port1, port1))
+		dis2 := newUdpDiscoverer(port2, "127.0.0.1:2002", "other", targetRange(port1, port1))
+
+		Expect(dis1.Init()).To(BeNil())
+		Expect(dis2.Init()).To(BeNil())
+
+		Consistently(func() []string { return knownAddresses(dis1) }, "200ms").Should(BeEmpty())
+	})
+
+	It("Should ignore announcements with an invalid signature", func() {
+		port := freeUdpPort()
+		dis := newUdpDiscoverer(port, "127.0.0.1:2001", "test", targetRange(port, port))
+		Expect(dis.Init()).To(BeNil())
+
+		keys, err := keypair.New()
+		Expect(err).To(BeNil())
+		announcement := &peerdiscovery.Announcement{Endpoint: "127.0.0.1:2002", PubKey: keys.PublicKey,
+			ChainId: "test", Timestamp: time.Now().UnixNano()}
+		Expect(announcement.Sign(keys.ToEcdsaPrivateKey())).To(BeNil())
+		announcement.Endpoint = "127.0.0.1:2003"
+
+		conn, err := net.Dial("udp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+		Expect(err).To(BeNil())
+		defer conn.Close()
+		_, err = conn.Write(announcement.ToBytes())
+		Expect(err).To(BeNil())
+
+		Consistently(func() []string { return knownAddresses(dis) }, "200ms").Should(BeEmpty())
+	})
+
+	It("Should drop the peers that stopped announcing", func() {
+		port1, port2 := freeUdpPort(), freeUdpPort()
+		dis1 := newUdpDiscoverer(port1, "127.0.0.1:2001", "test", targetRange(port1, port1))
+		dis2 := newUdpDiscoverer(port2, "127.0.0.1:2002", "test", targetRange(port1, port1))
+
+		Expect(dis1.Init()).To(BeNil())
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go dis1.Run(ctx)
+
+		Expect(dis2.Init()).To(BeNil())
+
+		Eventually(func() []string { return knownAddresses(dis1) }).Should(Equal([]string{"127.0.0.1:2002"}))
+		Eventually(func() []string { return knownAddresses(dis1) }, "2s").Should(BeEmpty())
+	})
+
+	It("Should return error if an announce target is invalid", func() {
+		for _, target := range []string{"127.0.0.1", "127.0.0.1:a", "127.0.0.1:1205-1200", "127.0.0.1:1200-70000"} {
+			dis := newUdpDiscoverer(freeUdpPort(), "127.0.0.1:2001", "test", target)
+			Expect(dis.Init()).To(Equal(peerdiscovery.ErrInvalidAnnounceTarget))
+		}
+	})
+})
+
+func newUdpDiscoverer(port int, endpoint, chainId, target string) *peerdiscovery.UdpDiscoverer {
+	keys, err := keypair.New()
+	Expect(err).To(BeNil())
+
+	cfg := viper.New()
+	cfg.Set(config.CfgUdpServer, net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	cfg.Set(config.CfgNodeServer, endpoint)
+	cfg.Set(config.CfgChainId, chainId)
+	cfg.Set(config.CfgDiscoveryAnnounce, []string{target})
+	cfg.Set(config.CfgDiscoveryInterval, "100ms")
+	return peerdiscovery.NewUdpDiscoverer(cfg, keys, grpc.WithInsecure())
+}
+
+func freeUdpPort() int {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	Expect(err).To(BeNil())
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func targetRange(from, to int) string {
+	return "127.0.0.1:" + strconv.Itoa(from) + "-" + strconv.Itoa(to)
+}
+
+func knownAddresses(dis peerdiscovery.Discoverer) []string {
+	known, err := dis.KnownPeers()
+	Expect(err).To(BeNil())
+	return addresses(known)
+}
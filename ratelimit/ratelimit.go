@@ -0,0 +1,215 @@
+// Package ratelimit limits the calls made to the node: how often each client calls each method, with token
+// buckets, and how many calls of a method run at the same time.
+package ratelimit
+
+import (
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ErrRateLimited  = errors.Error("rate limit exceeded")
+	ErrTooManyCalls = errors.Error("too many concurrent calls")
+)
+
+// maxBuckets is the number of client buckets kept before the full ones, which are the same as new ones, are
+// dropped. If none is full, the clients without a bucket share the overflow bucket of the method until some are.
+const maxBuckets = 10000
+
+// Limit is the token bucket of a method: each client may call it Rate times per second on average, in bursts of
+// up to Burst calls. A zero rate does not limit the calls.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Limiter limits the calls of every client to every method, and the calls of a method running at the same time.
+// The limits are read from the configuration: limits.rate and limits.burst are the default limit of the methods,
+// limits.methods.<method>.rate and limits.methods.<method>.burst override it for a method and
+// limits.concurrency.<method> bounds the concurrent calls of a method. Method names are not case sensitive.
+type Limiter struct {
+	cfg         *viper.Viper
+	mtx         sync.Mutex
+	limits      map[string]Limit
+	concurrency map[string]chan struct{}
+	buckets     map[bucketKey]*bucket
+	overflow    map[string]*bucket
+}
+
+type bucketKey struct {
+	method string
+	client string
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func New(cfg *viper.Viper) *Limiter {
+	return &Limiter{
+		cfg:         cfg,
+		limits:      make(map[string]Limit),
+		concurrency: make(map[string]chan struct{}),
+		buckets:     make(map[bucketKey]*bucket),
+		overflow:    make(map[string]*bucket),
+	}
+}
+
+// Allow takes a token from the bucket of every client for the method, such as the address and the identity of a
+// caller, returning ErrRateLimited without taking any if one of them is empty.
+func (l *Limiter) Allow(method string, clients ...string) error {
+	method = strings.ToLower(method)
+	now := time.Now()
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	limit := l.limit(method)
+	if limit.Rate <= 0 {
+		return nil
+	}
+
+	buckets := make([]*bucket, 0, len(clients))
+	for _, client := range clients {
+		b := l.bucket(method, client, limit, now)
+		if containsBucket(buckets, b) {
+			continue
+		}
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+		b.last = now
+		if b.tokens < 1 {
+			return ErrRateLimited
+		}
+		buckets = append(buckets, b)
+	}
+	for _, b := range buckets {
+		b.tokens--
+	}
+	return nil
+}
+
+// Acquire starts a call of the method, returning ErrTooManyCalls if too many are running. The returned function
+// must be called when the call ends.
+func (l *Limiter) Acquire(method string) (func(), error) {
+	sem := l.semaphore(strings.ToLower(method))
+	if sem == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	default:
+		return nil, ErrTooManyCalls
+	}
+}
+
+// MaxRecvSize is the maximum size in bytes of the messages received, or 0 for the default gRPC limit.
+func (l *Limiter) MaxRecvSize() int {
+	return l.cfg.GetInt(config.CfgLimitMaxRecvSize)
+}
+
+// MaxSendSize is the maximum size in bytes of the messages sent, or 0 for the default gRPC limit.
+func (l *Limiter) MaxSendSize() int {
+	return l.cfg.GetInt(config.CfgLimitMaxSendSize)
+}
+
+// ServerOptions returns the gRPC server options that bound the size of the messages.
+func (l *Limiter) ServerOptions() []grpc.ServerOption {
+	opts := make([]grpc.ServerOption, 0)
+	if size := l.MaxRecvSize(); size > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(size))
+	}
+	if size := l.MaxSendSize(); size > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(size))
+	}
+	return opts
+}
+
+// limit returns the limit of the method. The caller must hold mtx.
+func (l *Limiter) limit(method string) Limit {
+	if limit, ok := l.limits[method]; ok {
+		return limit
+	}
+
+	limit := Limit{Rate: l.cfg.GetFloat64(config.CfgLimitRate), Burst: l.cfg.GetInt(config.CfgLimitBurst)}
+	prefix := config.CfgLimitMethods + "." + method + "."
+	if l.cfg.IsSet(prefix + "rate") {
+		limit.Rate = l.cfg.GetFloat64(prefix + "rate")
+	}
+	if l.cfg.IsSet(prefix + "burst") {
+		limit.Burst = l.cfg.GetInt(prefix + "burst")
+	}
+	if limit.Burst < 1 {
+		limit.Burst = int(math.Max(1, math.Ceil(limit.Rate)))
+	}
+
+	l.limits[method] = limit
+	return limit
+}
+
+func (l *Limiter) semaphore(method string) chan struct{} {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	sem, ok := l.concurrency[method]
+	if !ok {
+		if max := l.cfg.GetInt(config.CfgLimitConcurrency + "." + method); max > 0 {
+			sem = make(chan struct{}, max)
+		}
+		l.concurrency[method] = sem
+	}
+	return sem
+}
+
+// bucket returns the bucket of the client for the method, creating it if there is room, or else the overflow bucket
+// of the method. The caller must hold mtx.
+func (l *Limiter) bucket(method, client string, limit Limit, now time.Time) *bucket {
+	key := bucketKey{method: method, client: client}
+	if b, ok := l.buckets[key]; ok {
+		return b
+	}
+
+	if len(l.buckets) >= maxBuckets {
+		l.prune(now)
+	}
+	if len(l.buckets) < maxBuckets {
+		b := &bucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+		return b
+	}
+
+	b, ok := l.overflow[method]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		l.overflow[method] = b
+	}
+	return b
+}
+
+// prune drops the buckets that refilled since they were last used. The caller must hold mtx.
+func (l *Limiter) prune(now time.Time) {
+	for key, b := range l.buckets {
+		limit := l.limits[key.method]
+		if b.tokens+now.Sub(b.last).Seconds()*limit.Rate >= float64(limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// containsBucket tells whether b is one of buckets, as the clients without a bucket share the overflow one.
+func containsBucket(buckets []*bucket, b *bucket) bool {
+	for _, other := range buckets {
+		if other == b {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package ratelimit_test
+
+import (
+	"fmt"
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/ratelimit"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"time"
+)
+
+var _ = Describe("Limiter", func() {
+
+	var cfg *viper.Viper
+
+	BeforeEach(func() {
+		cfg = viper.New()
+	})
+
+	It("Should not limit the calls without limits", func() {
+		limiter := ratelimit.New(cfg)
+		for i := 0; i < 100; i++ {
+			Expect(limiter.Allow("Register", "ip:127.0.0.1")).To(BeNil())
+		}
+		release, err := limiter.Acquire("Register")
+		Expect(err).To(BeNil())
+		release()
+		Expect(limiter.ServerOptions()).To(BeEmpty())
+	})
+
+	It("Should allow bursts and refill the buckets at the rate", func() {
+		cfg.Set(config.CfgLimitRate, 50)
+		cfg.Set(config.CfgLimitBurst, 2)
+		limiter := ratelimit.New(cfg)
+
+		Expect(limiter.Allow("Register", "ip:127.0.0.1")).To(BeNil())
+		Expect(limiter.Allow("Register", "ip:127.0.0.1")).To(BeNil())
+		Expect(limiter.Allow("Register", "ip:127.0.0.1")).To(Equal(ratelimit.ErrRateLimited))
+
+		time.Sleep(50 * time.Millisecond)
+		Expect(limiter.Allow("Register", "ip:127.0.0.1")).To(BeNil())
+	})
+
+	It("Should keep a bucket per client and method", func() {
+		cfg.Set(config.CfgLimitRate, 0.001)
+		cfg.Set(config.CfgLimitBurst, 1)
+		limiter := ratelimit.New(cfg)
+
+		Expect(limiter.Allow("Register", "ip:127.0.0.1")).To(BeNil())
+		Expect(limiter.Allow("Register", "ip:127.0.0.1")).To(Equal(ratelimit.ErrRateLimited))
+		Expect(limiter.Allow("register", "ip:127.0.0.1")).To(Equal(ratelimit.ErrRateLimited))
+		Expect(limiter.Allow("Register", "ip:127.0.0.2")).To(BeNil())
+		Expect(limiter.Allow("Verify", "ip:127.0.0.1")).To(BeNil())
+	})
+
+	It("Should refuse the calls over the limit of any of the clients", func() {
+		cfg.Set(config.CfgLimitRate, 0.001)
+		cfg.Set(config.CfgLimitBurst, 2)
+		limiter := ratelimit.New(cfg)
+
+		Expect(limiter.Allow("Register", "ip:127.0.0.1", "token:a")).To(BeNil())
+		Expect(limiter.Allow("Register", "ip:127.0.0.1", "token:b")).To(BeNil())
+		Expect(limiter.Allow("Register", "ip:127.0.0.1", "token:c")).To(Equal(ratelimit.ErrRateLimited))
+
+		Expect(limiter.Allow("Register", "ip:127.0.0.2", "token:a")).To(BeNil())
+		Expect(limiter.Allow("Register", "ip:127.0.0.3", "token:a")).To(Equal(ratelimit.ErrRateLimited))
+		Expect(limiter.Allow("Register", "ip:127.0.0.3", "token:c")).To(BeNil())
+	})
+
+	It("Should make the new clients share a bucket when there are too many", func() {
+		cfg.Set(config.CfgLimitRate, 0.001)
+		cfg.Set(config.CfgLimitBurst, 1)
+		limiter := ratelimit.New(cfg)
+
+		for i := 0; i < 10000; i++ {
+			Expect(limiter.Allow("Register", fmt.Sprintf("ip:%d", i))).To(BeNil())
+		}
+		Expect(limiter.Allow("Register", "ip:new-1")).To(BeNil())
+		Expect(limiter.Allow("Register", "ip:new-2")).To(Equal(ratelimit.ErrRateLimited))
+		Expect(limiter.Allow("Register", "ip:0")).To(Equal(ratelimit.ErrRateLimited))
+		Expect(limiter.Allow("Verify", "ip:new-1")).To(BeNil())
+	})
+
+	It("Should override the default limit per method", func() {
+		cfg.Set(config.CfgLimitRate, 0.001)
+		cfg.Set(config.CfgLimitBurst, 1)
+		cfg.Set(config.CfgLimitMethods+".ping.rate", 0)
+		cfg.Set(config.CfgLimitMethods+".vote.burst", 3)
+		limiter := ratelimit.New(cfg)
+
+		for i := 0; i < 10; i++ {
+			Expect(limiter.Allow("Ping", "peer:01")).To(BeNil())
+		}
+		for i := 0; i < 3; i++ {
+			Expect(limiter.Allow("Vote", "peer:01")).To(BeNil())
+		}
+		Expect(limiter.Allow("Vote", "peer:01")).To(Equal(ratelimit.ErrRateLimited))
+	})
+
+	It("Should bound the concurrent calls of a method", func() {
+		cfg.Set(config.CfgLimitConcurrency+".getaddressstatement", 2)
+		limiter := ratelimit.New(cfg)
+
+		release1, err := limiter.Acquire("GetAddressStatement")
+		Expect(err).To(BeNil())
+		release2, err := limiter.Acquire("GetAddressStatement")
+		Expect(err).To(BeNil())
+		_, err = limiter.Acquire("GetAddressStatement")
+		Expect(err).To(Equal(ratelimit.ErrTooManyCalls))
+
+		release1()
+		release3, err := limiter.Acquire("GetAddressStatement")
+		Expect(err).To(BeNil())
+		release2()
+		release3()
+	})
+
+	It("Should bound the size of the messages", func() {
+		cfg.Set(config.CfgLimitMaxRecvSize, 1024)
+		cfg.Set(config.CfgLimitMaxSendSize, 2048)
+		limiter := ratelimit.New(cfg)
+
+		Expect(limiter.MaxRecvSize()).To(Equal(1024))
+		Expect(limiter.MaxSendSize()).To(Equal(2048))
+		Expect(limiter.ServerOptions()).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,174 @@
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/spf13/viper"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"time"
+)
+
+const (
+	ErrCertificateExists = errors.Error("node certificate already exists")
+	ErrInvalidPem        = errors.Error("invalid PEM file")
+)
+
+const (
+	caValidity   = 10 * 365 * 24 * time.Hour
+	nodeValidity = 2 * 365 * 24 * time.Hour
+)
+
+// GenerateCerts creates a node certificate for the given hosts, signed by the dev CA, and returns its
+// fingerprint. The CA is created if the configured CA files do not exist, otherwise it is reused, so the
+// certificates of all nodes can be signed by the same CA. The certificate can be used both by servers and
+// clients, so it also serves for mutual TLS.
+func GenerateCerts(cfg *viper.Viper, hosts []string) (string, error) {
+	if _, err := os.Stat(path(cfg, config.CfgTlsCert)); err == nil {
+		return "", ErrCertificateExists
+	}
+
+	caCert, caKey, err := loadOrCreateCA(path(cfg, config.CfgTlsCA), path(cfg, config.CfgTlsCAKey))
+	if err != nil {
+		return "", err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	template, err := newTemplate(hosts[0], nodeValidity)
+	if err != nil {
+		return "", err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return "", err
+	}
+
+	err = writeKey(path(cfg, config.CfgTlsKey), key)
+	if err != nil {
+		return "", err
+	}
+	err = writePem(path(cfg, config.CfgTlsCert), "CERTIFICATE", der, 0644)
+	if err != nil {
+		return "", err
+	}
+	return Fingerprint(der), nil
+}
+
+func loadOrCreateCA(certFile, keyFile string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	if _, err := os.Stat(certFile); err == nil {
+		return loadCAKeyPair(certFile, keyFile)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template, err := newTemplate("realChain dev CA", caValidity)
+	if err != nil {
+		return nil, nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = writeKey(keyFile, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = writePem(certFile, "CERTIFICATE", der, 0644)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	return cert, key, err
+}
+
+func loadCAKeyPair(certFile, keyFile string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certDer, err := readPem(certFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(certDer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyDer, err := readPem(keyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParseECPrivateKey(keyDer)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+func newTemplate(commonName string, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"realChain"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(validity),
+	}, nil
+}
+
+func writeKey(file string, key *ecdsa.PrivateKey) error {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	return writePem(file, "EC PRIVATE KEY", der, 0600)
+}
+
+func writePem(file, blockType string, der []byte, perm os.FileMode) error {
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	return ioutil.WriteFile(file, data, perm)
+}
+
+func readPem(file string) ([]byte, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidPem
+	}
+	return block.Bytes, nil
+}
@@ -0,0 +1,144 @@
+package security
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/errors"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	ErrInvalidCA           = errors.Error("no certificate found in CA file")
+	ErrClientAuthWithoutCA = errors.Error("client authentication requires a CA")
+	ErrPinMismatch         = errors.Error("certificate does not match any pinned certificate")
+	ErrNoPeerCertificate   = errors.Error("peer did not present a certificate")
+)
+
+// ServerOptions returns the gRPC server options for the TLS configuration. With TLS disabled there are no
+// options and the server accepts plain connections. With client authentication enabled the clients must
+// present a certificate signed by the configured CA.
+func ServerOptions(cfg *viper.Viper) ([]grpc.ServerOption, error) {
+	tlsConfig, err := ServerConfig(cfg)
+	if err != nil || tlsConfig == nil {
+		return nil, err
+	}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}, nil
+}
+
+// ServerConfig returns the TLS configuration of the node servers, or nil with TLS disabled.
+func ServerConfig(cfg *viper.Viper) (*tls.Config, error) {
+	if !cfg.GetBool(config.CfgTls) {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(path(cfg, config.CfgTlsCert), path(cfg, config.CfgTlsKey))
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if cfg.GetBool(config.CfgTlsClientAuth) {
+		if cfg.GetString(config.CfgTlsCA) == "" {
+			return nil, ErrClientAuthWithoutCA
+		}
+		pool, err := loadCA(path(cfg, config.CfgTlsCA))
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// DialOption returns the gRPC dial option for the TLS configuration. With TLS disabled the connection is
+// insecure. The server certificate is verified against the configured CA, against the pinned certificate
+// fingerprints, or both. The node certificate, when configured, is presented to servers that require client
+// authentication.
+func DialOption(cfg *viper.Viper) (grpc.DialOption, error) {
+	if !cfg.GetBool(config.CfgTls) {
+		return grpc.WithInsecure(), nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.GetString(config.CfgTlsCA) != "" {
+		pool, err := loadCA(path(cfg, config.CfgTlsCA))
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.GetString(config.CfgTlsCert) != "" && cfg.GetString(config.CfgTlsKey) != "" {
+		cert, err := tls.LoadX509KeyPair(path(cfg, config.CfgTlsCert), path(cfg, config.CfgTlsKey))
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	pins := make(map[string]bool)
+	for _, pin := range cfg.GetStringSlice(config.CfgTlsPins) {
+		pins[normalizeFingerprint(pin)] = true
+	}
+	if len(pins) > 0 {
+		// A pinned certificate is trusted by itself, so self-signed certificates can be pinned without a CA.
+		tlsConfig.InsecureSkipVerify = tlsConfig.RootCAs == nil
+		tlsConfig.VerifyPeerCertificate = verifyPins(pins)
+	}
+
+	return grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)), nil
+}
+
+// Fingerprint returns the SHA-256 fingerprint of a DER encoded certificate, as used to pin it.
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
+
+func verifyPins(pins map[string]bool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return ErrNoPeerCertificate
+		}
+		if !pins[Fingerprint(rawCerts[0])] {
+			return ErrPinMismatch
+		}
+		return nil
+	}
+}
+
+func loadCA(file string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, ErrInvalidCA
+	}
+	return pool, nil
+}
+
+// path returns the file set in the key configuration property, relative to the data folder.
+func path(cfg *viper.Viper, key string) string {
+	file := cfg.GetString(key)
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(cfg.GetString(config.CfgDataFolder), file)
+}
+
+func normalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.Replace(fingerprint, ":", "", -1))
+}
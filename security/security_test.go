@@ -0,0 +1,165 @@
+package security_test
+
+import (
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/security"
+	"github.com/msaldanha/realChain/server"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ = Describe("Security", func() {
+
+	var dir string
+	var cfg *viper.Viper
+	var fingerprint string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "security")
+		Expect(err).To(BeNil())
+
+		cfg = newConfig(dir)
+		fingerprint, err = security.GenerateCerts(cfg, []string{"localhost", "127.0.0.1"})
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("Should connect over TLS verifying the server certificate with the CA", func() {
+		addr := startServer(cfg)
+
+		Expect(ping(cfg, addr)).To(BeNil())
+	})
+
+	It("Should connect to a pinned server without a CA", func() {
+		addr := startServer(cfg)
+
+		clientCfg := newConfig(dir)
+		clientCfg.Set(config.CfgTlsCA, "")
+		clientCfg.Set(config.CfgTlsPins, []string{fingerprint})
+
+		Expect(ping(clientCfg, addr)).To(BeNil())
+	})
+
+	It("Should not connect to a server that does not match the pinned certificate", func() {
+		addr := startServer(cfg)
+
+		clientCfg := newConfig(dir)
+		clientCfg.Set(config.CfgTlsPins, []string{"00" + fingerprint[2:]})
+
+		Expect(ping(clientCfg, addr)).NotTo(BeNil())
+	})
+
+	It("Should not connect to a server whose certificate is not signed by the CA", func() {
+		addr := startServer(cfg)
+
+		otherDir, err := ioutil.TempDir("", "security")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(otherDir)
+		clientCfg := newConfig(otherDir)
+		_, err = security.GenerateCerts(clientCfg, []string{"localhost"})
+		Expect(err).To(BeNil())
+
+		Expect(ping(clientCfg, addr)).NotTo(BeNil())
+	})
+
+	It("Should require a client certificate for mutual TLS", func() {
+		cfg.Set(config.CfgTlsClientAuth, true)
+		addr := startServer(cfg)
+
+		Expect(ping(cfg, addr)).To(BeNil())
+
+		clientCfg := newConfig(dir)
+		clientCfg.Set(config.CfgTlsCert, "")
+		clientCfg.Set(config.CfgTlsKey, "")
+		Expect(ping(clientCfg, addr)).NotTo(BeNil())
+	})
+
+	It("Should reuse the CA to sign the certificates of other nodes", func() {
+		cfg.Set(config.CfgTlsClientAuth, true)
+		addr := startServer(cfg)
+
+		clientCfg := newConfig(dir)
+		clientCfg.Set(config.CfgTlsCert, "node2.pem")
+		clientCfg.Set(config.CfgTlsKey, "node2-key.pem")
+		_, err := security.GenerateCerts(clientCfg, []string{"localhost"})
+		Expect(err).To(BeNil())
+
+		Expect(ping(clientCfg, addr)).To(BeNil())
+	})
+
+	It("Should not overwrite an existing node certificate", func() {
+		_, err := security.GenerateCerts(cfg, []string{"localhost"})
+		Expect(err).To(Equal(security.ErrCertificateExists))
+	})
+
+	It("Should return error if client authentication is enabled without a CA", func() {
+		cfg.Set(config.CfgTlsClientAuth, true)
+		cfg.Set(config.CfgTlsCA, "")
+
+		_, err := security.ServerOptions(cfg)
+		Expect(err).To(Equal(security.ErrClientAuthWithoutCA))
+	})
+
+	It("Should not use TLS if it is disabled", func() {
+		cfg.Set(config.CfgTls, false)
+
+		opts, err := security.ServerOptions(cfg)
+		Expect(err).To(BeNil())
+		Expect(opts).To(BeEmpty())
+
+		addr := startServer(cfg)
+		Expect(ping(cfg, addr)).To(BeNil())
+		Expect(filepath.Join(dir, "node.pem")).To(BeAnExistingFile())
+	})
+})
+
+func newConfig(dir string) *viper.Viper {
+	cfg := viper.New()
+	cfg.Set(config.CfgDataFolder, dir)
+	cfg.Set(config.CfgTls, true)
+	cfg.Set(config.CfgTlsCert, "node.pem")
+	cfg.Set(config.CfgTlsKey, "node-key.pem")
+	cfg.Set(config.CfgTlsCA, "ca.pem")
+	cfg.Set(config.CfgTlsCAKey, "ca-key.pem")
+	return cfg
+}
+
+func startServer(cfg *viper.Viper) string {
+	opts, err := security.ServerOptions(cfg)
+	Expect(err).To(BeNil())
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(BeNil())
+
+	srv := server.New(nil, nil, nil, nil, lis)
+	go srv.Run(opts...)
+
+	return lis.Addr().String()
+}
+
+func ping(cfg *viper.Viper, addr string) error {
+	dialOpt, err := security.DialOption(cfg)
+	Expect(err).To(BeNil())
+
+	conn, err := grpc.Dial(addr, dialOpt)
+	Expect(err).To(BeNil())
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = consensus.NewConsensusClient(conn).Ping(ctx, &consensus.PingRequest{}, grpc.FailFast(false))
+	return err
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"fmt"
+	"github.com/msaldanha/realChain/admin"
+	"github.com/msaldanha/realChain/auth"
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/ledgersync"
+	"github.com/msaldanha/realChain/peerdiscovery"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"time"
+)
+
+// redacted replaces the secrets in the effective configuration.
+const redacted = "[redacted]"
+
+// Admin answers the introspection calls of the node operators: the node identity, its peers and their health, the
+// ledger counters, the votings in progress and the effective configuration.
+type Admin struct {
+	srv   *Server
+	addr  string
+	peers *peerdiscovery.PeerManager
+	ae    *ledgersync.AntiEntropy
+	cfg   *viper.Viper
+}
+
+// NewAdmin creates the admin service of the node whose address is addr. ae is nil if anti-entropy is disabled.
+func NewAdmin(srv *Server, addr string, peers *peerdiscovery.PeerManager, ae *ledgersync.AntiEntropy,
+	cfg *viper.Viper) *Admin {
+	return &Admin{srv: srv, addr: addr, peers: peers, ae: ae, cfg: cfg}
+}
+
+func (a *Admin) Status(ctx context.Context, request *admin.StatusRequest) (*admin.StatusResult, error) {
+	ledgerStatus, err := a.ledgerStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	return &admin.StatusResult{
+		Address:         a.addr,
+		Version:         config.Version,
+		ProtocolVersion: consensus.ProtocolVersion,
+		ChainId:         a.cfg.GetString(config.CfgChainId),
+		Peers:           a.peerStatus(),
+		Ledger:          ledgerStatus,
+		Rounds:          a.rounds(),
+		AntiEntropy:     a.antiEntropyStatus(),
+		Config:          a.config(),
+	}, nil
+}
+
+func (a *Admin) ledgerStatus() (*admin.LedgerStatus, error) {
+	stats, err := a.srv.ld.GetStats()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &admin.LedgerStatus{Blocks: int64(stats.Blocks), Accounts: int64(stats.Accounts),
+		PendingSends: int64(stats.PendingSends)}
+	if a.srv.events != nil {
+		status.LastSeq, err = a.srv.events.LastSeq()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+func (a *Admin) peerStatus() []*admin.PeerStatus {
+	if a.peers == nil {
+		return nil
+	}
+
+	peers := make([]*admin.PeerStatus, 0)
+	for _, stats := range a.peers.Stats() {
+		peer := &admin.PeerStatus{
+			Address:     stats.Address,
+			Healthy:     stats.Healthy,
+			Score:       int32(stats.Score),
+			Latency:     int64(stats.Latency),
+			Failures:    int32(stats.Failures),
+			BannedUntil: unixNano(stats.BannedUntil),
+		}
+		if stats.Info != nil {
+			peer.PubKey = stats.Info.PubKey
+			peer.ProtocolVersion = stats.Info.Version
+		}
+		peers = append(peers, peer)
+	}
+	return peers
+}
+
+func (a *Admin) rounds() []*admin.Round {
+	rounds := make([]*admin.Round, 0)
+	for _, round := range a.srv.Rounds() {
+		rounds = append(rounds, &admin.Round{SendHash: round.SendHash, ReceiveHash: round.ReceiveHash,
+			Started: unixNano(round.Started)})
+	}
+	return rounds
+}
+
+func (a *Admin) antiEntropyStatus() *admin.AntiEntropyStatus {
+	if a.ae == nil {
+		return &admin.AntiEntropyStatus{}
+	}
+
+	stats := a.ae.Stats()
+	return &admin.AntiEntropyStatus{
+		Enabled:      true,
+		Rounds:       int64(stats.Rounds),
+		FailedRounds: int64(stats.FailedRounds),
+		LastRound:    unixNano(stats.LastRound),
+		LastDiverged: int64(stats.LastDiverged),
+		TotalStored:  int64(stats.TotalStored),
+	}
+}
+
+// config returns the effective configuration: the configuration file merged with the defaults and flags, with
+// the API tokens redacted.
+func (a *Admin) config() map[string]string {
+	settings := make(map[string]string)
+	for _, key := range a.cfg.AllKeys() {
+		if auth.IsSecret(key) {
+			settings[key] = redacted
+			continue
+		}
+		settings[key] = fmt.Sprint(a.cfg.Get(key))
+	}
+	return settings
+}
+
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
@@ -0,0 +1,113 @@
+package server_test
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/msaldanha/realChain/address"
+	"github.com/msaldanha/realChain/admin"
+	"github.com/msaldanha/realChain/config"
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/keyvaluestore"
+	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/server"
+	"github.com/msaldanha/realChain/tests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+)
+
+var _ = Describe("Admin", func() {
+
+	var mockCtrl *gomock.Controller
+	var ld *tests.MockLedger
+	var dis *tests.MockDiscoverer
+	var conCli *tests.MockConsensusClient
+	var events *ledger.EventBus
+	var srv *server.Server
+	var adm *server.Admin
+	var sendTx *ledger.Transaction
+	var receiveTx *ledger.Transaction
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		ld = tests.NewMockLedger(mockCtrl)
+		dis = tests.NewMockDiscoverer(mockCtrl)
+		conCli = tests.NewMockConsensusClient(mockCtrl)
+
+		events = ledger.NewEventBus(keyvaluestore.NewMemoryKeyValueStore())
+		srv = server.New(ld, events, nil, dis, nil)
+		srv.SetChainId("test")
+		dis.EXPECT().PeerIdentity(gomock.Any()).Return(tests.PeerIdentity).AnyTimes()
+
+		cfg := viper.New()
+		cfg.Set(config.CfgChainId, "testchain")
+		cfg.Set(config.CfgNodeServer, "127.0.0.1:1300")
+		cfg.Set(config.CfgAuthToken, "client-secret")
+		cfg.Set(config.CfgAuthTokens+".admin", []string{"admin-secret"})
+		adm = server.NewAdmin(srv, "node-address", nil, nil, cfg)
+
+		genesisTx, genesisAddr := tests.CreateGenesisTransaction(1000)
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+		sendTx, err = ledger.CreateSendTransaction(genesisTx, genesisAddr, receiveAddr.Address, 300)
+		Expect(err).To(BeNil())
+		receiveTx, err = ledger.CreateReceiveTransaction(sendTx, 300, receiveAddr, nil)
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Should report the node identity, ledger counters and configuration", func() {
+		ld.EXPECT().GetStats().Return(&ledger.Stats{Blocks: 5, Accounts: 2, PendingSends: 1}, nil)
+		Expect(events.Publish(sendTx)).To(BeNil())
+
+		status, err := adm.Status(nil, &admin.StatusRequest{})
+		Expect(err).To(BeNil())
+		Expect(status.Address).To(Equal("node-address"))
+		Expect(status.Version).To(Equal(config.Version))
+		Expect(status.ProtocolVersion).To(Equal(uint32(consensus.ProtocolVersion)))
+		Expect(status.ChainId).To(Equal("testchain"))
+		Expect(status.Ledger).To(Equal(&admin.LedgerStatus{Blocks: 5, Accounts: 2, PendingSends: 1, LastSeq: 1}))
+		Expect(status.Rounds).To(BeEmpty())
+		Expect(status.AntiEntropy.Enabled).To(BeFalse())
+		Expect(status.Config).To(HaveKeyWithValue(config.CfgNodeServer, "127.0.0.1:1300"))
+		Expect(status.Config).To(HaveKeyWithValue(config.CfgAuthToken, "[redacted]"))
+		Expect(status.Config).To(HaveKeyWithValue(config.CfgAuthTokens+".admin", "[redacted]"))
+	})
+
+	It("Should report the votings in progress", func() {
+		voting := make(chan bool)
+		release := make(chan bool)
+		ld.EXPECT().Verify(sendTx, receiveTx)
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{conCli}, nil)
+		conCli.EXPECT().Vote(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx interface{}, request *consensus.VoteRequest) (*consensus.VoteResult, error) {
+				close(voting)
+				<-release
+				return &consensus.VoteResult{Vote: tests.CreatePeerVote(false, sendTx, receiveTx)}, nil
+			})
+		ld.EXPECT().GetStats().Return(&ledger.Stats{}, nil).Times(2)
+
+		registered := make(chan error, 1)
+		go func() {
+			_, err := srv.Register(nil, &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx})
+			registered <- err
+		}()
+		Eventually(voting).Should(BeClosed())
+
+		status, err := adm.Status(nil, &admin.StatusRequest{})
+		Expect(err).To(BeNil())
+		Expect(status.Rounds).To(HaveLen(1))
+		Expect(status.Rounds[0].SendHash).To(Equal(sendTx.Hash))
+		Expect(status.Rounds[0].ReceiveHash).To(Equal(receiveTx.Hash))
+		Expect(status.Rounds[0].Started).NotTo(BeZero())
+
+		close(release)
+		Eventually(registered).Should(Receive(Equal(server.ErrDeclinedByVoting)))
+
+		status, err = adm.Status(nil, &admin.StatusRequest{})
+		Expect(err).To(BeNil())
+		Expect(status.Rounds).To(BeEmpty())
+	})
+})
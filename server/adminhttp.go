@@ -0,0 +1,76 @@
+package server
+
+import (
+	"crypto/tls"
+	"golang.org/x/net/context"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const adminShutdownTimeout = 5 * time.Second
+
+// adminHTTPServer serves the admin gRPC service and an HTTP handler on the same listener. The gRPC calls are told
+// apart by their HTTP/2 content type, with TLS negotiated by ALPN and without TLS by the HTTP/2 connection preface.
+type adminHTTPServer struct {
+	http *http.Server
+	grpc *grpc.Server
+	tls  *tls.Config
+}
+
+func newAdminHTTPServer(grpcServer *grpc.Server, handler http.Handler, tlsConfig *tls.Config) *adminHTTPServer {
+	mux := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+
+	s := &adminHTTPServer{grpc: grpcServer}
+	if tlsConfig == nil {
+		s.http = &http.Server{Handler: h2c.NewHandler(mux, &http2.Server{})}
+		return s
+	}
+	s.tls = tlsConfig.Clone()
+	s.http = &http.Server{Handler: mux, TLSConfig: s.tls}
+	return s
+}
+
+// Serve serves the listener until the server is shut down, returning nil then.
+func (s *adminHTTPServer) Serve(lis net.Listener) error {
+	var err error
+	if s.tls != nil {
+		err = http2.ConfigureServer(s.http, nil)
+		if err == nil {
+			err = s.http.Serve(tls.NewListener(lis, s.http.TLSConfig))
+		}
+	} else {
+		err = s.http.Serve(lis)
+	}
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops accepting calls and waits up to adminShutdownTimeout for the connections to go idle, then ends
+// them and the remaining gRPC calls, which are all quick admin calls.
+func (s *adminHTTPServer) Shutdown(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, adminShutdownTimeout)
+	defer cancel()
+	if s.http.Shutdown(ctx) != nil {
+		s.http.Close()
+	}
+	s.grpc.Stop()
+}
+
+// Close ends the requests and the calls right away.
+func (s *adminHTTPServer) Close() {
+	s.http.Close()
+	s.grpc.Stop()
+}
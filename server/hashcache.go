@@ -0,0 +1,41 @@
+package server
+
+import "sync"
+
+// hashCache is a bounded set of hashes used to suppress duplicated gossip.
+// When full, the oldest hash is evicted.
+type hashCache struct {
+	mtx   sync.Mutex
+	items map[string]struct{}
+	order []string
+	next  int
+}
+
+func newHashCache(size int) *hashCache {
+	return &hashCache{items: make(map[string]struct{}), order: make([]string, size)}
+}
+
+// Add returns false if the hash was already in the cache.
+func (c *hashCache) Add(hash string) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if _, ok := c.items[hash]; ok {
+		return false
+	}
+
+	if old := c.order[c.next]; old != "" {
+		delete(c.items, old)
+	}
+	c.order[c.next] = hash
+	c.next = (c.next + 1) % len(c.order)
+	c.items[hash] = struct{}{}
+	return true
+}
+
+func (c *hashCache) Remove(hash string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	delete(c.items, hash)
+}
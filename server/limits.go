@@ -0,0 +1,85 @@
+package server
+
+import (
+	"encoding/hex"
+	"github.com/msaldanha/realChain/auth"
+	"github.com/msaldanha/realChain/ratelimit"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"net"
+	"path"
+)
+
+// SetLimiter sets the limiter of the calls served by Run. Without a limiter the calls are not limited.
+func (s *Server) SetLimiter(limiter *ratelimit.Limiter) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.limiter = limiter
+}
+
+// limitUnary refuses the unary calls over the rate limit of the client or over the concurrency limit of the
+// method. It must run inside the authentication, so the authenticated clients are also limited by identity.
+func (s *Server) limitUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	release, err := s.limit(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return handler(ctx, req)
+}
+
+// limitStream refuses the streaming calls over the rate limit of the client or over the concurrency limit of
+// the method.
+func (s *Server) limitStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	release, err := s.limit(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return handler(srv, ss)
+}
+
+func (s *Server) limit(ctx context.Context, fullMethod string) (func(), error) {
+	if s.limiter == nil {
+		return func() {}, nil
+	}
+
+	method := path.Base(fullMethod)
+	err := s.limiter.Allow(method, clientKeys(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	return s.limiter.Acquire(method)
+}
+
+// clientKeys identifies the client of a call for the rate limits: by its remote IP and, if it is authenticated, by
+// the node key of the peer or the API token of the client, which may connect from several addresses. A call over
+// the limit of either is refused. The clients of the local socket share a single bucket.
+func clientKeys(ctx context.Context) []string {
+	keys := []string{addressKey(ctx)}
+	if identity := PeerIdentity(ctx); identity != nil {
+		keys = append(keys, "peer:"+hex.EncodeToString(identity.PubKey))
+	} else if identity := auth.FromContext(ctx); identity != nil {
+		keys = append(keys, "token:"+identity.Name)
+	}
+	return keys
+}
+
+// addressKey identifies the client of a call by its remote IP.
+func addressKey(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "ip:unknown"
+	}
+	if p.Addr.Network() == "unix" {
+		return "local"
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return "ip:" + p.Addr.String()
+	}
+	return "ip:" + host
+}
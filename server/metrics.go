@@ -0,0 +1,45 @@
+package server
+
+import (
+	"github.com/msaldanha/realChain/errcodes"
+	"github.com/msaldanha/realChain/metrics"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"time"
+)
+
+var (
+	requests = metrics.NewCounter("realchain_grpc_requests_total",
+		"gRPC calls handled by the node, by method and status code.", "method", "code")
+	requestDuration = metrics.NewHistogram("realchain_grpc_request_duration_seconds",
+		"Duration of the gRPC calls handled by the node, by method.", metrics.DefaultBuckets, "method")
+	voteOutcomes = metrics.NewCounter("realchain_votes_total",
+		"Votes asked in the votings started by the node, by outcome: accept, reject, invalid or error.", "outcome")
+	votingDuration = metrics.NewHistogram("realchain_voting_duration_seconds",
+		"Duration of the votings started by the node, by result: accepted, declined or failed.",
+		metrics.DefaultBuckets, "result")
+)
+
+// metricsUnaryInterceptor counts the unary calls and measures their duration. It must run outside the errcodes
+// interceptor, so the status code of the catalogued errors is counted.
+func metricsUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	observeCall(info.FullMethod, start, err)
+	return resp, err
+}
+
+// metricsStreamInterceptor counts the streaming calls and measures their duration.
+func metricsStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	observeCall(info.FullMethod, start, err)
+	return err
+}
+
+func observeCall(method string, start time.Time, err error) {
+	requests.Inc(method, errcodes.Code(err).String())
+	requestDuration.Since(start, method)
+}
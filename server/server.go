@@ -1,13 +1,29 @@
 package server
 
 import (
+	"bytes"
+	"encoding/hex"
+	"github.com/msaldanha/realChain/admin"
+	"github.com/msaldanha/realChain/auth"
 	"github.com/msaldanha/realChain/errors"
 	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/errcodes"
 	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/logging"
 	"github.com/msaldanha/realChain/peerdiscovery"
+	"github.com/msaldanha/realChain/ratelimit"
 	"golang.org/x/net/context"
+	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"crypto/tls"
+	"math"
 	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
 )
 
 //go:generate mockgen -destination=../tests/mock_listener.go -package=tests net Listener
@@ -15,36 +31,317 @@ import (
 const (
 	ErrDeclinedByVoting                 = errors.Error("transaction declined by voting")
 	ErrNoPeersForVoting                 = errors.Error("no peers for voting")
+	ErrInvalidPublishRequest            = errors.Error("invalid publish request")
+	ErrTransactionNotInChain            = errors.Error("transaction not in address chain")
+	ErrInvalidVote                      = errors.Error("invalid vote")
+	ErrHandshakeRequired                = errors.Error("handshake required")
+	ErrServerStopping                   = errors.Error("server stopping")
+	ErrNotEnoughPeersForVoting          = errors.Error("not enough peers for the voting quorum")
+)
+
+func init() {
+	errcodes.Register(ErrDeclinedByVoting, 3001, "DECLINED_BY_VOTING", codes.FailedPrecondition)
+	errcodes.Register(ErrNoPeersForVoting, 3002, "NO_PEERS_FOR_VOTING", codes.Unavailable)
+	errcodes.Register(ErrInvalidPublishRequest, 3003, "INVALID_PUBLISH_REQUEST", codes.InvalidArgument)
+	errcodes.Register(ErrTransactionNotInChain, 3004, "TRANSACTION_NOT_IN_CHAIN", codes.NotFound)
+	errcodes.Register(ErrHandshakeRequired, 3006, "HANDSHAKE_REQUIRED", codes.Unauthenticated)
+	errcodes.Register(ErrServerStopping, 3007, "SERVER_STOPPING", codes.Unavailable)
+	errcodes.Register(ErrNotEnoughPeersForVoting, 3008, "NOT_ENOUGH_PEERS_FOR_VOTING", codes.Unavailable)
+}
+
+const (
+	seenCacheSize = 10000
+	gossipTimeout = 5 * time.Second
+
+	// The default and maximum number of transactions of a statement page.
+	defaultStatementLimit = 100
+	maxStatementLimit     = 1000
 )
 
 type Server struct {
-	ld  ledger.Ledger
-	con consensus.Consensus
-	dis peerdiscovery.Discoverer
-	lis net.Listener
+	ld         ledger.Ledger
+	events     *ledger.EventBus
+	con        consensus.Consensus
+	dis        peerdiscovery.Discoverer
+	lis        net.Listener
+	adminLis   net.Listener
+	adminHTTP  http.Handler
+	adminTLS   *tls.Config
+	localLis   net.Listener
+	seen       *hashCache
+	sess       *sessions
+	mtx        sync.Mutex
+	servers    []*grpc.Server
+	httpServer *adminHTTPServer
+	stopping   bool
+	done       chan struct{}
+	abort      chan struct{}
+	rounds     sync.WaitGroup
+	quorum     int
+	chainId    string
+	voting     map[string]Round
+	admin      admin.AdminServer
+	health     grpc_health_v1.HealthServer
+	logger     *log.Entry
+	limiter    *ratelimit.Limiter
+	authorizer *auth.Authorizer
+}
+
+// Round is a voting on a transfer in progress.
+type Round struct {
+	SendHash    string
+	ReceiveHash string
+	Started     time.Time
 }
 
+// New creates a server for the ledger ld, whose stored transactions are published to events.
 func New(ld ledger.Ledger,
+		events *ledger.EventBus,
 		con consensus.Consensus,
 		dis peerdiscovery.Discoverer,
 		lis net.Listener) *Server {
-	return &Server{ld: ld, con: con, dis: dis, lis: lis}
+	return &Server{ld: ld, events: events, con: con, dis: dis, lis: lis, seen: newHashCache(seenCacheSize),
+		sess: newSessions(), done: make(chan struct{}), abort: make(chan struct{}), quorum: 1,
+		voting: make(map[string]Round),
+		logger: logging.Component(log.StandardLogger(), "server")}
+}
+
+// SetAdmin sets the admin service served by Run along with the ledger and consensus services.
+func (s *Server) SetAdmin(admin admin.AdminServer) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.admin = admin
+}
+
+// SetQuorum sets the number of healthy peers a voting needs, 1 by default. Every one of them must vote, and a
+// voting never runs without peers, whatever the quorum.
+func (s *Server) SetQuorum(quorum int) {
+	s.quorum = quorum
+}
+
+// SetChainId sets the chain whose votes are counted by the votings, none by default.
+func (s *Server) SetChainId(chainId string) {
+	s.chainId = chainId
+}
+
+// SetLogger sets the logger of the server, used by Run to log the calls.
+func (s *Server) SetLogger(logger log.FieldLogger) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.logger = logging.Component(logger, "server")
+}
+
+// SetHealth sets the gRPC health service served by Run along with the ledger and consensus services.
+func (s *Server) SetHealth(health grpc_health_v1.HealthServer) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.health = health
+}
+
+// SetAuthorizer sets the authorizer of the API tokens of the calls served by Run. Without an authorizer the
+// calls need no token.
+func (s *Server) SetAuthorizer(authorizer *auth.Authorizer) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.authorizer = authorizer
+}
+
+// SetAdminListener sets the listener of the admin service.
+func (s *Server) SetAdminListener(lis net.Listener) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.adminLis = lis
+}
+
+// SetAdminHandler sets the HTTP handler, such as the metrics endpoint, served along the admin service by the admin
+// listener, with TLS if tlsConfig is not nil.
+func (s *Server) SetAdminHandler(handler http.Handler, tlsConfig *tls.Config) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.adminHTTP = handler
+	s.adminTLS = tlsConfig
+}
+
+// SetLocalListener sets the listener, such as a Unix domain socket, of the local clients. It serves the ledger and
+// admin services without TLS nor API tokens, as the clients are trusted by the permissions of the socket file.
+func (s *Server) SetLocalListener(lis net.Listener) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.localLis = lis
+}
+
+// Run serves the ledger and consensus services on the public listener, the admin service on the admin and local
+// listeners, if set, along with the admin HTTP handler on the admin listener, and the ledger and consensus services
+// on the local listener. The admin service is never served by the public listener. The given server options, such
+// as the TLS credentials, apply to the public and admin listeners. Run returns when the
+// server is stopped or when one of the listeners fails. Catalogued errors are returned to the clients as gRPC status
+// errors. Every call is counted and timed in the node metrics, and gets a request ID that is logged with it and sent
+// along the peer calls it makes. Calls whose API token may not call the method, if an authorizer is set, fail with
+// Unauthenticated or PermissionDenied, and calls over the limits of the limiter, if set, fail with ResourceExhausted.
+func (s *Server) Run(opts ...grpc.ServerOption) error {
+	s.mtx.Lock()
+	if s.stopping {
+		s.mtx.Unlock()
+		return ErrServerStopping
+	}
+
+	public := s.newGrpcServer(true, opts...)
+	consensus.RegisterConsensusServer(public, s)
+	ledger.RegisterLedgerServer(public, s)
+	listeners := map[*grpc.Server]net.Listener{public: s.lis}
+
+	var adminServer *grpc.Server
+	if s.adminLis != nil {
+		adminServer = s.newGrpcServer(true, opts...)
+		if s.admin != nil {
+			admin.RegisterAdminServer(adminServer, s.admin)
+		}
+		if s.health != nil {
+			grpc_health_v1.RegisterHealthServer(adminServer, s.health)
+		}
+		if s.adminHTTP != nil {
+			s.httpServer = newAdminHTTPServer(adminServer, s.adminHTTP, s.adminTLS)
+		} else {
+			listeners[adminServer] = s.adminLis
+		}
+	}
+	if s.localLis != nil {
+		local := s.newGrpcServer(false)
+		consensus.RegisterConsensusServer(local, s)
+		ledger.RegisterLedgerServer(local, s)
+		if s.admin != nil {
+			admin.RegisterAdminServer(local, s.admin)
+		}
+		listeners[local] = s.localLis
+	}
+
+	s.servers = make([]*grpc.Server, 0, len(listeners))
+	for grpcServer := range listeners {
+		if s.health != nil && grpcServer != adminServer {
+			grpc_health_v1.RegisterHealthServer(grpcServer, s.health)
+		}
+		s.servers = append(s.servers, grpcServer)
+	}
+	httpServer := s.httpServer
+	s.mtx.Unlock()
+
+	errs := make(chan error, len(listeners)+1)
+	for grpcServer, lis := range listeners {
+		go func(grpcServer *grpc.Server, lis net.Listener) {
+			errs <- grpcServer.Serve(lis)
+		}(grpcServer, lis)
+	}
+	if httpServer != nil {
+		go func() {
+			errs <- httpServer.Serve(s.adminLis)
+		}()
+	}
+	return <-errs
+}
+
+// Stop stops the server gracefully: new calls are refused, the subscriptions are ended and the in-flight calls
+// and consensus rounds are waited for. If ctx is done first, the remaining calls and rounds are cancelled and
+// ctx.Err() is returned once they have ended, so the ledger is not written anymore when Stop returns.
+func (s *Server) Stop(ctx context.Context) error {
+	s.mtx.Lock()
+	if !s.stopping {
+		s.stopping = true
+		close(s.done)
+	}
+	grpcServers := s.servers
+	httpServer := s.httpServer
+	s.mtx.Unlock()
+
+	if grpcServers == nil {
+		for _, lis := range []net.Listener{s.lis, s.adminLis, s.localLis} {
+			if lis != nil {
+				lis.Close()
+			}
+		}
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		for _, grpcServer := range grpcServers {
+			wg.Add(1)
+			go func(grpcServer *grpc.Server) {
+				defer wg.Done()
+				grpcServer.GracefulStop()
+			}(grpcServer)
+		}
+		if httpServer != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				httpServer.Shutdown(context.Background())
+			}()
+		}
+		wg.Wait()
+		s.rounds.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.mtx.Lock()
+		select {
+		case <-s.abort:
+		default:
+			close(s.abort)
+		}
+		s.mtx.Unlock()
+		for _, grpcServer := range grpcServers {
+			grpcServer.Stop()
+		}
+		if httpServer != nil {
+			httpServer.Close()
+		}
+		<-stopped
+		return ctx.Err()
+	}
 }
 
-func (s *Server) Run() error {
-	grpcServer := grpc.NewServer()
-	consensus.RegisterConsensusServer(grpcServer, s)
-	ledger.RegisterLedgerServer(grpcServer, s)
-	return grpcServer.Serve(s.lis)
+// newGrpcServer creates a gRPC server with the interceptors of the server. The API tokens are only checked by
+// the servers of the network listeners, the local clients being trusted.
+func (s *Server) newGrpcServer(network bool, opts ...grpc.ServerOption) *grpc.Server {
+	if s.limiter != nil {
+		opts = append(opts, s.limiter.ServerOptions()...)
+	}
+	unary := []grpc.UnaryServerInterceptor{metricsUnaryInterceptor, logging.UnaryServerInterceptor(s.logger),
+		errcodes.UnaryServerInterceptor, s.sess.authenticate}
+	stream := []grpc.StreamServerInterceptor{metricsStreamInterceptor, logging.StreamServerInterceptor(s.logger),
+		errcodes.StreamServerInterceptor}
+	if s.authorizer != nil && network {
+		unary = append(unary, s.authorizer.UnaryServerInterceptor)
+		stream = append(stream, s.authorizer.StreamServerInterceptor)
+	}
+	opts = append(opts,
+		grpc.UnaryInterceptor(chainUnary(append(unary, s.limitUnary)...)),
+		grpc.StreamInterceptor(chainStream(append(stream, s.limitStream)...)))
+	return grpc.NewServer(opts...)
 }
 
 func (s *Server) Register(ctx context.Context, request *ledger.RegisterRequest) (*ledger.RegisterResult, error) {
+	if !s.startRound() {
+		return nil, ErrServerStopping
+	}
+	defer s.rounds.Done()
+	ctx, cancel := s.roundContext(ctx)
+	defer cancel()
+
 	err := s.ld.Verify(request.SendTx, request.ReceiveTx)
 	if err != nil {
 		return nil, err
 	}
 
+	s.publishPending(ctx, request)
+
+	done := s.startVoting(request)
 	err = s.resolve(ctx, request)
+	done()
 	if err != nil {
 		return nil, err
 	}
@@ -67,16 +364,108 @@ func (s *Server) GetTransaction(ctx context.Context, request *ledger.GetTransact
 	return &ledger.GetTransactionResult{Tx: tx}, nil
 }
 
+// GetAddressStatement returns a page of the statement selected by request, of up to maxStatementLimit
+// transactions, and the hash the next page starts at.
 func (s *Server) GetAddressStatement(ctx context.Context, request *ledger.GetAddressStatementRequest) (*ledger.GetAddressStatementResult, error) {
-	txs, err := s.ld.GetAddressStatement(request.Address)
+	query := ledger.NewStatementQuery(request)
+	query.Limit = defaultStatementLimit
+	if request.Limit > 0 {
+		query.Limit = int(math.Min(float64(request.Limit), maxStatementLimit))
+	}
+
+	txs, next, err := s.ld.GetAddressStatementPage(request.Address, query)
 	if err != nil {
 		return nil, err
 	}
-	return &ledger.GetAddressStatementResult{Txs: txs}, nil
+	return &ledger.GetAddressStatementResult{Txs: txs, NextHash: next}, nil
 }
 
+// StreamAddressStatement streams the statement selected by request, up to request.Limit transactions or whole if
+// it is 0. The statement is read in pages, so exporting long chains does not hold them in memory.
+func (s *Server) StreamAddressStatement(request *ledger.GetAddressStatementRequest,
+	stream ledger.Ledger_StreamAddressStatementServer) error {
+	query := ledger.NewStatementQuery(request)
+	limit, sent := int(request.Limit), 0
+	for {
+		query.Limit = maxStatementLimit
+		if limit > 0 && limit-sent < maxStatementLimit {
+			query.Limit = limit - sent
+		}
+
+		txs, next, err := s.ld.GetAddressStatementPage(request.Address, query)
+		if err != nil {
+			return err
+		}
+		for _, tx := range txs {
+			err = stream.Send(tx)
+			if err != nil {
+				return err
+			}
+		}
+
+		sent += len(txs)
+		if next == "" || (limit > 0 && sent == limit) {
+			return nil
+		}
+		query.StartHash, query.StartHeight = next, 0
+
+		select {
+		case <-s.done:
+			return ErrServerStopping
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		default:
+		}
+	}
+}
+
+// GetBalanceAt returns the balance of the address after the transaction at request.Height or, if it is 0, after
+// the transactions older than request.Timestamp. Without both, it returns the current balance.
+func (s *Server) GetBalanceAt(ctx context.Context, request *ledger.GetBalanceAtRequest) (*ledger.GetBalanceAtResult, error) {
+	balance, err := s.ld.GetBalanceAt(request.Address, request.Timestamp, request.Height)
+	if err != nil {
+		return nil, err
+	}
+	return &ledger.GetBalanceAtResult{Balance: balance}, nil
+}
+
+// GetBalanceHistory returns the balances of the address at the end of every bucket of the requested range, which
+// ends now if request.ToTimestamp is 0.
+func (s *Server) GetBalanceHistory(ctx context.Context, request *ledger.GetBalanceHistoryRequest) (*ledger.GetBalanceHistoryResult, error) {
+	to := request.ToTimestamp
+	if to == 0 {
+		to = time.Now().UnixNano()
+	}
+	balances, err := s.ld.GetBalanceHistory(request.Address, request.FromTimestamp, to, request.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	return &ledger.GetBalanceHistoryResult{Balances: balances}, nil
+}
+
+// GetIncoming returns the send transactions to the address, only the ones not received yet if request.Pending
+// is set.
+func (s *Server) GetIncoming(ctx context.Context, request *ledger.GetIncomingRequest) (*ledger.GetIncomingResult, error) {
+	txs, err := s.ld.GetIncoming(request.Address, request.Pending)
+	if err != nil {
+		return nil, err
+	}
+	return &ledger.GetIncomingResult{Txs: txs}, nil
+}
+
+// GetReceiveForSend returns the transaction that received the send transaction request.Hash, if any.
+func (s *Server) GetReceiveForSend(ctx context.Context, request *ledger.GetReceiveForSendRequest) (*ledger.GetReceiveForSendResult, error) {
+	tx, err := s.ld.GetReceiveForSend(request.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return &ledger.GetReceiveForSendResult{Tx: tx}, nil
+}
+
+// VerifyTransaction verifies the transaction as a new one or, if request.Stored is set, as one already in the
+// ledger.
 func (s *Server) VerifyTransaction(ctx context.Context, request *ledger.VerifyTransactionRequest) (*ledger.VerifyTransactionResult, error) {
-	err := s.ld.VerifyTransaction(request.Tx, true)
+	err := s.ld.VerifyTransaction(request.Tx, !request.Stored)
 	if err != nil {
 		return nil, err
 	}
@@ -96,10 +485,307 @@ func (s *Server) Vote(ctx context.Context, request *consensus.VoteRequest) (*con
 }
 
 func (s *Server) Accept(ctx context.Context, request *consensus.AcceptRequest) (*consensus.AcceptResult, error) {
-	return s.con.Accept(request)
+	if !s.startRound() {
+		return nil, ErrServerStopping
+	}
+	defer s.rounds.Done()
+
+	result, err := s.con.Accept(request)
+	if err != nil {
+		s.transferLogger(ctx, request.SendTx, request.ReceiveTx).WithField(logging.PeerField, peerName(ctx)).
+			Warnf("Rejected transactions accepted by peer: %s", err)
+	}
+	return result, err
+}
+
+func (s *Server) Publish(ctx context.Context, request *consensus.PublishRequest) (*consensus.PublishResult, error) {
+	if request.SendTx == nil || request.ReceiveTx == nil {
+		return nil, ErrInvalidPublishRequest
+	}
+	if !s.startRound() {
+		return nil, ErrServerStopping
+	}
+	defer s.rounds.Done()
+
+	hash := request.ReceiveTx.Hash
+	if !s.seen.Add(hash) {
+		return &consensus.PublishResult{Known: true}, nil
+	}
+
+	err := s.acceptPublished(request)
+	if err != nil {
+		s.seen.Remove(hash)
+		s.transferLogger(ctx, request.SendTx, request.ReceiveTx).WithField(logging.PeerField, peerName(ctx)).
+			Warnf("Rejected transaction published by peer: %s", err)
+		return nil, err
+	}
+
+	peers, err := s.dis.Peers()
+	if err != nil {
+		return nil, err
+	}
+	s.gossip(ctx, peers, request)
+
+	return &consensus.PublishResult{}, nil
+}
+
+func (s *Server) GetFrontiers(ctx context.Context, request *consensus.GetFrontiersRequest) (*consensus.GetFrontiersResult, error) {
+	frontiers, err := s.ld.GetFrontiers()
+	if err != nil {
+		return nil, err
+	}
+
+	for addr, hash := range request.Frontiers {
+		if frontiers[addr] == hash {
+			delete(frontiers, addr)
+		}
+	}
+	return &consensus.GetFrontiersResult{Frontiers: frontiers}, nil
+}
+
+// GetChain returns a page of the address chain after request.After, or from its open transaction, of up to
+// maxStatementLimit transactions, and the hash to pass as After for the next page, empty on the last one.
+func (s *Server) GetChain(ctx context.Context, request *consensus.GetChainRequest) (*consensus.GetChainResult, error) {
+	limit := defaultStatementLimit
+	if request.Limit > 0 {
+		limit = int(math.Min(float64(request.Limit), maxStatementLimit))
+	}
+
+	// The page starts at After itself, which is left out of it.
+	query := ledger.StatementQuery{StartHash: request.After, Limit: limit}
+	if request.After != "" {
+		query.Limit++
+	}
+	txs, next, err := s.ld.GetAddressStatementPage(request.Address, query)
+	if err == ledger.ErrStatementStartNotInChain {
+		return nil, ErrTransactionNotInChain
+	}
+	if err != nil {
+		return nil, err
+	}
+	if request.After != "" {
+		if len(txs) == 0 || txs[0].Hash != request.After {
+			return nil, ErrTransactionNotInChain
+		}
+		txs = txs[1:]
+	}
+
+	result := &consensus.GetChainResult{Txs: txs}
+	if next != "" && len(txs) > 0 {
+		result.NextHash = txs[len(txs)-1].Hash
+	}
+	return result, nil
+}
+
+// GetPeers returns the known peers. The address the caller is reachable at is only taken from a peer that ran a
+// handshake, and is checked by the discoverer in the background, so the list is returned either way.
+func (s *Server) GetPeers(ctx context.Context, request *consensus.GetPeersRequest) (*consensus.GetPeersResult, error) {
+	if request.From != "" && PeerIdentity(ctx) != nil {
+		err := s.dis.AddPeer(request.From)
+		if err != nil {
+			logging.FromContext(ctx, s.logger).WithField(logging.PeerField, request.From).
+				Warnf("Failed to add peer: %s", err)
+		}
+	}
+
+	peers, err := s.dis.KnownPeers()
+	if err != nil {
+		return nil, err
+	}
+	return &consensus.GetPeersResult{Peers: peers}, nil
+}
+
+func (s *Server) Ping(ctx context.Context, request *consensus.PingRequest) (*consensus.PingResult, error) {
+	return &consensus.PingResult{Timestamp: request.Timestamp}, nil
+}
+
+func (s *Server) Handshake(ctx context.Context, request *consensus.HandshakeRequest) (*consensus.HandshakeResult, error) {
+	result, err := s.con.Handshake(request)
+	if err != nil {
+		return nil, err
+	}
+	s.sess.Add(ctx, result.Session, request.Info)
+	return result, nil
+}
+
+// IsVoter tells if pubKey is the node key of a peer that ran a handshake with the server.
+func (s *Server) IsVoter(pubKey []byte) bool {
+	return s.sess.IsVoter(pubKey)
+}
+
+// GetVotes returns the votes that confirmed the transfer of the transaction request.Hash, none if they are not
+// known.
+func (s *Server) GetVotes(ctx context.Context, request *consensus.GetVotesRequest) (*consensus.GetVotesResult, error) {
+	confirmation, err := s.con.GetVotes(request.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return &consensus.GetVotesResult{Confirmation: confirmation}, nil
+}
+
+func (s *Server) acceptPublished(request *consensus.PublishRequest) error {
+	tx, err := s.ld.GetTransaction(request.ReceiveTx.Hash)
+	if err != nil {
+		return err
+	}
+	if tx != nil {
+		return nil
+	}
+
+	_, err = s.con.Accept(&consensus.AcceptRequest{SendTx: request.SendTx, ReceiveTx: request.ReceiveTx,
+		Votes: request.Votes})
+	return err
+}
+
+// gossip forwards a confirmed transfer to the given peers without waiting for them. Each peer
+// verifies it and forwards it to its own peers, so the transfer floods the whole network. The request ID of ctx
+// is sent along, so the transfer can be followed in the logs of every node.
+func (s *Server) gossip(ctx context.Context, peers []consensus.ConsensusClient, request *consensus.PublishRequest) {
+	id := logging.RequestId(ctx)
+	for _, peer := range peers {
+		if !s.startRound() {
+			return
+		}
+		go func(peer consensus.ConsensusClient) {
+			defer s.rounds.Done()
+			ctx, cancelRound := s.roundContext(logging.WithRequestId(context.Background(), id))
+			defer cancelRound()
+			ctx, cancel := context.WithTimeout(ctx, gossipTimeout)
+			defer cancel()
+			_, err := peer.Publish(ctx, request)
+			if err != nil {
+				s.transferLogger(ctx, request.SendTx, request.ReceiveTx).Debugf("Gossip failed: %s", err)
+			}
+		}(peer)
+	}
+}
+
+// transferLogger returns the logger with the request ID of ctx and the transactions of a transfer, which may be
+// missing from an invalid request.
+func (s *Server) transferLogger(ctx context.Context, sendTx, receiveTx *ledger.Transaction) *log.Entry {
+	fields := log.Fields{}
+	if sendTx != nil {
+		fields[logging.SendTxField] = sendTx.Hash
+	}
+	if receiveTx != nil {
+		fields[logging.TxField] = receiveTx.Hash
+		fields[logging.AddressField] = receiveTx.Address
+	}
+	return logging.FromContext(ctx, s.logger).WithFields(fields)
+}
+
+// Rounds returns the votings in progress, oldest first.
+func (s *Server) Rounds() []Round {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	rounds := make([]Round, 0, len(s.voting))
+	for _, round := range s.voting {
+		rounds = append(rounds, round)
+	}
+	sort.Slice(rounds, func(i, j int) bool { return rounds[i].Started.Before(rounds[j].Started) })
+	return rounds
+}
+
+// startVoting records the voting on a transfer until the returned function is called.
+func (s *Server) startVoting(request *ledger.RegisterRequest) func() {
+	round := Round{SendHash: request.SendTx.Hash, ReceiveHash: request.ReceiveTx.Hash, Started: time.Now()}
+	s.mtx.Lock()
+	s.voting[round.ReceiveHash] = round
+	s.mtx.Unlock()
+
+	return func() {
+		s.mtx.Lock()
+		delete(s.voting, round.ReceiveHash)
+		s.mtx.Unlock()
+	}
+}
+
+func votingResult(err error) string {
+	switch err {
+	case nil:
+		return "accepted"
+	case ErrDeclinedByVoting:
+		return "declined"
+	default:
+		return "failed"
+	}
+}
+
+// roundContext returns the context of a round started by the call ctx, which is also cancelled when Stop gives up
+// waiting for the rounds.
+func (s *Server) roundContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-s.abort:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// startRound registers an in-flight consensus round or ledger write, which Stop waits for. It returns false if the
+// server is stopping.
+func (s *Server) startRound() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.stopping {
+		return false
+	}
+	s.rounds.Add(1)
+	return true
+}
+
+// chainUnary chains unary interceptors, the first one being the outermost.
+func chainUnary(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], handler
+			handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return handler(ctx, req)
+	}
 }
 
-func (s *Server) resolve(ctx context.Context, request *ledger.RegisterRequest) error {
+// chainStream chains stream interceptors, the first one being the outermost.
+func chainStream(interceptors ...grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], handler
+			handler = func(srv interface{}, ss grpc.ServerStream) error {
+				return interceptor(srv, ss, info, next)
+			}
+		}
+		return handler(srv, ss)
+	}
+}
+
+func peerName(ctx context.Context) string {
+	identity := PeerIdentity(ctx)
+	if identity == nil {
+		return "unknown peer"
+	}
+	return hex.EncodeToString(identity.PubKey)
+}
+
+func (s *Server) resolve(ctx context.Context, request *ledger.RegisterRequest) (err error) {
+	logger := s.transferLogger(ctx, request.SendTx, request.ReceiveTx)
+	start := time.Now()
+	defer func() {
+		votingDuration.Since(start, votingResult(err))
+		if err != nil {
+			logger.Infof("Transfer not registered: %s", err)
+		}
+	}()
+
 	peers, err := s.dis.Peers()
 	if err != nil {
 		return err
@@ -108,20 +794,45 @@ func (s *Server) resolve(ctx context.Context, request *ledger.RegisterRequest) e
 	if len(peers) == 0 {
 		return ErrNoPeersForVoting
 	}
+	if len(peers) < s.quorum {
+		return ErrNotEnoughPeersForVoting
+	}
 
+	// The peers the discoverer found unhealthy before the round are already left out, so every peer must
+	// send a valid vote: the voting fails if a peer fails to vote or sends an invalid vote.
 	nok := 0
+	var lastErr error
+	voters := make([]consensus.ConsensusClient, 0)
 	votes := make([]*consensus.Vote, 0)
 	for _, peer := range peers {
 		result, err := peer.Vote(ctx, &consensus.VoteRequest{SendTx: request.SendTx, ReceiveTx: request.ReceiveTx})
 		if err != nil {
-			return err
+			voteOutcomes.Inc("error")
+			logger.Debugf("Vote failed: %s", err)
+			lastErr = err
+			continue
+		}
+		if reason := s.checkVote(peer, result.Vote, request); reason != "" {
+			voteOutcomes.Inc("invalid")
+			s.dis.Report(peer, reason)
+			lastErr = ErrInvalidVote
+			continue
 		}
-		if !result.Vote.Ok {
+		if result.Vote.Ok {
+			voteOutcomes.Inc("accept")
+		} else {
+			voteOutcomes.Inc("reject")
+			logger.Debugf("Vote against transfer: %s", result.Vote.Reason)
 			nok++
 		}
+		voters = append(voters, peer)
 		votes = append(votes, result.Vote)
 	}
 
+	if len(votes) < len(peers) {
+		return lastErr
+	}
+
 	if nok > 0 {
 		return ErrDeclinedByVoting
 	}
@@ -131,9 +842,38 @@ func (s *Server) resolve(ctx context.Context, request *ledger.RegisterRequest) e
 		return err
 	}
 
+	err = s.con.Confirm(request.SendTx, request.ReceiveTx, votes)
+	if err != nil {
+		logger.Warnf("Failed to keep the votes of the transfer: %s", err)
+	}
+
 	accept := &consensus.AcceptRequest{SendTx: request.SendTx, ReceiveTx: request.ReceiveTx, Votes: votes}
-	for _, peer := range peers {
+	for _, peer := range voters {
 		_, err = peer.Accept(ctx, accept)
+		if err != nil {
+			logger.Warnf("Peer did not accept the transfer: %s", err)
+		}
 	}
+
+	logger.Infof("Transfer registered with %d votes", len(votes))
+
+	s.seen.Add(request.ReceiveTx.Hash)
+	s.gossip(ctx, peers, &consensus.PublishRequest{SendTx: request.SendTx, ReceiveTx: request.ReceiveTx, Votes: votes})
 	return nil
 }
+
+// checkVote returns why vote, returned by peer, is not a vote of the peer on the transfer of request in the chain,
+// empty if it is. A vote of another node relayed by the peer, or on another chain, is not counted.
+func (s *Server) checkVote(peer consensus.ConsensusClient, vote *consensus.Vote, request *ledger.RegisterRequest) string {
+	if vote == nil || !vote.VerifySignature() {
+		return "invalid vote signature"
+	}
+	if !vote.IsFor(s.chainId, request.SendTx, request.ReceiveTx) {
+		return "vote for another transfer"
+	}
+	identity := s.dis.PeerIdentity(peer)
+	if identity == nil || !bytes.Equal(identity.PubKey, vote.PubKey) {
+		return "vote not signed by the peer"
+	}
+	return ""
+}
@@ -1,15 +1,35 @@
 package server_test
 
 import (
+	"bytes"
 	"github.com/golang/mock/gomock"
 	"github.com/msaldanha/realChain/errors"
 	"github.com/msaldanha/realChain/address"
+	"github.com/msaldanha/realChain/admin"
+	"github.com/msaldanha/realChain/auth"
+	"github.com/msaldanha/realChain/config"
 	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/keyvaluestore"
 	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/logging"
+	"github.com/msaldanha/realChain/metrics"
+	"github.com/msaldanha/realChain/ratelimit"
 	"github.com/msaldanha/realChain/server"
 	"github.com/msaldanha/realChain/tests"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
 )
 
 var _ = Describe("Server", func() {
@@ -20,6 +40,7 @@ var _ = Describe("Server", func() {
 	var dis *tests.MockDiscoverer
 	var lis *tests.MockListener
 	var conCli *tests.MockConsensusClient
+	var events *ledger.EventBus
 	var srv *server.Server
 	var sendTx *ledger.Transaction
 	var receiveTx *ledger.Transaction
@@ -32,7 +53,10 @@ var _ = Describe("Server", func() {
 		lis = tests.NewMockListener(mockCtrl)
 		conCli = tests.NewMockConsensusClient(mockCtrl)
 
-		srv = server.New(ld, con, dis, lis)
+		events = ledger.NewEventBus(keyvaluestore.NewMemoryKeyValueStore())
+		srv = server.New(ld, events, con, dis, lis)
+		srv.SetChainId("test")
+		dis.EXPECT().PeerIdentity(gomock.Any()).Return(tests.PeerIdentity).AnyTimes()
 
 		genesisTx, genesisAddr := tests.CreateGenesisTransaction(1000)
 
@@ -50,11 +74,16 @@ var _ = Describe("Server", func() {
 		defer mockCtrl.Finish()
 
 		ld.EXPECT().Register(sendTx, receiveTx)
+		con.EXPECT().Confirm(sendTx, receiveTx, gomock.Any())
 		ld.EXPECT().Verify(sendTx, receiveTx)
 
-		conCli.EXPECT().Vote(gomock.Any(), gomock.Any(), gomock.Any()).Return(&consensus.VoteResult{Vote:&consensus.Vote{Ok:true}}, nil)
+		conCli.EXPECT().Vote(gomock.Any(), gomock.Any(), gomock.Any()).Return(&consensus.VoteResult{Vote: tests.CreatePeerVote(true, sendTx, receiveTx)}, nil)
 		conCli.EXPECT().Accept(gomock.Any(), gomock.Any(), gomock.Any())
 
+		published := make(chan *consensus.PublishRequest, 1)
+		conCli.EXPECT().Publish(gomock.Any(), gomock.Any()).
+			Do(func(ctx interface{}, request *consensus.PublishRequest) { published <- request })
+
 		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{conCli}, nil)
 
 		request := &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx}
@@ -63,6 +92,40 @@ var _ = Describe("Server", func() {
 
 		Expect(err).To(BeNil())
 		Expect(result).NotTo(BeNil())
+
+		var publish *consensus.PublishRequest
+		Eventually(published).Should(Receive(&publish))
+		Expect(publish.SendTx).To(Equal(sendTx))
+		Expect(publish.ReceiveTx).To(Equal(receiveTx))
+		Expect(len(publish.Votes)).To(Equal(1))
+	})
+
+	It("Should send the request ID along the votings and the gossip", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().Register(sendTx, receiveTx)
+		con.EXPECT().Confirm(sendTx, receiveTx, gomock.Any())
+		ld.EXPECT().Verify(sendTx, receiveTx)
+
+		requestIds := make(chan string, 3)
+		conCli.EXPECT().Vote(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, request *consensus.VoteRequest) (*consensus.VoteResult, error) {
+				requestIds <- logging.RequestId(ctx)
+				return &consensus.VoteResult{Vote: tests.CreatePeerVote(true, sendTx, receiveTx)}, nil
+			})
+		conCli.EXPECT().Accept(gomock.Any(), gomock.Any()).
+			Do(func(ctx context.Context, request *consensus.AcceptRequest) { requestIds <- logging.RequestId(ctx) })
+		conCli.EXPECT().Publish(gomock.Any(), gomock.Any()).
+			Do(func(ctx context.Context, request *consensus.PublishRequest) { requestIds <- logging.RequestId(ctx) })
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{conCli}, nil)
+
+		ctx := logging.WithRequestId(context.Background(), "request-1")
+		_, err := srv.Register(ctx, &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx})
+		Expect(err).To(BeNil())
+
+		for i := 0; i < 3; i++ {
+			Eventually(requestIds).Should(Receive(Equal("request-1")))
+		}
 	})
 
 	It("Should handle error from ledger register transactions", func() {
@@ -71,7 +134,7 @@ var _ = Describe("Server", func() {
 		ld.EXPECT().Register(sendTx, receiveTx).Return(ledger.ErrInvalidReceiveTransaction)
 		ld.EXPECT().Verify(sendTx, receiveTx)
 
-		conCli.EXPECT().Vote(gomock.Any(), gomock.Any(), gomock.Any()).Return(&consensus.VoteResult{Vote:&consensus.Vote{Ok:true}}, nil)
+		conCli.EXPECT().Vote(gomock.Any(), gomock.Any(), gomock.Any()).Return(&consensus.VoteResult{Vote: tests.CreatePeerVote(true, sendTx, receiveTx)}, nil)
 
 		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{conCli}, nil)
 
@@ -113,6 +176,44 @@ var _ = Describe("Server", func() {
 		Expect(err).To(Equal(server.ErrNoPeersForVoting))
 	})
 
+	It("Should return error if there are fewer peers than the quorum", func() {
+		defer mockCtrl.Finish()
+
+		srv.SetQuorum(2)
+		ld.EXPECT().Verify(sendTx, receiveTx)
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{conCli}, nil)
+
+		request := &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx}
+
+		result, err := srv.Register(nil, request)
+
+		Expect(result).To(BeNil())
+		Expect(err).To(Equal(server.ErrNotEnoughPeersForVoting))
+	})
+
+	It("Should publish the transactions submitted for voting as pending", func() {
+		defer mockCtrl.Finish()
+
+		sub, err := events.Subscribe(10)
+		Expect(err).To(BeNil())
+		defer sub.Close()
+
+		ld.EXPECT().Verify(sendTx, receiveTx)
+
+		peers := [0]consensus.ConsensusClient{}
+		dis.EXPECT().Peers().Return(peers[:], nil)
+
+		_, err = srv.Register(nil, &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx})
+		Expect(err).To(Equal(server.ErrNoPeersForVoting))
+
+		var event *ledger.Event
+		Expect(sub.Events()).To(Receive(&event))
+		Expect(event.Tx).To(Equal(sendTx))
+		Expect(event.Type).To(Equal(ledger.BlockPending))
+		Expect(sub.Events()).To(Receive(&event))
+		Expect(event.Tx).To(Equal(receiveTx))
+	})
+
 	It("Should return error if conflict resolution fails due to error", func() {
 		defer mockCtrl.Finish()
 
@@ -131,12 +232,202 @@ var _ = Describe("Server", func() {
 		Expect(err).To(Equal(expectedError))
 	})
 
+	It("Should fail the voting if a healthy peer fails to vote", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().Verify(sendTx, receiveTx)
+
+		someErr := errors.Error("some error")
+		deadCli := tests.NewMockConsensusClient(mockCtrl)
+		deadCli.EXPECT().Vote(gomock.Any(), gomock.Any()).Return(nil, someErr)
+
+		conCli.EXPECT().Vote(gomock.Any(), gomock.Any()).Return(&consensus.VoteResult{Vote: tests.CreatePeerVote(true, sendTx, receiveTx)}, nil)
+
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{deadCli, conCli}, nil)
+
+		request := &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx}
+
+		result, err := srv.Register(nil, request)
+
+		Expect(result).To(BeNil())
+		Expect(err).To(Equal(someErr))
+	})
+
+	It("Should report the peers that send invalid votes", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().Verify(sendTx, receiveTx)
+
+		vote := tests.CreatePeerVote(true, sendTx, receiveTx)
+		vote.Ok = false
+		conCli.EXPECT().Vote(gomock.Any(), gomock.Any()).Return(&consensus.VoteResult{Vote: vote}, nil)
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{conCli}, nil)
+		dis.EXPECT().Report(conCli, gomock.Any())
+
+		request := &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx}
+
+		result, err := srv.Register(nil, request)
+
+		Expect(result).To(BeNil())
+		Expect(err).To(Equal(server.ErrInvalidVote))
+	})
+
+	It("Should report the peers that send votes on another transfer", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().Verify(sendTx, receiveTx)
+
+		vote := tests.CreatePeerVote(true, receiveTx, sendTx)
+		conCli.EXPECT().Vote(gomock.Any(), gomock.Any()).Return(&consensus.VoteResult{Vote: vote}, nil)
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{conCli}, nil)
+		dis.EXPECT().Report(conCli, gomock.Any())
+
+		request := &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx}
+
+		result, err := srv.Register(nil, request)
+
+		Expect(result).To(BeNil())
+		Expect(err).To(Equal(server.ErrInvalidVote))
+	})
+
+	It("Should report the peers that relay votes signed by other nodes", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().Verify(sendTx, receiveTx)
+
+		vote := tests.CreateVote(true, sendTx, receiveTx)
+		conCli.EXPECT().Vote(gomock.Any(), gomock.Any()).Return(&consensus.VoteResult{Vote: vote}, nil)
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{conCli}, nil)
+		dis.EXPECT().Report(conCli, "vote not signed by the peer")
+
+		request := &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx}
+
+		result, err := srv.Register(nil, request)
+
+		Expect(result).To(BeNil())
+		Expect(err).To(Equal(server.ErrInvalidVote))
+	})
+
+	It("Should report the peers that send votes on another chain", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().Verify(sendTx, receiveTx)
+
+		vote := tests.CreatePeerVote(true, sendTx, receiveTx)
+		vote.ChainId = "otherchain"
+		Expect(vote.Sign(tests.PeerKeys.ToEcdsaPrivateKey())).To(BeNil())
+		conCli.EXPECT().Vote(gomock.Any(), gomock.Any()).Return(&consensus.VoteResult{Vote: vote}, nil)
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{conCli}, nil)
+		dis.EXPECT().Report(conCli, "vote for another transfer")
+
+		request := &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx}
+
+		result, err := srv.Register(nil, request)
+
+		Expect(result).To(BeNil())
+		Expect(err).To(Equal(server.ErrInvalidVote))
+	})
+
+	It("Should accept a session only from the connection that ran its handshake", func() {
+		defer mockCtrl.Finish()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		srv = server.New(ld, events, con, dis, listener)
+		go srv.Run()
+		defer srv.Stop(context.Background())
+
+		info := &consensus.NodeInfo{PubKey: []byte("key")}
+		con.EXPECT().Handshake(gomock.Any()).Return(&consensus.HandshakeResult{Session: "session"}, nil)
+		conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+		Expect(err).To(BeNil())
+		defer conn.Close()
+		_, err = consensus.NewConsensusClient(conn).Handshake(context.Background(),
+			&consensus.HandshakeRequest{Info: info})
+		Expect(err).To(BeNil())
+		Expect(srv.IsVoter(info.PubKey)).To(BeTrue())
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), consensus.SessionKey, "session")
+		request := &consensus.VoteRequest{SendTx: sendTx, ReceiveTx: receiveTx}
+		con.EXPECT().Vote(gomock.Any()).Return(&consensus.VoteResult{}, nil)
+		_, err = consensus.NewConsensusClient(conn).Vote(ctx, request)
+		Expect(err).To(BeNil())
+
+		otherConn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+		Expect(err).To(BeNil())
+		defer otherConn.Close()
+		_, err = consensus.NewConsensusClient(otherConn).Vote(ctx, request)
+		Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+	})
+
+	It("Should serve the chains only to the peers that ran a handshake", func() {
+		defer mockCtrl.Finish()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		srv = server.New(ld, events, con, dis, listener)
+		go srv.Run()
+		defer srv.Stop(context.Background())
+
+		conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+		Expect(err).To(BeNil())
+		defer conn.Close()
+		client := consensus.NewConsensusClient(conn)
+		_, err = client.GetFrontiers(context.Background(), &consensus.GetFrontiersRequest{})
+		Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+		_, err = client.GetChain(context.Background(), &consensus.GetChainRequest{Address: sendTx.Address})
+		Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+
+		con.EXPECT().Handshake(gomock.Any()).Return(&consensus.HandshakeResult{Session: "session"}, nil)
+		_, err = client.Handshake(context.Background(),
+			&consensus.HandshakeRequest{Info: &consensus.NodeInfo{PubKey: []byte("key")}})
+		Expect(err).To(BeNil())
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), consensus.SessionKey, "session")
+		ld.EXPECT().GetAddressStatementPage(sendTx.Address, ledger.StatementQuery{Limit: 100}).
+			Return([]*ledger.Transaction{sendTx}, "", nil)
+		result, err := client.GetChain(ctx, &consensus.GetChainRequest{Address: sendTx.Address})
+		Expect(err).To(BeNil())
+		Expect(len(result.Txs)).To(Equal(1))
+	})
+
+	It("Should answer handshakes with consensus", func() {
+		defer mockCtrl.Finish()
+
+		request := &consensus.HandshakeRequest{Info: &consensus.NodeInfo{PubKey: []byte("key")}}
+		expected := &consensus.HandshakeResult{Session: "session"}
+		con.EXPECT().Handshake(request).Return(expected, nil)
+
+		result, err := srv.Handshake(nil, request)
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(expected))
+	})
+
+	It("Should return error if consensus rejects the handshake", func() {
+		defer mockCtrl.Finish()
+
+		request := &consensus.HandshakeRequest{}
+		con.EXPECT().Handshake(request).Return(nil, consensus.ErrInvalidHandshake)
+
+		result, err := srv.Handshake(nil, request)
+		Expect(result).To(BeNil())
+		Expect(err).To(Equal(consensus.ErrInvalidHandshake))
+	})
+
+	It("Should answer pings", func() {
+		defer mockCtrl.Finish()
+
+		result, err := srv.Ping(nil, &consensus.PingRequest{Timestamp: 10})
+		Expect(err).To(BeNil())
+		Expect(result.Timestamp).To(Equal(int64(10)))
+	})
+
 	It("Should return ErrDeclinedByVoting error if conflict resolution does not approves the transaction", func() {
 		defer mockCtrl.Finish()
 
 		ld.EXPECT().Verify(sendTx, receiveTx)
 
-		conCli.EXPECT().Vote(gomock.Any(), gomock.Any()).Return(&consensus.VoteResult{Vote: &consensus.Vote{Ok: false}}, nil)
+		conCli.EXPECT().Vote(gomock.Any(), gomock.Any()).Return(&consensus.VoteResult{Vote: tests.CreatePeerVote(false, sendTx, receiveTx)}, nil)
 		peers := [1]consensus.ConsensusClient{conCli}
 		dis.EXPECT().Peers().Return(peers[:], nil)
 
@@ -222,6 +513,31 @@ var _ = Describe("Server", func() {
 		Expect(err).To(Equal(ledger.ErrInvalidTransactionHash))
 	})
 
+	It("Should verify transactions already in the ledger", func() {
+		defer mockCtrl.Finish()
+
+		request := &ledger.VerifyTransactionRequest{Tx: sendTx, Stored: true}
+		ld.EXPECT().VerifyTransaction(request.Tx, false).Return(nil)
+
+		result, err := srv.VerifyTransaction(nil, request)
+
+		Expect(result).NotTo(BeNil())
+		Expect(err).To(BeNil())
+	})
+
+	It("Should return the votes of the transfers", func() {
+		defer mockCtrl.Finish()
+
+		confirmation := &consensus.Confirmation{SendHash: sendTx.Hash, ReceiveHash: receiveTx.Hash,
+			Votes: []*consensus.Vote{tests.CreatePeerVote(true, sendTx, receiveTx)}}
+		con.EXPECT().GetVotes(sendTx.Hash).Return(confirmation, nil)
+
+		result, err := srv.GetVotes(nil, &consensus.GetVotesRequest{Hash: sendTx.Hash})
+
+		Expect(err).To(BeNil())
+		Expect(result.Confirmation).To(Equal(confirmation))
+	})
+
 	It("Should call verify transactions from ledger", func() {
 		defer mockCtrl.Finish()
 
@@ -273,4 +589,620 @@ var _ = Describe("Server", func() {
 		Expect(result).To(BeNil())
 		Expect(err).To(Equal(someErr))
 	})
+
+	It("Should verify and forward published transactions", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().GetTransaction(receiveTx.Hash).Return(nil, nil)
+		con.EXPECT().Accept(gomock.Any()).Return(&consensus.AcceptResult{}, nil)
+
+		published := make(chan *consensus.PublishRequest, 1)
+		conCli.EXPECT().Publish(gomock.Any(), gomock.Any()).
+			Do(func(ctx interface{}, request *consensus.PublishRequest) { published <- request })
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{conCli}, nil)
+
+		request := &consensus.PublishRequest{SendTx: sendTx, ReceiveTx: receiveTx}
+		result, err := srv.Publish(nil, request)
+
+		Expect(err).To(BeNil())
+		Expect(result.Known).To(BeFalse())
+		Eventually(published).Should(Receive(Equal(request)))
+	})
+
+	It("Should forward published transactions already in the ledger without registering them again", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().GetTransaction(receiveTx.Hash).Return(receiveTx, nil)
+
+		published := make(chan *consensus.PublishRequest, 1)
+		conCli.EXPECT().Publish(gomock.Any(), gomock.Any()).
+			Do(func(ctx interface{}, request *consensus.PublishRequest) { published <- request })
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{conCli}, nil)
+
+		request := &consensus.PublishRequest{SendTx: sendTx, ReceiveTx: receiveTx}
+		result, err := srv.Publish(nil, request)
+
+		Expect(err).To(BeNil())
+		Expect(result.Known).To(BeFalse())
+		Eventually(published).Should(Receive(Equal(request)))
+	})
+
+	It("Should suppress duplicated published transactions", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().GetTransaction(receiveTx.Hash).Return(receiveTx, nil)
+
+		published := make(chan *consensus.PublishRequest, 1)
+		conCli.EXPECT().Publish(gomock.Any(), gomock.Any()).
+			Do(func(ctx interface{}, request *consensus.PublishRequest) { published <- request })
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{conCli}, nil)
+
+		request := &consensus.PublishRequest{SendTx: sendTx, ReceiveTx: receiveTx}
+		_, err := srv.Publish(nil, request)
+		Expect(err).To(BeNil())
+		Eventually(published).Should(Receive())
+
+		result, err := srv.Publish(nil, request)
+		Expect(err).To(BeNil())
+		Expect(result.Known).To(BeTrue())
+	})
+
+	It("Should not forward published transactions rejected by the ledger", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().GetTransaction(receiveTx.Hash).Return(nil, nil).Times(2)
+		con.EXPECT().Accept(gomock.Any()).Return(nil, ledger.ErrPreviousTransactionIsNotHead).Times(2)
+
+		request := &consensus.PublishRequest{SendTx: sendTx, ReceiveTx: receiveTx}
+		result, err := srv.Publish(nil, request)
+
+		Expect(result).To(BeNil())
+		Expect(err).To(Equal(ledger.ErrPreviousTransactionIsNotHead))
+
+		result, err = srv.Publish(nil, request)
+
+		Expect(result).To(BeNil())
+		Expect(err).To(Equal(ledger.ErrPreviousTransactionIsNotHead))
+	})
+
+	It("Should return error if published request is incomplete", func() {
+		defer mockCtrl.Finish()
+
+		result, err := srv.Publish(nil, &consensus.PublishRequest{SendTx: sendTx})
+
+		Expect(result).To(BeNil())
+		Expect(err).To(Equal(server.ErrInvalidPublishRequest))
+	})
+
+	It("Should return the ledger frontiers", func() {
+		defer mockCtrl.Finish()
+
+		frontiers := map[string]string{sendTx.Address: sendTx.Hash, receiveTx.Address: receiveTx.Hash}
+		ld.EXPECT().GetFrontiers().Return(frontiers, nil)
+
+		result, err := srv.GetFrontiers(nil, &consensus.GetFrontiersRequest{})
+
+		Expect(err).To(BeNil())
+		Expect(result.Frontiers).To(Equal(frontiers))
+	})
+
+	It("Should return only the frontiers that differ from the given ones", func() {
+		defer mockCtrl.Finish()
+
+		frontiers := map[string]string{sendTx.Address: sendTx.Hash, receiveTx.Address: receiveTx.Hash}
+		ld.EXPECT().GetFrontiers().Return(frontiers, nil)
+
+		request := &consensus.GetFrontiersRequest{Frontiers: map[string]string{sendTx.Address: sendTx.Hash,
+			receiveTx.Address: sendTx.Hash}}
+		result, err := srv.GetFrontiers(nil, request)
+
+		Expect(err).To(BeNil())
+		Expect(result.Frontiers).To(Equal(map[string]string{receiveTx.Address: receiveTx.Hash}))
+	})
+
+	It("Should return the address chain after the given transaction", func() {
+		defer mockCtrl.Finish()
+
+		chain := []*ledger.Transaction{receiveTx, sendTx}
+		ld.EXPECT().GetAddressStatementPage("xxxxxx", ledger.StatementQuery{Limit: 100}).Return(chain, "", nil)
+		ld.EXPECT().GetAddressStatementPage("xxxxxx", ledger.StatementQuery{StartHash: receiveTx.Hash, Limit: 101}).
+			Return(chain, "", nil)
+
+		result, err := srv.GetChain(nil, &consensus.GetChainRequest{Address: "xxxxxx"})
+		Expect(err).To(BeNil())
+		Expect(result.Txs).To(Equal(chain))
+		Expect(result.NextHash).To(BeEmpty())
+
+		result, err = srv.GetChain(nil, &consensus.GetChainRequest{Address: "xxxxxx", After: receiveTx.Hash})
+		Expect(err).To(BeNil())
+		Expect(result.Txs).To(Equal(chain[1:]))
+		Expect(result.NextHash).To(BeEmpty())
+	})
+
+	It("Should return the address chain a page at a time", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().GetAddressStatementPage("xxxxxx", ledger.StatementQuery{Limit: 1}).
+			Return([]*ledger.Transaction{receiveTx}, sendTx.Hash, nil)
+		ld.EXPECT().GetAddressStatementPage("xxxxxx", ledger.StatementQuery{StartHash: receiveTx.Hash, Limit: 1001}).
+			Return([]*ledger.Transaction{receiveTx, sendTx}, "", nil)
+
+		result, err := srv.GetChain(nil, &consensus.GetChainRequest{Address: "xxxxxx", Limit: 1})
+		Expect(err).To(BeNil())
+		Expect(result.Txs).To(Equal([]*ledger.Transaction{receiveTx}))
+		Expect(result.NextHash).To(Equal(receiveTx.Hash))
+
+		result, err = srv.GetChain(nil, &consensus.GetChainRequest{Address: "xxxxxx", After: result.NextHash,
+			Limit: 5000})
+		Expect(err).To(BeNil())
+		Expect(result.Txs).To(Equal([]*ledger.Transaction{sendTx}))
+		Expect(result.NextHash).To(BeEmpty())
+	})
+
+	It("Should return error if the given transaction is not in the address chain", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().GetAddressStatementPage("xxxxxx", ledger.StatementQuery{StartHash: sendTx.Hash, Limit: 101}).
+			Return(nil, "", ledger.ErrStatementStartNotInChain)
+
+		result, err := srv.GetChain(nil, &consensus.GetChainRequest{Address: "xxxxxx", After: sendTx.Hash})
+		Expect(result).To(BeNil())
+		Expect(err).To(Equal(server.ErrTransactionNotInChain))
+	})
+
+	It("Should return the statement page of the address", func() {
+		defer mockCtrl.Finish()
+
+		query := ledger.StatementQuery{Direction: ledger.Direction_BACKWARD, Type: ledger.Transaction_SEND, Limit: 100}
+		ld.EXPECT().GetAddressStatementPage("xxxxxx", query).Return([]*ledger.Transaction{sendTx}, receiveTx.Hash, nil)
+		query.Limit = 1000
+		ld.EXPECT().GetAddressStatementPage("xxxxxx", query).Return([]*ledger.Transaction{sendTx}, "", nil)
+
+		request := &ledger.GetAddressStatementRequest{Address: "xxxxxx", Direction: ledger.Direction_BACKWARD,
+			Type: ledger.Transaction_SEND}
+		result, err := srv.GetAddressStatement(nil, request)
+		Expect(err).To(BeNil())
+		Expect(result.Txs).To(Equal([]*ledger.Transaction{sendTx}))
+		Expect(result.NextHash).To(Equal(receiveTx.Hash))
+
+		request.Limit = 5000
+		result, err = srv.GetAddressStatement(nil, request)
+		Expect(err).To(BeNil())
+		Expect(result.NextHash).To(BeEmpty())
+	})
+
+	It("Should stream the whole statement of the address page by page", func() {
+		defer mockCtrl.Finish()
+
+		gomock.InOrder(
+			ld.EXPECT().GetAddressStatementPage("xxxxxx", ledger.StatementQuery{Limit: 1000}).
+				Return([]*ledger.Transaction{sendTx}, receiveTx.Hash, nil),
+			ld.EXPECT().GetAddressStatementPage("xxxxxx", ledger.StatementQuery{StartHash: receiveTx.Hash, Limit: 1000}).
+				Return([]*ledger.Transaction{receiveTx}, "", nil),
+		)
+
+		stream := &statementStream{ctx: context.Background()}
+		err := srv.StreamAddressStatement(&ledger.GetAddressStatementRequest{Address: "xxxxxx"}, stream)
+		Expect(err).To(BeNil())
+		Expect(stream.txs).To(Equal([]*ledger.Transaction{sendTx, receiveTx}))
+	})
+
+	It("Should return the balance of the address at a time or height", func() {
+		defer mockCtrl.Finish()
+
+		balance := &ledger.Balance{Timestamp: 100, Height: 2, Hash: receiveTx.Hash, Balance: 300}
+		ld.EXPECT().GetBalanceAt("xxxxxx", int64(100), int64(0)).Return(balance, nil)
+
+		result, err := srv.GetBalanceAt(nil, &ledger.GetBalanceAtRequest{Address: "xxxxxx", Timestamp: 100})
+		Expect(err).To(BeNil())
+		Expect(result.Balance).To(Equal(balance))
+	})
+
+	It("Should return the balance history of the address up to now by default", func() {
+		defer mockCtrl.Finish()
+
+		balances := []*ledger.Balance{{Timestamp: 200, Balance: 300}}
+		ld.EXPECT().GetBalanceHistory("xxxxxx", int64(100), int64(200), int64(100)).Return(balances, nil)
+		ld.EXPECT().GetBalanceHistory("xxxxxx", int64(100), gomock.Any(), int64(100)).
+			DoAndReturn(func(address string, from, to, bucket int64) ([]*ledger.Balance, error) {
+				Expect(to).To(BeNumerically("~", time.Now().UnixNano(), int64(time.Minute)))
+				return balances, nil
+			})
+
+		request := &ledger.GetBalanceHistoryRequest{Address: "xxxxxx", FromTimestamp: 100, ToTimestamp: 200, Bucket: 100}
+		result, err := srv.GetBalanceHistory(nil, request)
+		Expect(err).To(BeNil())
+		Expect(result.Balances).To(Equal(balances))
+
+		request.ToTimestamp = 0
+		result, err = srv.GetBalanceHistory(nil, request)
+		Expect(err).To(BeNil())
+		Expect(result.Balances).To(Equal(balances))
+	})
+
+	It("Should return the transfers to the address", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().GetIncoming(receiveTx.Address, true).Return([]*ledger.Transaction{sendTx}, nil)
+		ld.EXPECT().GetReceiveForSend(sendTx.Hash).Return(receiveTx, nil)
+
+		incoming, err := srv.GetIncoming(nil, &ledger.GetIncomingRequest{Address: receiveTx.Address, Pending: true})
+		Expect(err).To(BeNil())
+		Expect(incoming.Txs).To(Equal([]*ledger.Transaction{sendTx}))
+
+		receive, err := srv.GetReceiveForSend(nil, &ledger.GetReceiveForSendRequest{Hash: sendTx.Hash})
+		Expect(err).To(BeNil())
+		Expect(receive.Tx).To(Equal(receiveTx))
+	})
+
+	It("Should stop streaming the statement at the limit", func() {
+		defer mockCtrl.Finish()
+
+		ld.EXPECT().GetAddressStatementPage("xxxxxx", ledger.StatementQuery{Limit: 1}).
+			Return([]*ledger.Transaction{sendTx}, receiveTx.Hash, nil)
+
+		stream := &statementStream{ctx: context.Background()}
+		err := srv.StreamAddressStatement(&ledger.GetAddressStatementRequest{Address: "xxxxxx", Limit: 1}, stream)
+		Expect(err).To(BeNil())
+		Expect(stream.txs).To(Equal([]*ledger.Transaction{sendTx}))
+	})
+	It("Should add the requesting peer that ran a handshake and return the known peers", func() {
+		defer mockCtrl.Finish()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		srv = server.New(ld, events, con, dis, listener)
+		go srv.Run()
+		defer srv.Stop(context.Background())
+
+		conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+		Expect(err).To(BeNil())
+		defer conn.Close()
+		client := consensus.NewConsensusClient(conn)
+
+		known := []*consensus.Peer{{Address: "127.0.0.1:1000", LastSeen: 10}}
+		dis.EXPECT().KnownPeers().Return(known, nil).Times(2)
+		result, err := client.GetPeers(context.Background(), &consensus.GetPeersRequest{From: "127.0.0.1:2000"})
+		Expect(err).To(BeNil())
+		Expect(len(result.Peers)).To(Equal(1))
+
+		con.EXPECT().Handshake(gomock.Any()).Return(&consensus.HandshakeResult{Session: "session"}, nil)
+		_, err = client.Handshake(context.Background(),
+			&consensus.HandshakeRequest{Info: &consensus.NodeInfo{PubKey: []byte("key")}})
+		Expect(err).To(BeNil())
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), consensus.SessionKey, "session")
+		dis.EXPECT().AddPeer("127.0.0.1:2000").Return(nil)
+		result, err = client.GetPeers(ctx, &consensus.GetPeersRequest{From: "127.0.0.1:2000"})
+		Expect(err).To(BeNil())
+		Expect(len(result.Peers)).To(Equal(1))
+		Expect(result.Peers[0].Address).To(Equal(known[0].Address))
+	})
+
+	It("Should return the known peers even if the requesting peer can not be added", func() {
+		defer mockCtrl.Finish()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		srv = server.New(ld, events, con, dis, listener)
+		go srv.Run()
+		defer srv.Stop(context.Background())
+
+		conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+		Expect(err).To(BeNil())
+		defer conn.Close()
+		client := consensus.NewConsensusClient(conn)
+
+		con.EXPECT().Handshake(gomock.Any()).Return(&consensus.HandshakeResult{Session: "session"}, nil)
+		_, err = client.Handshake(context.Background(),
+			&consensus.HandshakeRequest{Info: &consensus.NodeInfo{PubKey: []byte("key")}})
+		Expect(err).To(BeNil())
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), consensus.SessionKey, "session")
+		dis.EXPECT().AddPeer("127.0.0.1:2000").Return(errors.Error("some error"))
+		dis.EXPECT().KnownPeers().Return([]*consensus.Peer{}, nil)
+		result, err := client.GetPeers(ctx, &consensus.GetPeersRequest{From: "127.0.0.1:2000"})
+		Expect(err).To(BeNil())
+		Expect(result.Peers).To(BeEmpty())
+	})
+
+	It("Should wait for the in-flight voting rounds when stopping", func() {
+		defer mockCtrl.Finish()
+
+		voting := make(chan bool)
+		release := make(chan bool)
+		ld.EXPECT().Verify(sendTx, receiveTx)
+		ld.EXPECT().Register(sendTx, receiveTx)
+		con.EXPECT().Confirm(sendTx, receiveTx, gomock.Any())
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{conCli}, nil)
+		conCli.EXPECT().Vote(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx interface{}, request *consensus.VoteRequest) (*consensus.VoteResult, error) {
+				close(voting)
+				<-release
+				return &consensus.VoteResult{Vote: tests.CreatePeerVote(true, sendTx, receiveTx)}, nil
+			})
+		conCli.EXPECT().Accept(gomock.Any(), gomock.Any())
+		lis.EXPECT().Close()
+
+		registered := make(chan error, 1)
+		go func() {
+			_, err := srv.Register(nil, &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx})
+			registered <- err
+		}()
+		Eventually(voting).Should(BeClosed())
+
+		stopped := make(chan error, 1)
+		go func() { stopped <- srv.Stop(context.Background()) }()
+		Consistently(stopped, 100*time.Millisecond).ShouldNot(Receive())
+
+		close(release)
+		Eventually(registered).Should(Receive(BeNil()))
+		Eventually(stopped).Should(Receive(BeNil()))
+	})
+
+	It("Should cancel the voting rounds and wait for them when the stop context is done", func() {
+		defer mockCtrl.Finish()
+
+		voting := make(chan bool)
+		ld.EXPECT().Verify(sendTx, receiveTx)
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{conCli}, nil)
+		conCli.EXPECT().Vote(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, request *consensus.VoteRequest) (*consensus.VoteResult, error) {
+				close(voting)
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+		lis.EXPECT().Close()
+
+		registered := make(chan error, 1)
+		go func() {
+			_, err := srv.Register(nil, &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx})
+			registered <- err
+		}()
+		Eventually(voting).Should(BeClosed())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := srv.Stop(ctx)
+		Expect(err).To(Equal(context.DeadlineExceeded))
+		Expect(registered).To(Receive(Equal(context.Canceled)))
+	})
+
+	It("Should refuse registrations once stopped", func() {
+		defer mockCtrl.Finish()
+
+		lis.EXPECT().Close()
+		err := srv.Stop(context.Background())
+		Expect(err).To(BeNil())
+
+		result, err := srv.Register(nil, &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx})
+		Expect(result).To(BeNil())
+		Expect(err).To(Equal(server.ErrServerStopping))
+
+		err = srv.Run()
+		Expect(err).To(Equal(server.ErrServerStopping))
+	})
+
+	It("Should count the calls and the votes in the metrics", func() {
+		defer mockCtrl.Finish()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		srv = server.New(ld, events, con, dis, listener)
+		srv.SetChainId("test")
+		go srv.Run()
+		defer srv.Stop(context.Background())
+
+		conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+		Expect(err).To(BeNil())
+		defer conn.Close()
+		_, err = consensus.NewConsensusClient(conn).Ping(context.Background(), &consensus.PingRequest{Timestamp: 1})
+		Expect(err).To(BeNil())
+
+		ld.EXPECT().Verify(sendTx, receiveTx)
+		dis.EXPECT().Peers().Return([]consensus.ConsensusClient{conCli}, nil)
+		conCli.EXPECT().Vote(gomock.Any(), gomock.Any()).Return(&consensus.VoteResult{Vote: tests.CreatePeerVote(false, sendTx, receiveTx)}, nil)
+		_, err = srv.Register(nil, &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx})
+		Expect(err).To(Equal(server.ErrDeclinedByVoting))
+
+		buf := &bytes.Buffer{}
+		Expect(metrics.Default.Write(buf)).To(BeNil())
+		Expect(buf.String()).To(ContainSubstring(`realchain_grpc_requests_total{method="/Consensus/Ping",code="OK"}`))
+		Expect(buf.String()).To(ContainSubstring(`realchain_grpc_request_duration_seconds_count{method="/Consensus/Ping"}`))
+		Expect(buf.String()).To(ContainSubstring(`realchain_votes_total{outcome="reject"}`))
+		Expect(buf.String()).To(ContainSubstring(`realchain_voting_duration_seconds_count{result="declined"}`))
+	})
+
+	It("Should refuse the calls over the limits of the clients", func() {
+		defer mockCtrl.Finish()
+
+		cfg := viper.New()
+		cfg.Set(config.CfgLimitMethods+".ping.rate", 0.001)
+		cfg.Set(config.CfgLimitMethods+".ping.burst", 2)
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		srv = server.New(ld, events, con, dis, listener)
+		srv.SetLimiter(ratelimit.New(cfg))
+		go srv.Run()
+		defer srv.Stop(context.Background())
+
+		conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+		Expect(err).To(BeNil())
+		defer conn.Close()
+		client := consensus.NewConsensusClient(conn)
+		for i := 0; i < 2; i++ {
+			_, err = client.Ping(context.Background(), &consensus.PingRequest{Timestamp: 1})
+			Expect(err).To(BeNil())
+		}
+
+		_, err = client.Ping(context.Background(), &consensus.PingRequest{Timestamp: 1})
+		Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+		Expect(status.Convert(err).Message()).To(Equal(ratelimit.ErrRateLimited.Error()))
+	})
+
+	It("Should limit the calls of the authenticated clients by their address too", func() {
+		defer mockCtrl.Finish()
+
+		cfg := viper.New()
+		cfg.Set(config.CfgAuth, true)
+		cfg.Set(config.CfgAuthTokens+".reader", []string{"reader-token", "other-token"})
+		cfg.Set(config.CfgLimitMethods+".gettransaction.rate", 0.001)
+		cfg.Set(config.CfgLimitMethods+".gettransaction.burst", 2)
+		authorizer, err := auth.New(cfg)
+		Expect(err).To(BeNil())
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		srv = server.New(ld, events, con, dis, listener)
+		srv.SetAuthorizer(authorizer)
+		srv.SetLimiter(ratelimit.New(cfg))
+		go srv.Run()
+		defer srv.Stop(context.Background())
+
+		ld.EXPECT().GetTransaction(sendTx.Hash).Return(sendTx, nil).Times(2)
+		for _, token := range []string{"reader-token", "other-token"} {
+			cfg.Set(config.CfgAuthToken, token)
+			opts := append([]grpc.DialOption{grpc.WithInsecure()}, auth.DialOptions(cfg)...)
+			conn, err := grpc.Dial(listener.Addr().String(), opts...)
+			Expect(err).To(BeNil())
+			defer conn.Close()
+			client := ledger.NewLedgerClient(conn)
+
+			_, err = client.GetTransaction(context.Background(), &ledger.GetTransactionRequest{Hash: sendTx.Hash})
+			Expect(err).To(BeNil())
+		}
+
+		cfg.Set(config.CfgAuthToken, "reader-token")
+		opts := append([]grpc.DialOption{grpc.WithInsecure()}, auth.DialOptions(cfg)...)
+		conn, err := grpc.Dial(listener.Addr().String(), opts...)
+		Expect(err).To(BeNil())
+		defer conn.Close()
+		_, err = ledger.NewLedgerClient(conn).GetTransaction(context.Background(),
+			&ledger.GetTransactionRequest{Hash: sendTx.Hash})
+		Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+	})
+
+	It("Should refuse the calls whose API token may not call the method", func() {
+		defer mockCtrl.Finish()
+
+		cfg := viper.New()
+		cfg.Set(config.CfgAuth, true)
+		cfg.Set(config.CfgAuthTokens+".reader", []string{"reader-token"})
+		authorizer, err := auth.New(cfg)
+		Expect(err).To(BeNil())
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		srv = server.New(ld, events, con, dis, listener)
+		srv.SetAuthorizer(authorizer)
+		go srv.Run()
+		defer srv.Stop(context.Background())
+
+		conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+		Expect(err).To(BeNil())
+		defer conn.Close()
+		_, err = ledger.NewLedgerClient(conn).GetTransaction(context.Background(),
+			&ledger.GetTransactionRequest{Hash: sendTx.Hash})
+		Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+		_, err = consensus.NewConsensusClient(conn).Ping(context.Background(), &consensus.PingRequest{Timestamp: 1})
+		Expect(err).To(BeNil())
+
+		cfg.Set(config.CfgAuthToken, "reader-token")
+		opts := append([]grpc.DialOption{grpc.WithInsecure()}, auth.DialOptions(cfg)...)
+		readerConn, err := grpc.Dial(listener.Addr().String(), opts...)
+		Expect(err).To(BeNil())
+		defer readerConn.Close()
+		client := ledger.NewLedgerClient(readerConn)
+
+		ld.EXPECT().GetTransaction(sendTx.Hash).Return(sendTx, nil)
+		result, err := client.GetTransaction(context.Background(), &ledger.GetTransactionRequest{Hash: sendTx.Hash})
+		Expect(err).To(BeNil())
+		Expect(result.Tx.Hash).To(Equal(sendTx.Hash))
+
+		_, err = client.Register(context.Background(), &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	})
+
+	It("Should serve the admin service on the admin and local listeners only", func() {
+		defer mockCtrl.Finish()
+
+		dir, err := ioutil.TempDir("", "server")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(dir)
+		socket := filepath.Join(dir, "node.sock")
+
+		cfg := viper.New()
+		cfg.Set(config.CfgAuth, true)
+		cfg.Set(config.CfgAuthTokens+".admin", []string{"admin-token"})
+		authorizer, err := auth.New(cfg)
+		Expect(err).To(BeNil())
+		publicLis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		adminLis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		localLis, err := net.Listen("unix", socket)
+		Expect(err).To(BeNil())
+
+		srv = server.New(ld, events, con, dis, publicLis)
+		srv.SetAdminListener(adminLis)
+		srv.SetLocalListener(localLis)
+		srv.SetAdmin(server.NewAdmin(srv, "node-address", nil, nil, viper.New()))
+		srv.SetAdminHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("metrics"))
+		}), nil)
+		srv.SetAuthorizer(authorizer)
+		go srv.Run()
+		defer srv.Stop(context.Background())
+
+		ld.EXPECT().GetStats().Return(&ledger.Stats{}, nil).Times(2)
+		cfg.Set(config.CfgAuthToken, "admin-token")
+		withToken := append([]grpc.DialOption{grpc.WithInsecure()}, auth.DialOptions(cfg)...)
+
+		publicConn, err := grpc.Dial(publicLis.Addr().String(), withToken...)
+		Expect(err).To(BeNil())
+		defer publicConn.Close()
+		_, err = admin.NewAdminClient(publicConn).Status(context.Background(), &admin.StatusRequest{})
+		Expect(status.Code(err)).To(Equal(codes.Unimplemented))
+
+		adminConn, err := grpc.Dial(adminLis.Addr().String(), withToken...)
+		Expect(err).To(BeNil())
+		defer adminConn.Close()
+		result, err := admin.NewAdminClient(adminConn).Status(context.Background(), &admin.StatusRequest{})
+		Expect(err).To(BeNil())
+		Expect(result.Address).To(Equal("node-address"))
+		_, err = consensus.NewConsensusClient(adminConn).Ping(context.Background(), &consensus.PingRequest{Timestamp: 1})
+		Expect(status.Code(err)).To(Equal(codes.Unimplemented))
+		response, err := http.Get("http://" + adminLis.Addr().String() + "/metrics")
+		Expect(err).To(BeNil())
+		body, err := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		Expect(err).To(BeNil())
+		Expect(string(body)).To(Equal("metrics"))
+
+		localConn, err := grpc.Dial(socket, grpc.WithInsecure(),
+			grpc.WithDialer(func(addr string, timeout time.Duration) (net.Conn, error) {
+				return net.DialTimeout("unix", addr, timeout)
+			}))
+		Expect(err).To(BeNil())
+		defer localConn.Close()
+		_, err = admin.NewAdminClient(localConn).Status(context.Background(), &admin.StatusRequest{})
+		Expect(err).To(BeNil())
+		ld.EXPECT().GetTransaction(sendTx.Hash).Return(sendTx, nil)
+		_, err = ledger.NewLedgerClient(localConn).GetTransaction(context.Background(),
+			&ledger.GetTransactionRequest{Hash: sendTx.Hash})
+		Expect(err).To(BeNil())
+	})
 })
+
+type statementStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	txs []*ledger.Transaction
+}
+
+func (s *statementStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *statementStream) Send(tx *ledger.Transaction) error {
+	s.txs = append(s.txs, tx)
+	return nil
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/msaldanha/realChain/consensus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"sync"
+)
+
+// authenticatedMethods are the calls that must come from a peer that ran a handshake, so the votes and
+// gossip they carry are attributable to it and the chains are only read by peers.
+var authenticatedMethods = map[string]bool{
+	"/Consensus/Vote":         true,
+	"/Consensus/Accept":       true,
+	"/Consensus/Publish":      true,
+	"/Consensus/GetFrontiers": true,
+	"/Consensus/GetChain":     true,
+}
+
+type peerIdentityKey struct{}
+
+// sessions binds the sessions created by handshakes to the identity of the peers and to the connection the
+// handshake came through, so a session is not accepted from another connection. A peer has a single session, so
+// a new handshake replaces the previous one.
+type sessions struct {
+	mtx      sync.Mutex
+	byId     map[string]*session
+	byPubKey map[string]string
+}
+
+type session struct {
+	info *consensus.NodeInfo
+	conn string
+}
+
+func newSessions() *sessions {
+	return &sessions{byId: make(map[string]*session), byPubKey: make(map[string]string)}
+}
+
+// Add adds the session id of the peer info that ran a handshake through the connection of ctx.
+func (s *sessions) Add(ctx context.Context, id string, info *consensus.NodeInfo) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	pubKey := hex.EncodeToString(info.PubKey)
+	if old, ok := s.byPubKey[pubKey]; ok {
+		delete(s.byId, old)
+	}
+	s.byPubKey[pubKey] = id
+	s.byId[id] = &session{info: info, conn: connectionKey(ctx)}
+}
+
+// Get returns the identity of the peer of the session id if the call of ctx came through the connection of the
+// session, nil otherwise.
+func (s *sessions) Get(ctx context.Context, id string) *consensus.NodeInfo {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	session := s.byId[id]
+	if session == nil || session.conn != connectionKey(ctx) {
+		return nil
+	}
+	return session.info
+}
+
+// IsVoter tells if pubKey is the node key of a peer with a session.
+func (s *sessions) IsVoter(pubKey []byte) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	id, ok := s.byPubKey[hex.EncodeToString(pubKey)]
+	return ok && s.byId[id] != nil && bytes.Equal(s.byId[id].info.PubKey, pubKey)
+}
+
+// authenticate rejects the calls to the authenticated methods that do not carry a valid session and adds the
+// identity of the calling peer to the context of the calls that do, to any method.
+func (s *sessions) authenticate(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	var identity *consensus.NodeInfo
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(consensus.SessionKey); len(values) > 0 {
+		identity = s.Get(ctx, values[0])
+	}
+
+	if identity == nil {
+		if authenticatedMethods[info.FullMethod] {
+			return nil, ErrHandshakeRequired
+		}
+		return handler(ctx, req)
+	}
+	return handler(context.WithValue(ctx, peerIdentityKey{}, identity), req)
+}
+
+// PeerIdentity returns the identity of the peer that made the call, or nil if the call is not authenticated.
+func PeerIdentity(ctx context.Context) *consensus.NodeInfo {
+	if ctx == nil {
+		return nil
+	}
+	identity, _ := ctx.Value(peerIdentityKey{}).(*consensus.NodeInfo)
+	return identity
+}
+
+// connectionKey identifies the connection of the call of ctx: by the certificate of the peer, with TLS, or else
+// by the address of the peer, which is the one of its connection.
+func connectionKey(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+		sum := sha256.Sum256(tlsInfo.State.PeerCertificates[0].Raw)
+		return "cert:" + hex.EncodeToString(sum[:])
+	}
+	if p.Addr != nil {
+		return "addr:" + p.Addr.String()
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/logging"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+const subscriptionBuffer = 100
+
+// Subscribe streams the transactions that match the request filters as they are stored, and as they are
+// submitted for voting if pending transactions are requested. With resume set, the stored transactions after
+// the request cursor are sent first, so a client that reconnects gets the ones it missed. The stream ends with
+// ErrServerStopping when the server stops.
+func (s *Server) Subscribe(request *ledger.SubscribeRequest, stream ledger.Ledger_SubscribeServer) error {
+	sub, err := s.events.Subscribe(subscriptionBuffer)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	filter := newEventFilter(request)
+	if request.Resume {
+		err = s.events.Replay(request.Cursor, sub.Start, func(event *ledger.Event) error {
+			return filter.send(stream, event)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-s.done:
+			return ErrServerStopping
+		case event, ok := <-sub.Events():
+			if !ok {
+				return sub.Err()
+			}
+			err = filter.send(stream, event)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// publishPending notifies the subscribers of a transfer submitted for voting.
+func (s *Server) publishPending(ctx context.Context, request *ledger.RegisterRequest) {
+	for _, tx := range []*ledger.Transaction{request.SendTx, request.ReceiveTx} {
+		err := s.events.PublishPending(tx)
+		if err != nil {
+			logging.FromContext(ctx, s.logger).WithFields(log.Fields{logging.TxField: tx.Hash,
+				logging.AddressField: tx.Address}).Warnf("Failed to publish pending transaction: %s", err)
+		}
+	}
+}
+
+type eventFilter struct {
+	addresses map[string]bool
+	txType    ledger.Transaction_Type
+	state     ledger.ConfirmationState
+}
+
+func newEventFilter(request *ledger.SubscribeRequest) *eventFilter {
+	addresses := make(map[string]bool)
+	for _, addr := range request.Addresses {
+		addresses[addr] = true
+	}
+	return &eventFilter{addresses: addresses, txType: request.Type, state: request.State}
+}
+
+func (f *eventFilter) send(stream ledger.Ledger_SubscribeServer, event *ledger.Event) error {
+	state := ledger.ConfirmationState_PENDING
+	if event.Type == ledger.BlockStored {
+		state = ledger.ConfirmationState_CONFIRMED
+	}
+	if !f.matches(event.Tx, state) {
+		return nil
+	}
+	return stream.Send(&ledger.TransactionEvent{Cursor: event.Seq, State: state, Tx: event.Tx})
+}
+
+// matches tells if a transaction passes the filters. An address matches the transactions of its chain and the
+// transactions sent to it.
+func (f *eventFilter) matches(tx *ledger.Transaction, state ledger.ConfirmationState) bool {
+	if f.state != ledger.ConfirmationState_ANY_STATE && f.state != state {
+		return false
+	}
+	if f.txType != ledger.Transaction_ZERO && f.txType != tx.Type {
+		return false
+	}
+	if len(f.addresses) == 0 {
+		return true
+	}
+	return f.addresses[tx.Address] || (tx.Type == ledger.Transaction_SEND && f.addresses[tx.Link])
+}
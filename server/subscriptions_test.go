@@ -0,0 +1,155 @@
+package server_test
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/msaldanha/realChain/address"
+	"github.com/msaldanha/realChain/keyvaluestore"
+	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/server"
+	"github.com/msaldanha/realChain/tests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+var _ = Describe("Subscriptions", func() {
+
+	var events *ledger.EventBus
+	var srv *server.Server
+	var genesisTx *ledger.Transaction
+	var sendTx *ledger.Transaction
+	var receiveTx *ledger.Transaction
+
+	BeforeEach(func() {
+		events = ledger.NewEventBus(keyvaluestore.NewMemoryKeyValueStore())
+		srv = server.New(nil, events, nil, nil, nil)
+
+		var genesisAddr *address.Address
+		genesisTx, genesisAddr = tests.CreateGenesisTransaction(1000)
+
+		receiveAddr, err := address.NewAddressWithKeys()
+		Expect(err).To(BeNil())
+
+		sendTx, err = ledger.CreateSendTransaction(genesisTx, genesisAddr, receiveAddr.Address, 300)
+		Expect(err).To(BeNil())
+
+		receiveTx, err = ledger.CreateReceiveTransaction(sendTx, 300, receiveAddr, nil)
+		Expect(err).To(BeNil())
+	})
+
+	publish := func(txs ...*ledger.Transaction) {
+		for _, tx := range txs {
+			Expect(events.Publish(tx)).To(BeNil())
+		}
+	}
+
+	It("Should stream the transactions of the subscribed addresses", func() {
+		publish(genesisTx, sendTx)
+
+		stream := newSubscribeStream()
+		defer stream.cancel()
+		go srv.Subscribe(&ledger.SubscribeRequest{Addresses: []string{receiveTx.Address}, Resume: true}, stream)
+
+		var event *ledger.TransactionEvent
+		Eventually(stream.events).Should(Receive(&event))
+		Expect(event.Tx.Hash).To(Equal(sendTx.Hash))
+		Expect(event.Cursor).To(Equal(uint64(2)))
+		Expect(event.State).To(Equal(ledger.ConfirmationState_CONFIRMED))
+
+		publish(receiveTx)
+		Eventually(stream.events).Should(Receive(&event))
+		Expect(event.Tx.Hash).To(Equal(receiveTx.Hash))
+		Expect(event.Cursor).To(Equal(uint64(3)))
+		Consistently(stream.events).ShouldNot(Receive())
+	})
+
+	It("Should resume after the given cursor", func() {
+		publish(genesisTx, sendTx, receiveTx)
+
+		stream := newSubscribeStream()
+		defer stream.cancel()
+		go srv.Subscribe(&ledger.SubscribeRequest{Resume: true, Cursor: 2}, stream)
+
+		var event *ledger.TransactionEvent
+		Eventually(stream.events).Should(Receive(&event))
+		Expect(event.Tx.Hash).To(Equal(receiveTx.Hash))
+		Consistently(stream.events).ShouldNot(Receive())
+	})
+
+	It("Should filter the transactions by type", func() {
+		publish(genesisTx, sendTx, receiveTx)
+
+		stream := newSubscribeStream()
+		defer stream.cancel()
+		go srv.Subscribe(&ledger.SubscribeRequest{Type: ledger.Transaction_SEND, Resume: true}, stream)
+
+		var event *ledger.TransactionEvent
+		Eventually(stream.events).Should(Receive(&event))
+		Expect(event.Tx.Hash).To(Equal(sendTx.Hash))
+		Consistently(stream.events).ShouldNot(Receive())
+	})
+
+	It("Should filter the transactions by confirmation state", func() {
+		publish(genesisTx)
+
+		stream := newSubscribeStream()
+		defer stream.cancel()
+		go srv.Subscribe(&ledger.SubscribeRequest{State: ledger.ConfirmationState_PENDING, Resume: true}, stream)
+
+		var event *ledger.TransactionEvent
+		Eventually(func() chan *ledger.TransactionEvent {
+			Expect(events.PublishPending(sendTx)).To(BeNil())
+			return stream.events
+		}).Should(Receive(&event))
+		Expect(event.Tx.Hash).To(Equal(sendTx.Hash))
+		Expect(event.State).To(Equal(ledger.ConfirmationState_PENDING))
+		Expect(event.Cursor).To(Equal(uint64(1)))
+	})
+
+	It("Should end the stream when the client goes away", func() {
+		stream := newSubscribeStream()
+		done := make(chan error, 1)
+		go func() { done <- srv.Subscribe(&ledger.SubscribeRequest{}, stream) }()
+
+		stream.cancel()
+		Eventually(done).Should(Receive(Equal(context.Canceled)))
+	})
+
+	It("Should end the stream when the server stops", func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		defer mockCtrl.Finish()
+		lis := tests.NewMockListener(mockCtrl)
+		lis.EXPECT().Close()
+		srv = server.New(nil, events, nil, nil, lis)
+
+		stream := newSubscribeStream()
+		defer stream.cancel()
+		done := make(chan error, 1)
+		go func() { done <- srv.Subscribe(&ledger.SubscribeRequest{}, stream) }()
+
+		Expect(srv.Stop(context.Background())).To(BeNil())
+		Eventually(done).Should(Receive(Equal(server.ErrServerStopping)))
+	})
+})
+
+type subscribeStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	cancel context.CancelFunc
+	events chan *ledger.TransactionEvent
+}
+
+func newSubscribeStream() *subscribeStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &subscribeStream{ctx: ctx, cancel: cancel, events: make(chan *ledger.TransactionEvent, 10)}
+}
+
+func (s *subscribeStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *subscribeStream) Send(event *ledger.TransactionEvent) error {
+	s.events <- event
+	return nil
+}
@@ -7,6 +7,7 @@ package tests
 import (
 	gomock "github.com/golang/mock/gomock"
 	consensus "github.com/msaldanha/realChain/consensus"
+	ledger "github.com/msaldanha/realChain/ledger"
 	reflect "reflect"
 )
 
@@ -46,6 +47,44 @@ func (mr *MockConsensusMockRecorder) Accept(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accept", reflect.TypeOf((*MockConsensus)(nil).Accept), arg0)
 }
 
+// Confirm mocks base method
+func (m *MockConsensus) Confirm(arg0, arg1 *ledger.Transaction, arg2 []*consensus.Vote) error {
+	ret := m.ctrl.Call(m, "Confirm", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Confirm indicates an expected call of Confirm
+func (mr *MockConsensusMockRecorder) Confirm(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockConsensus)(nil).Confirm), arg0, arg1, arg2)
+}
+
+// GetVotes mocks base method
+func (m *MockConsensus) GetVotes(arg0 string) (*consensus.Confirmation, error) {
+	ret := m.ctrl.Call(m, "GetVotes", arg0)
+	ret0, _ := ret[0].(*consensus.Confirmation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVotes indicates an expected call of GetVotes
+func (mr *MockConsensusMockRecorder) GetVotes(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVotes", reflect.TypeOf((*MockConsensus)(nil).GetVotes), arg0)
+}
+
+// Handshake mocks base method
+func (m *MockConsensus) Handshake(arg0 *consensus.HandshakeRequest) (*consensus.HandshakeResult, error) {
+	ret := m.ctrl.Call(m, "Handshake", arg0)
+	ret0, _ := ret[0].(*consensus.HandshakeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Handshake indicates an expected call of Handshake
+func (mr *MockConsensusMockRecorder) Handshake(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Handshake", reflect.TypeOf((*MockConsensus)(nil).Handshake), arg0)
+}
+
 // Vote mocks base method
 func (m *MockConsensus) Vote(arg0 *consensus.VoteRequest) (*consensus.VoteResult, error) {
 	ret := m.ctrl.Call(m, "Vote", arg0)
@@ -53,6 +53,132 @@ func (mr *MockConsensusClientMockRecorder) Accept(arg0, arg1 interface{}, arg2 .
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accept", reflect.TypeOf((*MockConsensusClient)(nil).Accept), varargs...)
 }
 
+// GetChain mocks base method
+func (m *MockConsensusClient) GetChain(arg0 context.Context, arg1 *consensus.GetChainRequest, arg2 ...grpc.CallOption) (*consensus.GetChainResult, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetChain", varargs...)
+	ret0, _ := ret[0].(*consensus.GetChainResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChain indicates an expected call of GetChain
+func (mr *MockConsensusClientMockRecorder) GetChain(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChain", reflect.TypeOf((*MockConsensusClient)(nil).GetChain), varargs...)
+}
+
+// GetFrontiers mocks base method
+func (m *MockConsensusClient) GetFrontiers(arg0 context.Context, arg1 *consensus.GetFrontiersRequest, arg2 ...grpc.CallOption) (*consensus.GetFrontiersResult, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetFrontiers", varargs...)
+	ret0, _ := ret[0].(*consensus.GetFrontiersResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFrontiers indicates an expected call of GetFrontiers
+func (mr *MockConsensusClientMockRecorder) GetFrontiers(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFrontiers", reflect.TypeOf((*MockConsensusClient)(nil).GetFrontiers), varargs...)
+}
+
+// GetPeers mocks base method
+func (m *MockConsensusClient) GetPeers(arg0 context.Context, arg1 *consensus.GetPeersRequest, arg2 ...grpc.CallOption) (*consensus.GetPeersResult, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetPeers", varargs...)
+	ret0, _ := ret[0].(*consensus.GetPeersResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPeers indicates an expected call of GetPeers
+func (mr *MockConsensusClientMockRecorder) GetPeers(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeers", reflect.TypeOf((*MockConsensusClient)(nil).GetPeers), varargs...)
+}
+
+// GetVotes mocks base method
+func (m *MockConsensusClient) GetVotes(arg0 context.Context, arg1 *consensus.GetVotesRequest, arg2 ...grpc.CallOption) (*consensus.GetVotesResult, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetVotes", varargs...)
+	ret0, _ := ret[0].(*consensus.GetVotesResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVotes indicates an expected call of GetVotes
+func (mr *MockConsensusClientMockRecorder) GetVotes(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVotes", reflect.TypeOf((*MockConsensusClient)(nil).GetVotes), varargs...)
+}
+
+// Handshake mocks base method
+func (m *MockConsensusClient) Handshake(arg0 context.Context, arg1 *consensus.HandshakeRequest, arg2 ...grpc.CallOption) (*consensus.HandshakeResult, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Handshake", varargs...)
+	ret0, _ := ret[0].(*consensus.HandshakeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Handshake indicates an expected call of Handshake
+func (mr *MockConsensusClientMockRecorder) Handshake(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Handshake", reflect.TypeOf((*MockConsensusClient)(nil).Handshake), varargs...)
+}
+
+// Ping mocks base method
+func (m *MockConsensusClient) Ping(arg0 context.Context, arg1 *consensus.PingRequest, arg2 ...grpc.CallOption) (*consensus.PingResult, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Ping", varargs...)
+	ret0, _ := ret[0].(*consensus.PingResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Ping indicates an expected call of Ping
+func (mr *MockConsensusClientMockRecorder) Ping(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockConsensusClient)(nil).Ping), varargs...)
+}
+
+// Publish mocks base method
+func (m *MockConsensusClient) Publish(arg0 context.Context, arg1 *consensus.PublishRequest, arg2 ...grpc.CallOption) (*consensus.PublishResult, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Publish", varargs...)
+	ret0, _ := ret[0].(*consensus.PublishResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Publish indicates an expected call of Publish
+func (mr *MockConsensusClientMockRecorder) Publish(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockConsensusClient)(nil).Publish), varargs...)
+}
+
 // Vote mocks base method
 func (m *MockConsensusClient) Vote(arg0 context.Context, arg1 *consensus.VoteRequest, arg2 ...grpc.CallOption) (*consensus.VoteResult, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -33,6 +33,18 @@ func (m *MockDiscoverer) EXPECT() *MockDiscovererMockRecorder {
 	return m.recorder
 }
 
+// AddPeer mocks base method
+func (m *MockDiscoverer) AddPeer(arg0 string) error {
+	ret := m.ctrl.Call(m, "AddPeer", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPeer indicates an expected call of AddPeer
+func (mr *MockDiscovererMockRecorder) AddPeer(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPeer", reflect.TypeOf((*MockDiscoverer)(nil).AddPeer), arg0)
+}
+
 // Init mocks base method
 func (m *MockDiscoverer) Init() error {
 	ret := m.ctrl.Call(m, "Init")
@@ -45,6 +57,31 @@ func (mr *MockDiscovererMockRecorder) Init() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockDiscoverer)(nil).Init))
 }
 
+// KnownPeers mocks base method
+func (m *MockDiscoverer) KnownPeers() ([]*consensus.Peer, error) {
+	ret := m.ctrl.Call(m, "KnownPeers")
+	ret0, _ := ret[0].([]*consensus.Peer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KnownPeers indicates an expected call of KnownPeers
+func (mr *MockDiscovererMockRecorder) KnownPeers() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KnownPeers", reflect.TypeOf((*MockDiscoverer)(nil).KnownPeers))
+}
+
+// PeerIdentity mocks base method
+func (m *MockDiscoverer) PeerIdentity(arg0 consensus.ConsensusClient) *consensus.NodeInfo {
+	ret := m.ctrl.Call(m, "PeerIdentity", arg0)
+	ret0, _ := ret[0].(*consensus.NodeInfo)
+	return ret0
+}
+
+// PeerIdentity indicates an expected call of PeerIdentity
+func (mr *MockDiscovererMockRecorder) PeerIdentity(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PeerIdentity", reflect.TypeOf((*MockDiscoverer)(nil).PeerIdentity), arg0)
+}
+
 // Peers mocks base method
 func (m *MockDiscoverer) Peers() ([]consensus.ConsensusClient, error) {
 	ret := m.ctrl.Call(m, "Peers")
@@ -57,3 +94,13 @@ func (m *MockDiscoverer) Peers() ([]consensus.ConsensusClient, error) {
 func (mr *MockDiscovererMockRecorder) Peers() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Peers", reflect.TypeOf((*MockDiscoverer)(nil).Peers))
 }
+
+// Report mocks base method
+func (m *MockDiscoverer) Report(arg0 consensus.ConsensusClient, arg1 string) {
+	m.ctrl.Call(m, "Report", arg0, arg1)
+}
+
+// Report indicates an expected call of Report
+func (mr *MockDiscovererMockRecorder) Report(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockDiscoverer)(nil).Report), arg0, arg1)
+}
@@ -46,6 +46,72 @@ func (mr *MockLedgerMockRecorder) GetAddressStatement(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressStatement", reflect.TypeOf((*MockLedger)(nil).GetAddressStatement), arg0)
 }
 
+// GetAddressStatementPage mocks base method
+func (m *MockLedger) GetAddressStatementPage(arg0 string, arg1 ledger.StatementQuery) ([]*ledger.Transaction, string, error) {
+	ret := m.ctrl.Call(m, "GetAddressStatementPage", arg0, arg1)
+	ret0, _ := ret[0].([]*ledger.Transaction)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAddressStatementPage indicates an expected call of GetAddressStatementPage
+func (mr *MockLedgerMockRecorder) GetAddressStatementPage(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressStatementPage", reflect.TypeOf((*MockLedger)(nil).GetAddressStatementPage), arg0, arg1)
+}
+
+// GetBalanceAt mocks base method
+func (m *MockLedger) GetBalanceAt(arg0 string, arg1, arg2 int64) (*ledger.Balance, error) {
+	ret := m.ctrl.Call(m, "GetBalanceAt", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*ledger.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceAt indicates an expected call of GetBalanceAt
+func (mr *MockLedgerMockRecorder) GetBalanceAt(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceAt", reflect.TypeOf((*MockLedger)(nil).GetBalanceAt), arg0, arg1, arg2)
+}
+
+// GetBalanceHistory mocks base method
+func (m *MockLedger) GetBalanceHistory(arg0 string, arg1, arg2, arg3 int64) ([]*ledger.Balance, error) {
+	ret := m.ctrl.Call(m, "GetBalanceHistory", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*ledger.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceHistory indicates an expected call of GetBalanceHistory
+func (mr *MockLedgerMockRecorder) GetBalanceHistory(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceHistory", reflect.TypeOf((*MockLedger)(nil).GetBalanceHistory), arg0, arg1, arg2, arg3)
+}
+
+// GetFrontiers mocks base method
+func (m *MockLedger) GetFrontiers() (map[string]string, error) {
+	ret := m.ctrl.Call(m, "GetFrontiers")
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFrontiers indicates an expected call of GetFrontiers
+func (mr *MockLedgerMockRecorder) GetFrontiers() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFrontiers", reflect.TypeOf((*MockLedger)(nil).GetFrontiers))
+}
+
+// GetIncoming mocks base method
+func (m *MockLedger) GetIncoming(arg0 string, arg1 bool) ([]*ledger.Transaction, error) {
+	ret := m.ctrl.Call(m, "GetIncoming", arg0, arg1)
+	ret0, _ := ret[0].([]*ledger.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncoming indicates an expected call of GetIncoming
+func (mr *MockLedgerMockRecorder) GetIncoming(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncoming", reflect.TypeOf((*MockLedger)(nil).GetIncoming), arg0, arg1)
+}
+
 // GetLastTransaction mocks base method
 func (m *MockLedger) GetLastTransaction(arg0 string) (*ledger.Transaction, error) {
 	ret := m.ctrl.Call(m, "GetLastTransaction", arg0)
@@ -59,6 +125,32 @@ func (mr *MockLedgerMockRecorder) GetLastTransaction(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastTransaction", reflect.TypeOf((*MockLedger)(nil).GetLastTransaction), arg0)
 }
 
+// GetReceiveForSend mocks base method
+func (m *MockLedger) GetReceiveForSend(arg0 string) (*ledger.Transaction, error) {
+	ret := m.ctrl.Call(m, "GetReceiveForSend", arg0)
+	ret0, _ := ret[0].(*ledger.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReceiveForSend indicates an expected call of GetReceiveForSend
+func (mr *MockLedgerMockRecorder) GetReceiveForSend(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceiveForSend", reflect.TypeOf((*MockLedger)(nil).GetReceiveForSend), arg0)
+}
+
+// GetStats mocks base method
+func (m *MockLedger) GetStats() (*ledger.Stats, error) {
+	ret := m.ctrl.Call(m, "GetStats")
+	ret0, _ := ret[0].(*ledger.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats
+func (mr *MockLedgerMockRecorder) GetStats() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockLedger)(nil).GetStats))
+}
+
 // GetTransaction mocks base method
 func (m *MockLedger) GetTransaction(arg0 string) (*ledger.Transaction, error) {
 	ret := m.ctrl.Call(m, "GetTransaction", arg0)
@@ -53,6 +53,60 @@ func (mr *MockLedgerClientMockRecorder) GetAddressStatement(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressStatement", reflect.TypeOf((*MockLedgerClient)(nil).GetAddressStatement), varargs...)
 }
 
+// GetBalanceAt mocks base method
+func (m *MockLedgerClient) GetBalanceAt(arg0 context.Context, arg1 *ledger.GetBalanceAtRequest, arg2 ...grpc.CallOption) (*ledger.GetBalanceAtResult, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetBalanceAt", varargs...)
+	ret0, _ := ret[0].(*ledger.GetBalanceAtResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceAt indicates an expected call of GetBalanceAt
+func (mr *MockLedgerClientMockRecorder) GetBalanceAt(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceAt", reflect.TypeOf((*MockLedgerClient)(nil).GetBalanceAt), varargs...)
+}
+
+// GetBalanceHistory mocks base method
+func (m *MockLedgerClient) GetBalanceHistory(arg0 context.Context, arg1 *ledger.GetBalanceHistoryRequest, arg2 ...grpc.CallOption) (*ledger.GetBalanceHistoryResult, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetBalanceHistory", varargs...)
+	ret0, _ := ret[0].(*ledger.GetBalanceHistoryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceHistory indicates an expected call of GetBalanceHistory
+func (mr *MockLedgerClientMockRecorder) GetBalanceHistory(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceHistory", reflect.TypeOf((*MockLedgerClient)(nil).GetBalanceHistory), varargs...)
+}
+
+// GetIncoming mocks base method
+func (m *MockLedgerClient) GetIncoming(arg0 context.Context, arg1 *ledger.GetIncomingRequest, arg2 ...grpc.CallOption) (*ledger.GetIncomingResult, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetIncoming", varargs...)
+	ret0, _ := ret[0].(*ledger.GetIncomingResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncoming indicates an expected call of GetIncoming
+func (mr *MockLedgerClientMockRecorder) GetIncoming(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncoming", reflect.TypeOf((*MockLedgerClient)(nil).GetIncoming), varargs...)
+}
+
 // GetLastTransaction mocks base method
 func (m *MockLedgerClient) GetLastTransaction(arg0 context.Context, arg1 *ledger.GetLastTransactionRequest, arg2 ...grpc.CallOption) (*ledger.GetLastTransactionResult, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -71,6 +125,24 @@ func (mr *MockLedgerClientMockRecorder) GetLastTransaction(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastTransaction", reflect.TypeOf((*MockLedgerClient)(nil).GetLastTransaction), varargs...)
 }
 
+// GetReceiveForSend mocks base method
+func (m *MockLedgerClient) GetReceiveForSend(arg0 context.Context, arg1 *ledger.GetReceiveForSendRequest, arg2 ...grpc.CallOption) (*ledger.GetReceiveForSendResult, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetReceiveForSend", varargs...)
+	ret0, _ := ret[0].(*ledger.GetReceiveForSendResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReceiveForSend indicates an expected call of GetReceiveForSend
+func (mr *MockLedgerClientMockRecorder) GetReceiveForSend(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceiveForSend", reflect.TypeOf((*MockLedgerClient)(nil).GetReceiveForSend), varargs...)
+}
+
 // GetTransaction mocks base method
 func (m *MockLedgerClient) GetTransaction(arg0 context.Context, arg1 *ledger.GetTransactionRequest, arg2 ...grpc.CallOption) (*ledger.GetTransactionResult, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -107,6 +179,42 @@ func (mr *MockLedgerClientMockRecorder) Register(arg0, arg1 interface{}, arg2 ..
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockLedgerClient)(nil).Register), varargs...)
 }
 
+// StreamAddressStatement mocks base method
+func (m *MockLedgerClient) StreamAddressStatement(arg0 context.Context, arg1 *ledger.GetAddressStatementRequest, arg2 ...grpc.CallOption) (ledger.Ledger_StreamAddressStatementClient, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StreamAddressStatement", varargs...)
+	ret0, _ := ret[0].(ledger.Ledger_StreamAddressStatementClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamAddressStatement indicates an expected call of StreamAddressStatement
+func (mr *MockLedgerClientMockRecorder) StreamAddressStatement(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamAddressStatement", reflect.TypeOf((*MockLedgerClient)(nil).StreamAddressStatement), varargs...)
+}
+
+// Subscribe mocks base method
+func (m *MockLedgerClient) Subscribe(arg0 context.Context, arg1 *ledger.SubscribeRequest, arg2 ...grpc.CallOption) (ledger.Ledger_SubscribeClient, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Subscribe", varargs...)
+	ret0, _ := ret[0].(ledger.Ledger_SubscribeClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe
+func (mr *MockLedgerClientMockRecorder) Subscribe(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockLedgerClient)(nil).Subscribe), varargs...)
+}
+
 // Verify mocks base method
 func (m *MockLedgerClient) Verify(arg0 context.Context, arg1 *ledger.VerifyRequest, arg2 ...grpc.CallOption) (*ledger.VerifyResult, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -0,0 +1,189 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/msaldanha/realChain/ledger (interfaces: LedgerServer)
+
+// Package tests is a generated GoMock package.
+package tests
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	ledger "github.com/msaldanha/realChain/ledger"
+	reflect "reflect"
+)
+
+// MockLedgerServer is a mock of LedgerServer interface
+type MockLedgerServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerServerMockRecorder
+}
+
+// MockLedgerServerMockRecorder is the mock recorder for MockLedgerServer
+type MockLedgerServerMockRecorder struct {
+	mock *MockLedgerServer
+}
+
+// NewMockLedgerServer creates a new mock instance
+func NewMockLedgerServer(ctrl *gomock.Controller) *MockLedgerServer {
+	mock := &MockLedgerServer{ctrl: ctrl}
+	mock.recorder = &MockLedgerServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLedgerServer) EXPECT() *MockLedgerServerMockRecorder {
+	return m.recorder
+}
+
+// GetAddressStatement mocks base method
+func (m *MockLedgerServer) GetAddressStatement(arg0 context.Context, arg1 *ledger.GetAddressStatementRequest) (*ledger.GetAddressStatementResult, error) {
+	ret := m.ctrl.Call(m, "GetAddressStatement", arg0, arg1)
+	ret0, _ := ret[0].(*ledger.GetAddressStatementResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAddressStatement indicates an expected call of GetAddressStatement
+func (mr *MockLedgerServerMockRecorder) GetAddressStatement(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressStatement", reflect.TypeOf((*MockLedgerServer)(nil).GetAddressStatement), arg0, arg1)
+}
+
+// GetBalanceAt mocks base method
+func (m *MockLedgerServer) GetBalanceAt(arg0 context.Context, arg1 *ledger.GetBalanceAtRequest) (*ledger.GetBalanceAtResult, error) {
+	ret := m.ctrl.Call(m, "GetBalanceAt", arg0, arg1)
+	ret0, _ := ret[0].(*ledger.GetBalanceAtResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceAt indicates an expected call of GetBalanceAt
+func (mr *MockLedgerServerMockRecorder) GetBalanceAt(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceAt", reflect.TypeOf((*MockLedgerServer)(nil).GetBalanceAt), arg0, arg1)
+}
+
+// GetBalanceHistory mocks base method
+func (m *MockLedgerServer) GetBalanceHistory(arg0 context.Context, arg1 *ledger.GetBalanceHistoryRequest) (*ledger.GetBalanceHistoryResult, error) {
+	ret := m.ctrl.Call(m, "GetBalanceHistory", arg0, arg1)
+	ret0, _ := ret[0].(*ledger.GetBalanceHistoryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceHistory indicates an expected call of GetBalanceHistory
+func (mr *MockLedgerServerMockRecorder) GetBalanceHistory(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceHistory", reflect.TypeOf((*MockLedgerServer)(nil).GetBalanceHistory), arg0, arg1)
+}
+
+// GetIncoming mocks base method
+func (m *MockLedgerServer) GetIncoming(arg0 context.Context, arg1 *ledger.GetIncomingRequest) (*ledger.GetIncomingResult, error) {
+	ret := m.ctrl.Call(m, "GetIncoming", arg0, arg1)
+	ret0, _ := ret[0].(*ledger.GetIncomingResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncoming indicates an expected call of GetIncoming
+func (mr *MockLedgerServerMockRecorder) GetIncoming(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncoming", reflect.TypeOf((*MockLedgerServer)(nil).GetIncoming), arg0, arg1)
+}
+
+// GetLastTransaction mocks base method
+func (m *MockLedgerServer) GetLastTransaction(arg0 context.Context, arg1 *ledger.GetLastTransactionRequest) (*ledger.GetLastTransactionResult, error) {
+	ret := m.ctrl.Call(m, "GetLastTransaction", arg0, arg1)
+	ret0, _ := ret[0].(*ledger.GetLastTransactionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastTransaction indicates an expected call of GetLastTransaction
+func (mr *MockLedgerServerMockRecorder) GetLastTransaction(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastTransaction", reflect.TypeOf((*MockLedgerServer)(nil).GetLastTransaction), arg0, arg1)
+}
+
+// GetReceiveForSend mocks base method
+func (m *MockLedgerServer) GetReceiveForSend(arg0 context.Context, arg1 *ledger.GetReceiveForSendRequest) (*ledger.GetReceiveForSendResult, error) {
+	ret := m.ctrl.Call(m, "GetReceiveForSend", arg0, arg1)
+	ret0, _ := ret[0].(*ledger.GetReceiveForSendResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReceiveForSend indicates an expected call of GetReceiveForSend
+func (mr *MockLedgerServerMockRecorder) GetReceiveForSend(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceiveForSend", reflect.TypeOf((*MockLedgerServer)(nil).GetReceiveForSend), arg0, arg1)
+}
+
+// GetTransaction mocks base method
+func (m *MockLedgerServer) GetTransaction(arg0 context.Context, arg1 *ledger.GetTransactionRequest) (*ledger.GetTransactionResult, error) {
+	ret := m.ctrl.Call(m, "GetTransaction", arg0, arg1)
+	ret0, _ := ret[0].(*ledger.GetTransactionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransaction indicates an expected call of GetTransaction
+func (mr *MockLedgerServerMockRecorder) GetTransaction(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockLedgerServer)(nil).GetTransaction), arg0, arg1)
+}
+
+// Register mocks base method
+func (m *MockLedgerServer) Register(arg0 context.Context, arg1 *ledger.RegisterRequest) (*ledger.RegisterResult, error) {
+	ret := m.ctrl.Call(m, "Register", arg0, arg1)
+	ret0, _ := ret[0].(*ledger.RegisterResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Register indicates an expected call of Register
+func (mr *MockLedgerServerMockRecorder) Register(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockLedgerServer)(nil).Register), arg0, arg1)
+}
+
+// StreamAddressStatement mocks base method
+func (m *MockLedgerServer) StreamAddressStatement(arg0 *ledger.GetAddressStatementRequest, arg1 ledger.Ledger_StreamAddressStatementServer) error {
+	ret := m.ctrl.Call(m, "StreamAddressStatement", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamAddressStatement indicates an expected call of StreamAddressStatement
+func (mr *MockLedgerServerMockRecorder) StreamAddressStatement(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamAddressStatement", reflect.TypeOf((*MockLedgerServer)(nil).StreamAddressStatement), arg0, arg1)
+}
+
+// Subscribe mocks base method
+func (m *MockLedgerServer) Subscribe(arg0 *ledger.SubscribeRequest, arg1 ledger.Ledger_SubscribeServer) error {
+	ret := m.ctrl.Call(m, "Subscribe", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe
+func (mr *MockLedgerServerMockRecorder) Subscribe(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockLedgerServer)(nil).Subscribe), arg0, arg1)
+}
+
+// Verify mocks base method
+func (m *MockLedgerServer) Verify(arg0 context.Context, arg1 *ledger.VerifyRequest) (*ledger.VerifyResult, error) {
+	ret := m.ctrl.Call(m, "Verify", arg0, arg1)
+	ret0, _ := ret[0].(*ledger.VerifyResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify
+func (mr *MockLedgerServerMockRecorder) Verify(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockLedgerServer)(nil).Verify), arg0, arg1)
+}
+
+// VerifyTransaction mocks base method
+func (m *MockLedgerServer) VerifyTransaction(arg0 context.Context, arg1 *ledger.VerifyTransactionRequest) (*ledger.VerifyTransactionResult, error) {
+	ret := m.ctrl.Call(m, "VerifyTransaction", arg0, arg1)
+	ret0, _ := ret[0].(*ledger.VerifyTransactionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyTransaction indicates an expected call of VerifyTransaction
+func (mr *MockLedgerServerMockRecorder) VerifyTransaction(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTransaction", reflect.TypeOf((*MockLedgerServer)(nil).VerifyTransaction), arg0, arg1)
+}
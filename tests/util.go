@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"strings"
 	"github.com/msaldanha/realChain/address"
+	"github.com/msaldanha/realChain/consensus"
+	"github.com/msaldanha/realChain/keypair"
 )
 
 func AssertCommonVal(val ledger.Validator, tx *ledger.Transaction) {
@@ -111,4 +113,46 @@ func SendFunds(ld ledger.Ledger, sendAddr *address.Address, prevSendTx *ledger.T
 	Expect(err).To(BeNil())
 
 	return sendTx, receiveTx
-}
\ No newline at end of file
+}
+
+// PeerKeys are the node keys of the peer the votes created by CreatePeerVote come from.
+var PeerKeys = newKeys()
+
+// PeerIdentity is the identity the peer with PeerKeys proves in its handshake.
+var PeerIdentity = &consensus.NodeInfo{PubKey: PeerKeys.PublicKey, ChainId: "test"}
+
+// CreateVote creates a vote of a new node on the transfer of sendTx and receiveTx in the chain "test".
+func CreateVote(ok bool, sendTx, receiveTx *ledger.Transaction) *consensus.Vote {
+	keys, err := keypair.New()
+	Expect(err).To(BeNil())
+	return createVote(keys, ok, sendTx, receiveTx)
+}
+
+// CreatePeerVote creates a vote of the node with PeerKeys on the transfer of sendTx and receiveTx in the chain
+// "test".
+func CreatePeerVote(ok bool, sendTx, receiveTx *ledger.Transaction) *consensus.Vote {
+	return createVote(PeerKeys, ok, sendTx, receiveTx)
+}
+
+func createVote(keys *keypair.KeyPair, ok bool, sendTx, receiveTx *ledger.Transaction) *consensus.Vote {
+	vote := &consensus.Vote{Ok: ok, PubKey: keys.PublicKey, ChainId: "test", SendHash: sendTx.Hash,
+		ReceiveHash: receiveTx.Hash}
+	err := vote.Sign(keys.ToEcdsaPrivateKey())
+	Expect(err).To(BeNil())
+	return vote
+}
+
+func newKeys() *keypair.KeyPair {
+	keys, err := keypair.New()
+	if err != nil {
+		panic(err)
+	}
+	return keys
+}
+
+// VotersFunc tells whether a node key is a voter by calling the function.
+type VotersFunc func(pubKey []byte) bool
+
+func (f VotersFunc) IsVoter(pubKey []byte) bool {
+	return f(pubKey)
+}
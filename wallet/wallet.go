@@ -6,22 +6,40 @@ import (
 	"github.com/msaldanha/realChain/errors"
 	"github.com/msaldanha/realChain/keyvaluestore"
 	"github.com/msaldanha/realChain/ledger"
+	"github.com/msaldanha/realChain/logging"
+	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"time"
 )
 
 const (
 	ErrAddressNotManagedByThisWallet = errors.Error("address not managed by this wallet")
 )
 
+const (
+	watchRetryDelay    = time.Second
+	watchMaxRetryDelay = 30 * time.Second
+)
+
 type Wallet struct {
 	ld        ledger.LedgerClient
 	addresses keyvaluestore.Storer
 	ctx       context.Context
 	opts      grpc.CallOption
+	logger    *log.Entry
 }
 
 func New(addressStore keyvaluestore.Storer, ld ledger.LedgerClient) *Wallet {
-	return &Wallet{ld: ld, addresses: addressStore, ctx: context.Background(), opts: &grpc.EmptyCallOption{}}
+	return &Wallet{ld: ld, addresses: addressStore, ctx: context.Background(), opts: &grpc.EmptyCallOption{},
+		logger: logging.Component(log.StandardLogger(), "wallet")}
+}
+
+// SetLogger sets the logger of the wallet.
+func (wa *Wallet) SetLogger(logger log.FieldLogger) {
+	wa.logger = logging.Component(logger, "wallet")
 }
 
 func (wa *Wallet) Transfer(from, to string, amount float64) (*ledger.Transaction, error) {
@@ -66,23 +84,106 @@ func (wa *Wallet) Transfer(from, to string, amount float64) (*ledger.Transaction
 		return nil, err
 	}
 
-	_, err = wa.ld.Register(wa.ctx, &ledger.RegisterRequest{SendTx: sendTx, ReceiveTx: receiveTx}, wa.opts)
+	// The request ID is logged by the node too, so the transfer can be followed there.
+	ctx := logging.WithRequestId(wa.ctx, logging.NewRequestId())
+	logger := logging.FromContext(ctx, wa.logger).WithFields(log.Fields{logging.SendTxField: sendTx.Hash,
+		logging.TxField: receiveTx.Hash, logging.AddressField: from})
+	logger.Debugf("Registering transfer of %f to %s", amount, to)
+	_, err = wa.ld.Register(logging.AppendToOutgoingContext(ctx), &ledger.RegisterRequest{SendTx: sendTx,
+		ReceiveTx: receiveTx}, wa.opts)
 	if err != nil {
+		logger.Debugf("Transfer failed: %s", err)
 		return nil, err
 	}
 
 	return receiveTx, nil
 }
 
+// GetAddressStatement returns the whole statement of addr, reading it page by page.
 func (wa *Wallet) GetAddressStatement(addr string) ([]*ledger.Transaction, error) {
-	result, err := wa.ld.GetAddressStatement(wa.ctx, &ledger.GetAddressStatementRequest{Address: addr}, wa.opts)
+	txs := make([]*ledger.Transaction, 0)
+	request := &ledger.GetAddressStatementRequest{Address: addr}
+	for {
+		result, err := wa.GetStatementPage(request)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, result.Txs...)
+		if result.NextHash == "" {
+			return txs, nil
+		}
+		request.StartHash = result.NextHash
+	}
+}
+
+// GetStatementPage returns the page of the statement selected by request and the hash the next page starts at.
+func (wa *Wallet) GetStatementPage(request *ledger.GetAddressStatementRequest) (*ledger.GetAddressStatementResult, error) {
+	return wa.ld.GetAddressStatement(wa.ctx, request, wa.opts)
+}
+
+// ExportStatement calls fn with the transactions of the statement selected by request, streamed by the node.
+func (wa *Wallet) ExportStatement(ctx context.Context, request *ledger.GetAddressStatementRequest,
+	fn func(*ledger.Transaction) error) error {
+	stream, err := wa.ld.StreamAddressStatement(ctx, request, wa.opts)
+	if err != nil {
+		return err
+	}
+	for {
+		tx, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(tx); err != nil {
+			return err
+		}
+	}
+}
+
+// GetBalanceAt returns the balance of addr after the transaction at height or, if height is 0, after the
+// transactions older than timestamp. Without both, it returns the current balance.
+func (wa *Wallet) GetBalanceAt(addr string, timestamp, height int64) (*ledger.Balance, error) {
+	request := &ledger.GetBalanceAtRequest{Address: addr, Timestamp: timestamp, Height: height}
+	result, err := wa.ld.GetBalanceAt(wa.ctx, request, wa.opts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Balance, nil
+}
+
+// GetBalanceHistory returns the balances of addr at the end of every bucket from the timestamp from up to to, or
+// up to now if to is 0.
+func (wa *Wallet) GetBalanceHistory(addr string, from, to int64, bucket time.Duration) ([]*ledger.Balance, error) {
+	request := &ledger.GetBalanceHistoryRequest{Address: addr, FromTimestamp: from, ToTimestamp: to,
+		Bucket: int64(bucket)}
+	result, err := wa.ld.GetBalanceHistory(wa.ctx, request, wa.opts)
 	if err != nil {
 		return nil, err
 	}
+	return result.Balances, nil
+}
 
+// GetIncoming returns the send transactions to addr, only the ones not received yet if pending is set.
+func (wa *Wallet) GetIncoming(addr string, pending bool) ([]*ledger.Transaction, error) {
+	result, err := wa.ld.GetIncoming(wa.ctx, &ledger.GetIncomingRequest{Address: addr, Pending: pending}, wa.opts)
+	if err != nil {
+		return nil, err
+	}
 	return result.Txs, nil
 }
 
+// GetReceiveForSend returns the transaction that received the send transaction hash, or nil if it was not
+// received.
+func (wa *Wallet) GetReceiveForSend(hash string) (*ledger.Transaction, error) {
+	result, err := wa.ld.GetReceiveForSend(wa.ctx, &ledger.GetReceiveForSendRequest{Hash: hash}, wa.opts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Tx, nil
+}
+
 func (wa *Wallet) GetLastTransaction(addr string) (*ledger.Transaction, error) {
 	result, err := wa.ld.GetLastTransaction(wa.ctx, &ledger.GetLastTransactionRequest{Address: addr}, wa.opts)
 	if err != nil {
@@ -91,6 +192,59 @@ func (wa *Wallet) GetLastTransaction(addr string) (*ledger.Transaction, error) {
 	return result.Tx, nil
 }
 
+// Watch calls fn with the transactions that match request as the node receives them. If the stream breaks, because
+// the node is unavailable or reset the stream, it subscribes again from the last transaction received, so none is
+// missed, waiting longer after each failed attempt. It returns when ctx is done, fn fails or the node refuses the
+// subscription for any other reason, such as a missing permission or an invalid request.
+func (wa *Wallet) Watch(ctx context.Context, request *ledger.SubscribeRequest, fn func(*ledger.TransactionEvent) error) error {
+	request = &ledger.SubscribeRequest{Addresses: request.Addresses, Type: request.Type, State: request.State,
+		Resume: request.Resume, Cursor: request.Cursor}
+	delay := watchRetryDelay
+	for {
+		stream, err := wa.ld.Subscribe(ctx, request, wa.opts)
+		for err == nil {
+			var event *ledger.TransactionEvent
+			event, err = stream.Recv()
+			if err != nil {
+				break
+			}
+			request.Resume = true
+			request.Cursor = event.Cursor
+			delay = watchRetryDelay
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !isWatchRetryable(err) {
+			return err
+		}
+		wa.logger.Warnf("Subscription broken, subscribing again after cursor %d in %s: %s", request.Cursor, delay,
+			err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > watchMaxRetryDelay {
+			delay = watchMaxRetryDelay
+		}
+	}
+}
+
+// isWatchRetryable tells if err broke the subscription in a way that subscribing again may fix: the node is
+// unavailable or ended the stream.
+func isWatchRetryable(err error) bool {
+	if err == io.EOF {
+		return true
+	}
+	return status.Code(err) == codes.Unavailable
+}
+
 func (wa *Wallet) GetAddresses() ([]*address.Address, error) {
 	addrs, err := wa.addresses.GetAll()
 	if err != nil {
@@ -109,7 +263,12 @@ func (wa *Wallet) CreateAddress() (*address.Address, error) {
 		return nil, err
 	}
 
-	err = wa.addresses.Put(addr.Address, addr.ToBytes())
+	addrBytes, err := addr.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = wa.addresses.Put(addr.Address, addrBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package wallet_test
 
 import (
+	"context"
 	"encoding/hex"
 	"github.com/msaldanha/realChain/tests"
 	. "github.com/onsi/ginkgo"
@@ -10,6 +11,9 @@ import (
 	"github.com/msaldanha/realChain/address"
 	"github.com/msaldanha/realChain/wallet"
 	"github.com/msaldanha/realChain/ledger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var ts *ledger.TransactionStore
@@ -110,8 +114,59 @@ var _ = Describe("Wallet", func() {
 		Expect(err).To(BeNil())
 		Expect(tx).To(Equal(firstTx))
 	})
+
+	It("Should subscribe again from the last event when the node is unavailable", func() {
+		defer mockCtrl.Finish()
+
+		event := &ledger.TransactionEvent{Tx: firstTx, Cursor: 7}
+		unavailable := status.Error(codes.Unavailable, "transport is closing")
+		denied := status.Error(codes.PermissionDenied, "permission denied")
+		gomock.InOrder(
+			ld.EXPECT().Subscribe(gomock.Any(), &ledger.SubscribeRequest{}, gomock.Any()).
+				Return(&eventStream{events: []*ledger.TransactionEvent{event}, err: unavailable}, nil),
+			ld.EXPECT().Subscribe(gomock.Any(), &ledger.SubscribeRequest{Resume: true, Cursor: 7}, gomock.Any()).
+				Return(nil, denied),
+		)
+
+		received := make([]*ledger.TransactionEvent, 0)
+		err := wa.Watch(context.Background(), &ledger.SubscribeRequest{}, func(event *ledger.TransactionEvent) error {
+			received = append(received, event)
+			return nil
+		})
+		Expect(err).To(Equal(denied))
+		Expect(received).To(Equal([]*ledger.TransactionEvent{event}))
+	})
+
+	It("Should NOT subscribe again when the node refuses the subscription", func() {
+		defer mockCtrl.Finish()
+
+		for _, code := range []codes.Code{codes.Unauthenticated, codes.PermissionDenied, codes.InvalidArgument} {
+			refused := status.Error(code, "refused")
+			ld.EXPECT().Subscribe(gomock.Any(), gomock.Any(), gomock.Any()).Return(&eventStream{err: refused}, nil)
+
+			err := wa.Watch(context.Background(), &ledger.SubscribeRequest{},
+				func(event *ledger.TransactionEvent) error { return nil })
+			Expect(err).To(Equal(refused))
+		}
+	})
 })
 
+// eventStream is a subscription stream that returns its events, then err.
+type eventStream struct {
+	grpc.ClientStream
+	events []*ledger.TransactionEvent
+	err    error
+}
+
+func (s *eventStream) Recv() (*ledger.TransactionEvent, error) {
+	if len(s.events) == 0 {
+		return nil, s.err
+	}
+	event := s.events[0]
+	s.events = s.events[1:]
+	return event, nil
+}
+
 func createFirstTx() (*ledger.Transaction, *address.Address) {
 	tx := ledger.NewOpenTransaction()
 	addr, _ := address.NewAddressWithKeys()
@@ -131,7 +186,8 @@ func createWallet(ld ledger.LedgerClient) (*wallet.Wallet, *ledger.Transaction,
 
 	firstTx, addr := createFirstTx()
 
-	as.Put(addr.Address, addr.ToBytes())
+	addrBytes, _ := addr.ToBytes()
+	as.Put(addr.Address, addrBytes)
 	ts.Store(firstTx)
 
 	return wallet.New(as, ld), firstTx, addr